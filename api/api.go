@@ -3,32 +3,92 @@ package api
 import (
 	"log"
 	"net/http"
+	"time"
 
 	"gotodo/api/config"
 	apictx "gotodo/api/context"
 	"gotodo/api/errors"
+	"gotodo/api/middleware/workspace"
 	"gotodo/api/v1"
+	"gotodo/blobstore"
+	"gotodo/bucket"
 	"gotodo/database"
+	"gotodo/mailer"
 	"gotodo/services"
+	"gotodo/throttle"
 
 	"github.com/beeker1121/httprouter"
 )
 
+// purgeInterval is how often closed accounts are checked for deletion.
+const purgeInterval = time.Minute
+
+// collectInterval is how often the attachments left behind by deleted todos
+// are collected.
+const collectInterval = 10 * time.Minute
+
 // New creates a new API application. All of the necessary routes for the
 // API will be created on the given router, which should then be used to
-// create the web server.
-func New(config *config.Config, logger *log.Logger, gdb *database.Database, router *httprouter.Router) {
+// create the web server. Emails are sent using the given mailer, failed login
+// attempts are kept in the given throttle store, and the bytes of
+// attachments are kept in the given blob store.
+func New(config *config.Config, logger *log.Logger, gdb *database.Database, m mailer.Mailer, ts throttle.Store, blobs blobstore.BlobStore, router *httprouter.Router) {
+	// Create the login throttle.
+	t := throttle.New(ts, &throttle.Limits{
+		BackoffBase:      time.Second * config.LoginBackoffBase,
+		BackoffMax:       time.Second * config.LoginBackoffMax,
+		LockoutThreshold: config.LoginLockoutThreshold,
+		LockoutTime:      time.Minute * config.LoginLockoutTime,
+		IPLimit:          config.LoginIPLimit,
+		IPWindow:         time.Minute * config.LoginIPWindow,
+	})
+
 	// Create the services.
-	services := services.New(gdb)
+	services := services.New(gdb, m, t, blobs)
 
 	// Create a new API context.
-	ac := apictx.New(config, logger, services)
+	ac := apictx.New(config, logger, services, bucket.NewLimiter())
 
 	// Create a new API v1.
 	v1.New(ac, router)
 
-	// Handle not found.
-	router.NotFound = http.HandlerFunc(handleNotFound(ac))
+	// Handle not found, serving the todo and list
+	// routes prefixed with a workspace first.
+	router.NotFound = workspace.Prefix(router, http.HandlerFunc(handleNotFound(ac)))
+
+	// Purge the closed accounts once their grace
+	// period has passed. This runs even without a
+	// grace period, for the accounts closed while
+	// there was one.
+	go purgeClosed(ac)
+
+	// Collect the attachments left behind
+	// by deleted todos.
+	go collectAttachments(ac)
+}
+
+// purgeClosed deletes the closed accounts whose grace period has passed
+// every purgeInterval, for as long as the process runs.
+func purgeClosed(ac *apictx.Context) {
+	for range time.Tick(purgeInterval) {
+		n, err := ac.Services.Members.PurgeClosed()
+		if err != nil {
+			ac.Logger.Printf("members.PurgeClosed() service error: %s\n", err)
+		}
+		if n > 0 {
+			ac.Logger.Printf("Purged %d closed account(s)\n", n)
+		}
+	}
+}
+
+// collectAttachments deletes the attachments left behind by deleted todos
+// every collectInterval, for as long as the process runs.
+func collectAttachments(ac *apictx.Context) {
+	for range time.Tick(collectInterval) {
+		if err := ac.Services.Todos.CollectAttachments(); err != nil {
+			ac.Logger.Printf("todos.CollectAttachments() service error: %s\n", err)
+		}
+	}
 }
 
 // handleNotFound handles 404 Not Found errors.
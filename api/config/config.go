@@ -8,20 +8,159 @@ import (
 	"time"
 )
 
+// DefaultRefreshExpiryTime is the number of minutes refresh tokens are valid
+// for when the refresh_expiry_time setting is missing, which is 30 days.
+const DefaultRefreshExpiryTime = 43200
+
+// DefaultResetExpiryTime is the number of minutes password reset tokens are
+// valid for when the reset_expiry_time setting is missing.
+const DefaultResetExpiryTime = 60
+
+// DefaultVerifyExpiryTime is the number of minutes email verification tokens
+// are valid for when the verify_expiry_time setting is missing, which is a
+// day.
+const DefaultVerifyExpiryTime = 1440
+
+// DefaultMFAExpiryTime is the number of minutes members have to enter their
+// two-factor authentication code after logging in when the mfa_expiry_time
+// setting is missing.
+const DefaultMFAExpiryTime = 5
+
+// DefaultLoginBackoffBase is the number of seconds an account has to wait
+// after its first failed login when the login_backoff_base setting is
+// missing.
+const DefaultLoginBackoffBase = 1
+
+// DefaultLoginBackoffMax is the maximum number of seconds an account has to
+// wait after a failed login when the login_backoff_max setting is missing.
+const DefaultLoginBackoffMax = 60
+
+// DefaultLoginLockoutThreshold is the number of failed logins after which an
+// account is locked out when the login_lockout_threshold setting is missing.
+const DefaultLoginLockoutThreshold = 10
+
+// DefaultLoginLockoutTime is the number of minutes an account is locked out
+// for when the login_lockout_time setting is missing.
+const DefaultLoginLockoutTime = 15
+
+// DefaultLoginIPLimit is the number of failed logins allowed per client IP
+// within the login IP window when the login_ip_limit setting is missing.
+const DefaultLoginIPLimit = 50
+
+// DefaultLoginIPWindow is the number of minutes of the sliding window of the
+// failed logins per client IP when the login_ip_window setting is missing.
+const DefaultLoginIPWindow = 15
+
+// DefaultBlobDir is the directory the local blob store keeps the bytes of
+// attachments in when the blob_dir setting is missing.
+const DefaultBlobDir = "attachments"
+
+// DefaultAttachmentMaxSize is the maximum size of an attachment in bytes
+// when the attachment_max_size setting is missing, which is 10 MB.
+const DefaultAttachmentMaxSize = 10 << 20
+
+// DefaultAttachmentTypes returns the MIME types allowed for attachments when
+// the attachment_types setting is missing, which are images and PDFs.
+func DefaultAttachmentTypes() []string {
+	return []string{"image/png", "image/jpeg", "image/gif", "image/webp", "application/pdf"}
+}
+
+// DefaultRateLimits returns the rate limits of the groups of routes missing
+// from the rate_limits setting.
+func DefaultRateLimits() map[string]*RateLimit {
+	return map[string]*RateLimit{
+		"auth":    {Limit: 20, Burst: 10},
+		"account": {Limit: 30, Burst: 10},
+		"todos":   {Limit: 120, Burst: 60},
+	}
+}
+
+// RateLimit defines the rate limit of a group of routes, which allows Limit
+// requests per minute, with up to Burst requests at once. Burst is the same
+// as Limit when it is zero, and the group is not limited when Limit is zero
+// or less.
+type RateLimit struct {
+	Limit int `json:"limit"`
+	Burst int `json:"burst"`
+}
+
 // Config defines the Go Todo API settings.
+//
+// JWTExpiryTime, RefreshExpiryTime, ResetExpiryTime, VerifyExpiryTime,
+// MFAExpiryTime and DeleteGraceTime are given in minutes.
+//
+// VerifyURL is the link sent to verify an email, with the token added as the
+// token query parameter. Only the token is sent if it is empty. When
+// RequireVerified is set, members must verify their email before using the
+// todos, lists, tags and series endpoints.
+//
+// DeleteGraceTime is how long closed accounts are kept before being deleted,
+// during which logging in reopens them. They are deleted right away if it
+// is zero.
+//
+// LoginBackoffBase and LoginBackoffMax are given in seconds, while
+// LoginLockoutTime and LoginIPWindow are given in minutes. After each failed
+// login, an account has to wait LoginBackoffBase before being tried again,
+// doubled for every failure before it, up to LoginBackoffMax. The account is
+// locked out for LoginLockoutTime after LoginLockoutThreshold failures, and
+// each client IP is allowed LoginIPLimit failures within LoginIPWindow.
+// Setting LoginBackoffBase, LoginLockoutThreshold or LoginIPLimit to a
+// negative number turns off the backoff, the lockout or the client IP limit.
+//
+// RateLimits sets the rate limit of each group of routes by name, which are
+// auth for logging in and signing up, account for managing the account and
+// its API keys, and todos for the todos, lists, tags and series. Requests
+// are limited per member, or per API key, or per client IP for the routes
+// that are not authenticated.
+//
+// BlobDriver selects where the bytes of attachments are kept, which is in
+// BlobDir on the local filesystem for the local driver. Attachments can be
+// up to AttachmentMaxSize bytes, and must be of one of AttachmentTypes,
+// which are MIME types detected from the bytes themselves.
+//
+// MailDriver selects how emails are sent, either through the SMTP server
+// given by the SMTP settings, or written to MailFile, or to standard output
+// if it is empty.
 type Config struct {
-	DBHost        string        `json:"db_host"`
-	DBPort        string        `json:"db_port"`
-	DBName        string        `json:"db_name"`
-	DBUser        string        `json:"db_user"`
-	DBPass        string        `json:"db_pass"`
-	APIHost       string        `json:"api_host"`
-	APIPort       string        `json:"api_port"`
-	LogFile       string        `json:"log_file"`
-	JWTSecret     string        `json:"jwt_secret"`
-	JWTExpiryTime time.Duration `json:"jwt_expiry_time"`
-	LimitDefault  int           `json:"limit_default"`
-	LimitMax      int           `json:"limit_max"`
+	DBDriver              string                `json:"db_driver"`
+	DBHost                string                `json:"db_host"`
+	DBPort                string                `json:"db_port"`
+	DBName                string                `json:"db_name"`
+	DBUser                string                `json:"db_user"`
+	DBPass                string                `json:"db_pass"`
+	DBCheckMigrations     bool                  `json:"db_check_migrations"`
+	APIHost               string                `json:"api_host"`
+	APIPort               string                `json:"api_port"`
+	LogFile               string                `json:"log_file"`
+	JWTSecret             string                `json:"jwt_secret"`
+	JWTExpiryTime         time.Duration         `json:"jwt_expiry_time"`
+	RefreshExpiryTime     time.Duration         `json:"refresh_expiry_time"`
+	ResetExpiryTime       time.Duration         `json:"reset_expiry_time"`
+	VerifyExpiryTime      time.Duration         `json:"verify_expiry_time"`
+	VerifyURL             string                `json:"verify_url"`
+	MFAExpiryTime         time.Duration         `json:"mfa_expiry_time"`
+	DeleteGraceTime       time.Duration         `json:"delete_grace_time"`
+	RequireVerified       bool                  `json:"require_verified"`
+	LoginBackoffBase      time.Duration         `json:"login_backoff_base"`
+	LoginBackoffMax       time.Duration         `json:"login_backoff_max"`
+	LoginLockoutThreshold int                   `json:"login_lockout_threshold"`
+	LoginLockoutTime      time.Duration         `json:"login_lockout_time"`
+	LoginIPLimit          int                   `json:"login_ip_limit"`
+	LoginIPWindow         time.Duration         `json:"login_ip_window"`
+	RateLimits            map[string]*RateLimit `json:"rate_limits"`
+	LimitDefault          int                   `json:"limit_default"`
+	LimitMax              int                   `json:"limit_max"`
+	BlobDriver            string                `json:"blob_driver"`
+	BlobDir               string                `json:"blob_dir"`
+	AttachmentMaxSize     int64                 `json:"attachment_max_size"`
+	AttachmentTypes       []string              `json:"attachment_types"`
+	MailDriver            string                `json:"mail_driver"`
+	MailFrom              string                `json:"mail_from"`
+	MailFile              string                `json:"mail_file"`
+	SMTPHost              string                `json:"smtp_host"`
+	SMTPPort              string                `json:"smtp_port"`
+	SMTPUser              string                `json:"smtp_user"`
+	SMTPPass              string                `json:"smtp_pass"`
 }
 
 // ParseConfigFile parses the API configuration file.
@@ -39,5 +178,73 @@ func ParseConfigFile(filepath string) (*Config, error) {
 		return nil, errors.New("Failed to Unmarshal JSON into struct")
 	}
 
+	// Use the default refresh token expiry
+	// time if it was not set.
+	if config.RefreshExpiryTime == 0 {
+		config.RefreshExpiryTime = DefaultRefreshExpiryTime
+	}
+
+	// Use the default password reset token
+	// expiry time if it was not set.
+	if config.ResetExpiryTime == 0 {
+		config.ResetExpiryTime = DefaultResetExpiryTime
+	}
+
+	// Use the default email verification token
+	// expiry time if it was not set.
+	if config.VerifyExpiryTime == 0 {
+		config.VerifyExpiryTime = DefaultVerifyExpiryTime
+	}
+
+	// Use the default two-factor authentication
+	// expiry time if it was not set.
+	if config.MFAExpiryTime == 0 {
+		config.MFAExpiryTime = DefaultMFAExpiryTime
+	}
+
+	// Use the default login throttling
+	// settings if they were not set.
+	if config.LoginBackoffBase == 0 {
+		config.LoginBackoffBase = DefaultLoginBackoffBase
+	}
+	if config.LoginBackoffMax == 0 {
+		config.LoginBackoffMax = DefaultLoginBackoffMax
+	}
+	if config.LoginLockoutThreshold == 0 {
+		config.LoginLockoutThreshold = DefaultLoginLockoutThreshold
+	}
+	if config.LoginLockoutTime == 0 {
+		config.LoginLockoutTime = DefaultLoginLockoutTime
+	}
+	if config.LoginIPLimit == 0 {
+		config.LoginIPLimit = DefaultLoginIPLimit
+	}
+	if config.LoginIPWindow == 0 {
+		config.LoginIPWindow = DefaultLoginIPWindow
+	}
+
+	// Use the default rate limits of the
+	// groups of routes that were not set.
+	if config.RateLimits == nil {
+		config.RateLimits = make(map[string]*RateLimit)
+	}
+	for group, rl := range DefaultRateLimits() {
+		if _, ok := config.RateLimits[group]; !ok {
+			config.RateLimits[group] = rl
+		}
+	}
+
+	// Use the default attachment settings
+	// if they were not set.
+	if config.BlobDir == "" {
+		config.BlobDir = DefaultBlobDir
+	}
+	if config.AttachmentMaxSize == 0 {
+		config.AttachmentMaxSize = DefaultAttachmentMaxSize
+	}
+	if config.AttachmentTypes == nil {
+		config.AttachmentTypes = DefaultAttachmentTypes()
+	}
+
 	return config, nil
 }
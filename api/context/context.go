@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"gotodo/api/config"
+	"gotodo/bucket"
 	"gotodo/services"
 )
 
@@ -13,13 +14,16 @@ type Context struct {
 	Config   *config.Config
 	Logger   *log.Logger
 	Services *services.Services
+	Limiter  *bucket.Limiter
 }
 
-// New returns a new API context.
-func New(config *config.Config, logger *log.Logger, services *services.Services) *Context {
+// New returns a new API context, limiting the rate of requests using the
+// given limiter.
+func New(config *config.Config, logger *log.Logger, services *services.Services, limiter *bucket.Limiter) *Context {
 	return &Context{
 		Config:   config,
 		Logger:   logger,
 		Services: services,
+		Limiter:  limiter,
 	}
 }
@@ -15,6 +15,10 @@ var (
 	// ErrBadRequest is returned usually when sent parameters are invalid.
 	ErrBadRequest = New(http.StatusBadRequest, "", "Bad request most likely due to invalid parameters")
 
+	// ErrTooManyRequests is returned when a client has made too many
+	// requests and is being rate limited.
+	ErrTooManyRequests = New(http.StatusTooManyRequests, "", "Too many requests, please try again later")
+
 	// ErrInternalServerError is returned when an internal server error occurs.
 	ErrInternalServerError = New(http.StatusInternalServerError, "", "Internal server error")
 )
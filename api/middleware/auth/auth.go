@@ -10,7 +10,9 @@ import (
 
 	apictx "gotodo/api/context"
 	"gotodo/api/errors"
+	"gotodo/services/keys"
 	"gotodo/services/members"
+	"gotodo/services/tokens"
 
 	"github.com/dgrijalva/jwt-go"
 )
@@ -22,14 +24,77 @@ type key int
 // request context.
 var AuthKey key = 1
 
+// SessionKey is the key used for storing and retrieving the session, or
+// refresh token family, of the JWT from the request context.
+var SessionKey key = 2
+
+// APIKeyKey is the key used for storing and retrieving the ID of the API key
+// used to authenticate the request from the request context.
+var APIKeyKey key = 3
+
+// WriteRoles are the roles allowed to change todos, lists, tags, series and
+// comments.
+var WriteRoles = []string{members.RoleMember, members.RoleAdmin}
+
+// AdminRoles are the roles allowed to use the admin endpoints.
+var AdminRoles = []string{members.RoleAdmin}
+
 // TokenClaims defines the custom claims we use for the JWT.
+//
+// The SessionID is the family of the refresh tokens the JWT was issued
+// with, so the JWT stops working once the family is revoked.
+//
+// The Role is the role of the member when the JWT was issued, for clients
+// to know what the member can do. Endpoints check the current role of the
+// member instead, so a role change takes effect right away.
 type TokenClaims struct {
-	MemberID int `json:"member_id"`
+	MemberID  int    `json:"member_id"`
+	SessionID string `json:"sid,omitempty"`
+	Role      string `json:"role,omitempty"`
 	jwt.StandardClaims
 }
 
-// NewJWT creates and returns a new signed JWT.
-func NewJWT(ac *apictx.Context, memberPassword string, mid int) (string, error) {
+// Tokens defines the tokens issued to a member when they log in or refresh
+// their access token.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// NewTokens starts a new session for the given member, issuing a JWT along
+// with the first refresh token of a new family.
+func NewTokens(ac *apictx.Context, member *members.Member) (*Tokens, error) {
+	// Create a new refresh token.
+	token, secret, err := ac.Services.Tokens.New(member.ID, time.Minute*ac.Config.RefreshExpiryTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return IssueTokens(ac, member, token, secret)
+}
+
+// IssueTokens issues a new JWT for the given member, within the session of
+// the given refresh token.
+func IssueTokens(ac *apictx.Context, member *members.Member, token *tokens.Token, secret string) (*Tokens, error) {
+	// Issue a new JWT for this member.
+	accessToken, err := NewJWT(ac, member.Password, member.ID, member.Role, token.Family)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Tokens.
+	t := &Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: secret,
+		ExpiresIn:    int(time.Minute * ac.Config.JWTExpiryTime / time.Second),
+	}
+
+	return t, nil
+}
+
+// NewJWT creates and returns a new signed JWT for the given session.
+func NewJWT(ac *apictx.Context, memberPassword string, mid int, role, sid string) (string, error) {
 	// Set expiry time.
 	issued := time.Now()
 	expires := issued.Add(time.Minute * ac.Config.JWTExpiryTime)
@@ -37,6 +102,8 @@ func NewJWT(ac *apictx.Context, memberPassword string, mid int) (string, error)
 	// Create the claims.
 	claims := &TokenClaims{
 		mid,
+		sid,
+		role,
 		jwt.StandardClaims{
 			IssuedAt:  issued.Unix(),
 			ExpiresAt: expires.Unix(),
@@ -60,12 +127,13 @@ func NewJWT(ac *apictx.Context, memberPassword string, mid int) (string, error)
 //
 // JWTs are passed via the Authorization header as a Bearer token.
 //
-// API keys should be passed via the Authorization header using Basic Auth.
-//
-// Currently, the only supported authorization method is via JWTs.
+// API keys should be passed via the Authorization header using Basic Auth,
+// with the key as the username and an empty password.
 func AuthenticateEndpoint(ac *apictx.Context, h http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		member := &members.Member{}
+		var sid string
+		var kid int
 		var err error
 
 		// Get the Authorization header.
@@ -79,7 +147,7 @@ func AuthenticateEndpoint(ac *apictx.Context, h http.HandlerFunc) http.HandlerFu
 
 		if len(authHeader) == 2 && authHeader[0] == "Bearer" {
 			// Try authorization via JWT Authorization Bearer header first.
-			member, err = GetMemberFromJWT(ac, authHeader[1])
+			member, sid, err = GetMemberFromJWT(ac, authHeader[1])
 			if err == ErrJWTUnauthorized {
 				ac.Logger.Println("API authorization via JWT failure")
 				errors.Default(ac.Logger, w, errors.New(http.StatusUnauthorized, "", err.Error()))
@@ -90,24 +158,102 @@ func AuthenticateEndpoint(ac *apictx.Context, h http.HandlerFunc) http.HandlerFu
 				return
 			}
 		} else {
-			// Get the member from the API key.
-			ac.Logger.Println("API key authorization not implemented")
-			errors.Default(ac.Logger, w, errors.New(http.StatusUnauthorized, "", "API key authorization not implemented"))
+			// Get the API key from the Authorization Basic header.
+			apiKey, _, ok := r.BasicAuth()
+			if !ok || apiKey == "" {
+				errors.Default(ac.Logger, w, errors.New(http.StatusUnauthorized, "", ErrUnauthorized.Error()))
+				return
+			}
+
+			// Try authorization via the API key.
+			var k *keys.Key
+			member, k, err = GetMemberFromAPIKey(ac, apiKey)
+			if err == ErrAPIKeyUnauthorized {
+				ac.Logger.Println("API authorization via API key failure")
+				errors.Default(ac.Logger, w, errors.New(http.StatusUnauthorized, "", err.Error()))
+				return
+			} else if err != nil {
+				ac.Logger.Printf("auth.GetMemberFromAPIKey() error: %s\n", err)
+				errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+				return
+			}
+			kid = k.ID
+		}
+
+		// Check the member is not suspended.
+		if member.SuspendedAt != nil {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", ErrSuspended.Error()))
 			return
 		}
 
-		// Pass member to request context and call next handler.
+		// Pass member, session and API key to request context and call next handler.
 		ctx := context.WithValue(r.Context(), AuthKey, member)
+		ctx = context.WithValue(ctx, SessionKey, sid)
+		ctx = context.WithValue(ctx, APIKeyKey, kid)
 		h(w, r.WithContext(ctx))
 	}
 }
 
-// GetMemberFromJWT retrieves the member from the given JWT.
-func GetMemberFromJWT(ac *apictx.Context, headerToken string) (*members.Member, error) {
+// AuthenticateVerified is the middleware for authenticating API requests to
+// endpoints that require a verified email, when the API is configured to.
+//
+// Requests are authenticated the same way as with AuthenticateEndpoint.
+func AuthenticateVerified(ac *apictx.Context, h http.HandlerFunc) http.HandlerFunc {
+	return AuthenticateEndpoint(ac, func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Check the member is verified.
+		if ac.Config.RequireVerified && member.VerifiedAt == nil {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", ErrUnverified.Error()))
+			return
+		}
+
+		h(w, r)
+	})
+}
+
+// AuthorizeRoles is the middleware for authorizing API requests to
+// endpoints that require one of the given roles.
+//
+// It has to be called after AuthenticateEndpoint, such as:
+//
+// auth.AuthenticateEndpoint(ac, auth.AuthorizeRoles(ac, auth.AdminRoles, h))
+func AuthorizeRoles(ac *apictx.Context, roles []string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Check the role of the member.
+		for _, role := range roles {
+			if member.Role == role {
+				h(w, r)
+				return
+			}
+		}
+
+		errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", ErrForbiddenRole.Error()))
+	}
+}
+
+// GetMemberFromJWT retrieves the member and the session from the given JWT.
+//
+// JWTs issued before sessions were introduced do not have a session, and
+// are rejected so their members log in again, as they could not be revoked
+// by logging out everywhere otherwise.
+func GetMemberFromJWT(ac *apictx.Context, headerToken string) (*members.Member, string, error) {
 	// Get the signing key for this member from the JWT claims.
 	signingKey, err := GetMemberSigningKey(ac, headerToken)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// Parse the token.
@@ -120,25 +266,69 @@ func GetMemberFromJWT(ac *apictx.Context, headerToken string) (*members.Member,
 		return signingKey, nil
 	})
 	if err != nil {
-		return nil, ErrJWTUnauthorized
+		return nil, "", ErrJWTUnauthorized
 	}
 
 	// Get token claims and check token validity.
 	claims, ok := token.Claims.(*TokenClaims)
 	if !ok || !token.Valid {
-		return nil, ErrJWTUnauthorized
+		return nil, "", ErrJWTUnauthorized
 	}
 
 	// Get the member using the MemberID claim.
 	member, err := ac.Services.Members.GetByID(claims.MemberID)
 	switch {
 	case err == members.ErrMemberNotFound:
-		return nil, ErrJWTUnauthorized
+		return nil, "", ErrJWTUnauthorized
 	case err != nil:
-		return nil, err
+		return nil, "", err
 	}
 
-	return member, nil
+	// Check the account has not been closed.
+	if member.DeleteAt != nil {
+		return nil, "", ErrJWTUnauthorized
+	}
+
+	// Check the session has not been revoked.
+	if claims.SessionID == "" {
+		return nil, "", ErrJWTUnauthorized
+	}
+	active, err := ac.Services.Tokens.IsFamilyActive(claims.SessionID)
+	if err != nil {
+		return nil, "", err
+	} else if !active {
+		return nil, "", ErrJWTUnauthorized
+	}
+
+	return member, claims.SessionID, nil
+}
+
+// GetMemberFromAPIKey retrieves the given API key and the member owning it.
+func GetMemberFromAPIKey(ac *apictx.Context, apiKey string) (*members.Member, *keys.Key, error) {
+	// Try to authenticate the API key.
+	key, err := ac.Services.Keys.Authenticate(apiKey)
+	switch {
+	case err == keys.ErrInvalidKey:
+		return nil, nil, ErrAPIKeyUnauthorized
+	case err != nil:
+		return nil, nil, err
+	}
+
+	// Get the member owning this key.
+	member, err := ac.Services.Members.GetByID(key.MemberID)
+	switch {
+	case err == members.ErrMemberNotFound:
+		return nil, nil, ErrAPIKeyUnauthorized
+	case err != nil:
+		return nil, nil, err
+	}
+
+	// Check the account has not been closed.
+	if member.DeleteAt != nil {
+		return nil, nil, ErrAPIKeyUnauthorized
+	}
+
+	return member, key, nil
 }
 
 // GetMemberSigningKey creates the unique JWT signing key for the given member
@@ -194,3 +384,19 @@ func GetMemberFromRequest(r *http.Request) (*members.Member, error) {
 	}
 	return member, nil
 }
+
+// GetSessionFromRequest retrieves the session of the JWT used to authenticate
+// the request from the request context. It is empty for requests using an
+// API key, or a JWT issued without a session.
+func GetSessionFromRequest(r *http.Request) string {
+	sid, _ := r.Context().Value(SessionKey).(string)
+	return sid
+}
+
+// GetAPIKeyFromRequest retrieves the ID of the API key used to authenticate
+// the request from the request context. It is zero for requests using a
+// JWT.
+func GetAPIKeyFromRequest(r *http.Request) int {
+	kid, _ := r.Context().Value(APIKeyKey).(int)
+	return kid
+}
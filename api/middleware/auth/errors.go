@@ -8,4 +8,19 @@ var (
 
 	// ErrJWTUnauthorized is returned when there is an error during JWT authorization.
 	ErrJWTUnauthorized = errors.New("Could not validate JWT")
+
+	// ErrAPIKeyUnauthorized is returned when there is an error during API key
+	// authorization.
+	ErrAPIKeyUnauthorized = errors.New("Could not validate API key")
+
+	// ErrUnverified is returned when an unverified member uses an endpoint
+	// that requires a verified email.
+	ErrUnverified = errors.New("Email must be verified to use this endpoint")
+
+	// ErrSuspended is returned when a suspended member uses an endpoint.
+	ErrSuspended = errors.New("Account is suspended")
+
+	// ErrForbiddenRole is returned when a member uses an endpoint their
+	// role does not allow.
+	ErrForbiddenRole = errors.New("Your role does not allow using this endpoint")
 )
@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/bucket"
+)
+
+const (
+	// GroupAuth is the group of the routes used to log in and sign up.
+	GroupAuth = "auth"
+
+	// GroupAccount is the group of the routes used to manage the account
+	// and its API keys.
+	GroupAccount = "account"
+
+	// GroupTodos is the group of the todos, lists, tags and series routes.
+	GroupTodos = "todos"
+)
+
+// LimitEndpoint is the middleware for limiting the rate of API requests to
+// the routes of the given group, as set in the rate limits of the config.
+//
+// Requests are limited per API key when authenticated with one, per member
+// when authenticated with a JWT, and per client IP otherwise, so it has to
+// be called after AuthenticateEndpoint for authenticated routes, such as:
+//
+// auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, group, h))
+//
+// The RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers are
+// set on every response, along with Retry-After once rate limited.
+func LimitEndpoint(ac *apictx.Context, group string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the rate limit of this group.
+		rl, ok := ac.Config.RateLimits[group]
+		if !ok || rl.Limit <= 0 {
+			h(w, r)
+			return
+		}
+
+		// Create the limit of the bucket.
+		limit := &bucket.Limit{
+			Rate:  rl.Limit,
+			Per:   time.Minute,
+			Burst: rl.Burst,
+		}
+		if limit.Burst <= 0 {
+			limit.Burst = rl.Limit
+		}
+
+		// Take a token from the bucket of this client.
+		result := ac.Limiter.Take(group+":"+clientKey(r), limit)
+
+		// Set the rate limit headers.
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+
+		// Check the request is allowed.
+		if !result.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+			errors.Default(ac.Logger, w, errors.ErrTooManyRequests)
+			return
+		}
+
+		h(w, r)
+	}
+}
+
+// clientKey returns the key identifying the client of the given request,
+// which is the API key or the member it was authenticated with, or the
+// client IP.
+func clientKey(r *http.Request) string {
+	if kid := auth.GetAPIKeyFromRequest(r); kid != 0 {
+		return fmt.Sprintf("key:%d", kid)
+	}
+
+	if member, err := auth.GetMemberFromRequest(r); err == nil {
+		return fmt.Sprintf("member:%d", member.ID)
+	}
+
+	return "ip:" + ClientIP(r)
+}
+
+// ClientIP returns the IP address of the client of the given request.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// seconds returns the given duration in seconds, rounded up.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,9 @@
+package workspace
+
+import "errors"
+
+var (
+	// ErrWorkspaceIDInvalid is returned when the workspace ID given in the
+	// X-Workspace-ID header is invalid.
+	ErrWorkspaceIDInvalid = errors.New("Workspace ID in X-Workspace-ID header is invalid")
+)
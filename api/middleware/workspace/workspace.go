@@ -0,0 +1,115 @@
+package workspace
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/services/workspaces"
+)
+
+// Header is the header used to select the active workspace of a request.
+const Header = "X-Workspace-ID"
+
+// key is the key type used by this package for the request context.
+type key int
+
+// WorkspaceKey is the key used for storing and retrieving the ID of the
+// active workspace from the request context.
+var WorkspaceKey key = 1
+
+// prefix matches the paths of the todo and list routes prefixed with a
+// workspace, such as /api/v1/workspaces/1/todos.
+var prefix = regexp.MustCompile(`^/api/v1/workspaces/([0-9]+)(/(?:todos|lists)(?:/.*)?)$`)
+
+// Prefix returns the handler serving the todo and list routes prefixed with
+// a workspace, such as /api/v1/workspaces/1/todos, on the given router.
+//
+// The prefix is removed from the path and the workspace is given to the
+// route using the Header instead, so the routes do not have to be created
+// twice. Any other request is handled by the given not found handler, so
+// this is meant to be used as the NotFound handler of the router.
+func Prefix(router http.Handler, notFound http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Check the path is prefixed with a workspace.
+		matches := prefix.FindStringSubmatch(r.URL.Path)
+		if matches == nil {
+			notFound.ServeHTTP(w, r)
+			return
+		}
+
+		// Copy the request, moving the workspace
+		// from the path to the header.
+		u := *r.URL
+		u.Path = "/api/v1" + matches[2]
+		u.RawPath = ""
+		pr := r.Clone(r.Context())
+		pr.URL = &u
+		pr.Header.Set(Header, matches[1])
+
+		router.ServeHTTP(w, pr)
+	})
+}
+
+// Select is the middleware for selecting the active workspace of API
+// requests, given by the Header. Requests without it use the personal
+// space of the member.
+//
+// The member has to belong to the workspace, so it has to be called after
+// AuthenticateEndpoint, such as:
+//
+// auth.AuthenticateEndpoint(ac, workspace.Select(ac, h))
+func Select(ac *apictx.Context, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Use the personal space if no
+		// workspace was selected.
+		value := r.Header.Get(Header)
+		if value == "" {
+			h(w, r)
+			return
+		}
+
+		// Try to get the workspace ID.
+		id64, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || id64 <= 0 {
+			errors.Default(ac.Logger, w, errors.New(http.StatusBadRequest, "", ErrWorkspaceIDInvalid.Error()))
+			return
+		}
+		id := int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Make sure the member belongs to this workspace.
+		if _, err := ac.Services.Workspaces.GetByIDAndMemberID(id, member.ID); err == workspaces.ErrWorkspaceNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("workspaces.GetByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Pass the workspace to request context and call next handler.
+		ctx := context.WithValue(r.Context(), WorkspaceKey, id)
+		h(w, r.WithContext(ctx))
+	}
+}
+
+// GetWorkspaceFromRequest retrieves the ID of the active workspace from the
+// request context. It is nil when the personal space is used.
+func GetWorkspaceFromRequest(r *http.Request) *int {
+	id, ok := r.Context().Value(WorkspaceKey).(int)
+	if !ok {
+		return nil
+	}
+	return &id
+}
@@ -0,0 +1,15 @@
+package pagination
+
+import "errors"
+
+var (
+	// ErrOffsetInvalid is returned when the offset parameter is invalid.
+	ErrOffsetInvalid = errors.New("Offset parameter is invalid, must be an integer")
+
+	// ErrLimitInvalid is returned when the limit parameter is invalid.
+	ErrLimitInvalid = errors.New("Limit parameter is invalid, must be an integer")
+
+	// ErrLimitMax is returned when the limit parameter is greater than the
+	// maximum allowable limit.
+	ErrLimitMax = errors.New("Limit parameter is greater than maximum allowable limit")
+)
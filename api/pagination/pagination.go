@@ -0,0 +1,119 @@
+package pagination
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"gotodo/api/config"
+	"gotodo/api/errors"
+)
+
+// Meta defines the response top level meta object.
+type Meta struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+	Total  int `json:"total"`
+}
+
+// Links defines the response top level links object.
+type Links struct {
+	Prev *string `json:"prev"`
+	Next *string `json:"next"`
+}
+
+// Params defines the pagination parameters of a request.
+type Params struct {
+	Offset int
+	Limit  int
+}
+
+// Parse parses the offset and limit query string parameters of the given
+// request, adding any errors to the given API Errors.
+func Parse(cfg *config.Config, r *http.Request, errs *errors.Errors) Params {
+	// Create a new Params.
+	params := Params{
+		Offset: 0,
+		Limit:  cfg.LimitDefault,
+	}
+
+	// Handle offset.
+	if offsetqs, ok := r.URL.Query()["offset"]; ok && len(offsetqs) == 1 {
+		offset64, err := strconv.ParseInt(offsetqs[0], 10, 32)
+		if err != nil || offset64 < 0 {
+			errs.Add(errors.New(http.StatusBadRequest, "offset", ErrOffsetInvalid.Error()))
+		} else {
+			params.Offset = int(offset64)
+		}
+	}
+
+	// Handle limit.
+	if limitqs, ok := r.URL.Query()["limit"]; ok && len(limitqs) == 1 {
+		limit64, err := strconv.ParseInt(limitqs[0], 10, 32)
+		if err != nil || limit64 < 0 {
+			errs.Add(errors.New(http.StatusBadRequest, "limit", ErrLimitInvalid.Error()))
+		} else {
+			if int(limit64) > cfg.LimitMax {
+				errs.Add(errors.New(http.StatusBadRequest, "limit", ErrLimitMax.Error()+" of "+strconv.FormatUint(uint64(cfg.LimitMax), 10)))
+			} else {
+				params.Limit = int(limit64)
+			}
+		}
+	}
+
+	return params
+}
+
+// New returns the meta and links objects for the page of results described
+// by the given parameters and total number of results.
+//
+// The links point to the path of the given request, keeping all of its
+// query string parameters other than the offset and limit, so filters are
+// kept when paging through results.
+func New(cfg *config.Config, r *http.Request, params Params, total int) (Meta, Links) {
+	// Create a new Meta.
+	meta := Meta{
+		Offset: params.Offset,
+		Limit:  params.Limit,
+		Total:  total,
+	}
+
+	// Create a new Links.
+	links := Links{}
+
+	// Handle previous link.
+	if params.Offset > 0 {
+		offset := params.Offset - params.Limit
+		if offset < 0 {
+			offset = 0
+		}
+
+		prev := link(cfg, r, offset, params.Limit)
+		links.Prev = &prev
+	}
+
+	// Handle next link.
+	if params.Offset+params.Limit < total {
+		next := link(cfg, r, params.Offset+params.Limit, params.Limit)
+		links.Next = &next
+	}
+
+	return meta, links
+}
+
+// link returns the link to the page of results at the given offset.
+func link(cfg *config.Config, r *http.Request, offset, limit int) string {
+	// Copy the query string parameters of the request.
+	query := r.URL.Query()
+	query.Set("offset", strconv.FormatInt(int64(offset), 10))
+	query.Set("limit", strconv.FormatInt(int64(limit), 10))
+
+	// Use the path the request was sent to, since the path
+	// of the routes prefixed with a workspace is rewritten.
+	path := r.URL.Path
+	if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		path = u.Path
+	}
+
+	return "https://" + cfg.APIHost + path + "?" + query.Encode()
+}
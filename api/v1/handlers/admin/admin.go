@@ -0,0 +1,314 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/ratelimit"
+	"gotodo/api/pagination"
+	"gotodo/api/render"
+	serverrors "gotodo/services/errors"
+	"gotodo/services/members"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// ResultGet defines the response data for the HandleGet handler.
+type ResultGet struct {
+	Data  []*members.Member `json:"data"`
+	Meta  pagination.Meta   `json:"meta"`
+	Links pagination.Links  `json:"links"`
+}
+
+// ResultMember defines the response data for the handlers returning a
+// member.
+type ResultMember struct {
+	Data *members.Member `json:"data"`
+}
+
+// New creates the routes for the admin endpoints of the API, which are only
+// allowed to administrators.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.GET("/api/v1/admin/members", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, auth.AuthorizeRoles(ac, auth.AdminRoles, HandleGet(ac)))))
+	router.GET("/api/v1/admin/members/:id", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, auth.AuthorizeRoles(ac, auth.AdminRoles, HandleGetMember(ac)))))
+	router.POST("/api/v1/admin/members/:id/role", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, auth.AuthorizeRoles(ac, auth.AdminRoles, HandleRole(ac)))))
+	router.POST("/api/v1/admin/members/:id/suspend", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, auth.AuthorizeRoles(ac, auth.AdminRoles, HandleSuspend(ac)))))
+	router.POST("/api/v1/admin/members/:id/unsuspend", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, auth.AuthorizeRoles(ac, auth.AdminRoles, HandleUnsuspend(ac)))))
+	router.POST("/api/v1/admin/members/:id/reset", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, auth.AuthorizeRoles(ac, auth.AdminRoles, HandleReset(ac)))))
+}
+
+// HandleGet handles the /api/v1/admin/members GET route of the API.
+//
+// The members can be filtered by role with ?role= and by suspension with
+// ?suspended=true or false.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Create a new GetParams.
+		params := &members.GetParams{}
+
+		// Create a new API Errors.
+		errs := &errors.Errors{}
+
+		// Handle role.
+		if roleqs, ok := r.URL.Query()["role"]; ok && len(roleqs) == 1 {
+			if !members.IsRole(roleqs[0]) {
+				errs.Add(errors.New(http.StatusBadRequest, "role", ErrRoleInvalid.Error()))
+			} else {
+				params.Role = &roleqs[0]
+			}
+		}
+
+		// Handle suspended.
+		if suspendedqs, ok := r.URL.Query()["suspended"]; ok && len(suspendedqs) == 1 {
+			suspended, err := strconv.ParseBool(suspendedqs[0])
+			if err != nil {
+				errs.Add(errors.New(http.StatusBadRequest, "suspended", ErrSuspendedInvalid.Error()))
+			} else {
+				params.Suspended = &suspended
+			}
+		}
+
+		// Handle offset and limit.
+		page := pagination.Parse(ac.Config, r, errs)
+		params.Offset = page.Offset
+		params.Limit = page.Limit
+
+		// Return if there were errors.
+		if errs.Length() > 0 {
+			errors.Multiple(ac.Logger, w, http.StatusBadRequest, errs)
+			return
+		}
+
+		// Try to get the members.
+		ms, err := ac.Services.Members.Get(params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.Get() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultGet{
+			Data: ms.Members,
+		}
+		result.Meta, result.Links = pagination.New(ac.Config, r, page, ms.Total)
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleGetMember handles the /api/v1/admin/members/:id GET route of the
+// API.
+func HandleGetMember(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the member ID.
+		id, ok := getID(ac, w, r)
+		if !ok {
+			return
+		}
+
+		// Try to get this member.
+		member, err := ac.Services.Members.GetByID(id)
+		if err == members.ErrMemberNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.GetByID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderMember(ac, w, member)
+	}
+}
+
+// HandleRole handles the /api/v1/admin/members/:id/role POST route of the
+// API.
+func HandleRole(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params members.SetRoleParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the member ID of another member.
+		id, ok := getOtherID(ac, w, r)
+		if !ok {
+			return
+		}
+
+		// Try to set the role of this member.
+		member, err := ac.Services.Members.SetRole(id, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == members.ErrMemberNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.SetRole() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderMember(ac, w, member)
+	}
+}
+
+// HandleSuspend handles the /api/v1/admin/members/:id/suspend POST route of
+// the API.
+//
+// The member is logged out everywhere and cannot log in or use the API
+// until the suspension is lifted.
+func HandleSuspend(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the member ID of another member.
+		id, ok := getOtherID(ac, w, r)
+		if !ok {
+			return
+		}
+
+		// Try to suspend this member.
+		member, err := ac.Services.Members.Suspend(id)
+		if err == members.ErrMemberNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.Suspend() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderMember(ac, w, member)
+	}
+}
+
+// HandleUnsuspend handles the /api/v1/admin/members/:id/unsuspend POST
+// route of the API.
+func HandleUnsuspend(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the member ID.
+		id, ok := getID(ac, w, r)
+		if !ok {
+			return
+		}
+
+		// Try to lift the suspension of this member.
+		member, err := ac.Services.Members.Unsuspend(id)
+		if err == members.ErrMemberNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.Unsuspend() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderMember(ac, w, member)
+	}
+}
+
+// HandleReset handles the /api/v1/admin/members/:id/reset POST route of the
+// API.
+//
+// The password of the member is replaced and they are logged out
+// everywhere, then emailed a password reset token to choose a new one.
+// Two-factor authentication is also disabled when the request body has
+// "disable_totp": true.
+func HandleReset(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params members.ResetParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the member ID.
+		id, ok := getID(ac, w, r)
+		if !ok {
+			return
+		}
+
+		// Try to reset this member.
+		member, err := ac.Services.Members.Reset(id, &params, time.Minute*ac.Config.ResetExpiryTime)
+		if err == members.ErrMemberNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.Reset() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderMember(ac, w, member)
+	}
+}
+
+// getID gets the member ID from the path of the given request, rendering an
+// error if it is invalid.
+func getID(ac *apictx.Context, w http.ResponseWriter, r *http.Request) (int, bool) {
+	id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+	if err != nil {
+		errors.Default(ac.Logger, w, errors.ErrBadRequest)
+		return 0, false
+	}
+
+	return int(id64), true
+}
+
+// getOtherID gets the member ID from the path of the given request like
+// getID, also rendering an error if it is the ID of the administrator
+// making the request.
+func getOtherID(ac *apictx.Context, w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, ok := getID(ac, w, r)
+	if !ok {
+		return 0, false
+	}
+
+	// Get this member from the request context.
+	member, err := auth.GetMemberFromRequest(r)
+	if err != nil {
+		errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+		return 0, false
+	}
+
+	// Check the member is not the administrator.
+	if id == member.ID {
+		errors.Default(ac.Logger, w, errors.New(http.StatusBadRequest, "", ErrSelf.Error()))
+		return 0, false
+	}
+
+	return id, true
+}
+
+// renderMember renders the given member.
+func renderMember(ac *apictx.Context, w http.ResponseWriter, member *members.Member) {
+	// Create a new Result.
+	result := ResultMember{
+		Data: member,
+	}
+
+	// Render output.
+	if err := render.JSON(w, true, result); err != nil {
+		ac.Logger.Printf("render.JSON() error: %s\n", err)
+		errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,16 @@
+package admin
+
+import "errors"
+
+var (
+	// ErrRoleInvalid is returned when the role parameter is invalid.
+	ErrRoleInvalid = errors.New("Role parameter is invalid, must be one of member, admin or read-only")
+
+	// ErrSuspendedInvalid is returned when the suspended parameter is
+	// invalid.
+	ErrSuspendedInvalid = errors.New("Suspended parameter is invalid, must be a boolean")
+
+	// ErrSelf is returned when administrators try to suspend or change the
+	// role of their own account, which could lock every administrator out.
+	ErrSelf = errors.New("Administrators cannot suspend or change the role of their own account")
+)
@@ -0,0 +1,253 @@
+package keys
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/ratelimit"
+	"gotodo/api/render"
+	serverrors "gotodo/services/errors"
+	servkeys "gotodo/services/keys"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// Key defines the API key API type.
+//
+// This mirrors the service Key type, which mirrors the database Key type.
+// However, we specify that the MemberID and Hash should not be included
+// when encoding to JSON.
+//
+// The Key field holds the plain text key and is only set when the key is
+// first created.
+type Key struct {
+	ID       int        `json:"id"`
+	MemberID int        `json:"-"`
+	Name     string     `json:"name"`
+	Prefix   string     `json:"prefix"`
+	Key      string     `json:"key,omitempty"`
+	Created  time.Time  `json:"created"`
+	LastUsed *time.Time `json:"last_used"`
+}
+
+// ResultGet defines the response data for the HandleGet handler.
+type ResultGet struct {
+	Data []*Key `json:"data"`
+}
+
+// ResultPost defines the response data for the HandlePost handler.
+type ResultPost struct {
+	Data *Key `json:"data"`
+}
+
+// ResultUpdate defines the response data for the HandleUpdate handler.
+type ResultUpdate struct {
+	Data *Key `json:"data"`
+}
+
+// New creates the routes for the API key endpoints of the API.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.GET("/api/v1/keys", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandleGet(ac))))
+	router.POST("/api/v1/keys", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandlePost(ac))))
+	router.POST("/api/v1/keys/:id", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandleUpdate(ac))))
+	router.DELETE("/api/v1/keys/:id", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandleDelete(ac))))
+}
+
+// HandleGet handles the /api/v1/keys GET route of the API.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get the keys.
+		keys, err := ac.Services.Keys.GetByMemberID(member.ID)
+		if err != nil {
+			ac.Logger.Printf("keys.GetByMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultGet{
+			Data: []*Key{},
+		}
+
+		// Loop through the keys.
+		for _, k := range keys.Keys {
+			// Copy the Key type over.
+			key := &Key{
+				ID:       k.ID,
+				MemberID: k.MemberID,
+				Name:     k.Name,
+				Prefix:   k.Prefix,
+				Created:  k.Created,
+				LastUsed: k.LastUsed,
+			}
+
+			result.Data = append(result.Data, key)
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandlePost handles the /api/v1/keys POST route of the API.
+func HandlePost(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servkeys.NewParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to create a new key.
+		key, secret, err := ac.Services.Keys.New(member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err != nil {
+			ac.Logger.Printf("keys.New() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultPost{
+			Data: &Key{
+				ID:       key.ID,
+				MemberID: key.MemberID,
+				Name:     key.Name,
+				Prefix:   key.Prefix,
+				Key:      secret,
+				Created:  key.Created,
+				LastUsed: key.LastUsed,
+			},
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleUpdate handles the /api/v1/keys/:id POST route of the API.
+func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servkeys.UpdateParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the key ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to update this key.
+		key, err := ac.Services.Keys.UpdateByIDAndMemberID(id, member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servkeys.ErrKeyNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("keys.UpdateByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultUpdate{
+			Data: &Key{
+				ID:       key.ID,
+				MemberID: key.MemberID,
+				Name:     key.Name,
+				Prefix:   key.Prefix,
+				Created:  key.Created,
+				LastUsed: key.LastUsed,
+			},
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleDelete handles the /api/v1/keys/:id DELETE route of the API.
+func HandleDelete(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the key ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to revoke this key.
+		err = ac.Services.Keys.DeleteByIDAndMemberID(id, member.ID)
+		if err == servkeys.ErrKeyNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("keys.DeleteByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,316 @@
+package lists
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/ratelimit"
+	"gotodo/api/middleware/workspace"
+	"gotodo/api/render"
+	serverrors "gotodo/services/errors"
+	servlists "gotodo/services/lists"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// List defines the list API type.
+//
+// This mirrors the service List type. However, we specify that the MemberID
+// should not be included when encoding to JSON.
+//
+// Owned tells whether the list belongs to the member or was shared with
+// them, in which case Permission is either viewer or editor.
+type List struct {
+	ID          int       `json:"id"`
+	MemberID    int       `json:"-"`
+	WorkspaceID *int      `json:"workspace_id"`
+	Created     time.Time `json:"created"`
+	Name        string    `json:"name"`
+	Owned       bool      `json:"owned"`
+	Permission  string    `json:"permission"`
+}
+
+// ResultGet defines the response data for the HandleGet handler.
+type ResultGet struct {
+	Data []*List `json:"data"`
+}
+
+// ResultGetList defines the response data for the HandleGetList handler.
+type ResultGetList struct {
+	Data *List `json:"data"`
+}
+
+// ResultPost defines the response data for the HandlePost handler.
+type ResultPost struct {
+	Data *List `json:"data"`
+}
+
+// ResultUpdate defines the response data for the HandleUpdate handler.
+type ResultUpdate struct {
+	Data *List `json:"data"`
+}
+
+// New creates the routes for the list endpoints of the API.
+//
+// The todos of a list are served by the todos endpoints.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.GET("/api/v1/lists", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, HandleGet(ac)))))
+	router.GET("/api/v1/lists/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, HandleGetList(ac)))))
+	router.POST("/api/v1/lists", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandlePost(ac))))))
+	router.POST("/api/v1/lists/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleUpdate(ac))))))
+	router.DELETE("/api/v1/lists/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleDelete(ac))))))
+}
+
+// HandleGet handles the /api/v1/lists GET route of the API.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get the lists.
+		lists, err := ac.Services.Lists.GetByMemberID(member.ID, workspace.GetWorkspaceFromRequest(r))
+		if err == servlists.ErrWorkspaceNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("lists.GetByMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultGet{
+			Data: []*List{},
+		}
+
+		// Loop through the lists.
+		for _, l := range lists.Lists {
+			result.Data = append(result.Data, newList(l))
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleGetList handles the /api/v1/lists/:id GET route of the API.
+func HandleGetList(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the list ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get this list.
+		list, err := ac.Services.Lists.GetByIDAndMemberID(id, member.ID, workspace.GetWorkspaceFromRequest(r))
+		if err == servlists.ErrListNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("lists.GetByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultGetList{
+			Data: newList(list),
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandlePost handles the /api/v1/lists POST route of the API.
+func HandlePost(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servlists.NewParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to create a new list.
+		list, err := ac.Services.Lists.New(member.ID, workspace.GetWorkspaceFromRequest(r), &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servlists.ErrWorkspaceNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("lists.New() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultPost{
+			Data: newList(list),
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleUpdate handles the /api/v1/lists/:id POST route of the API.
+func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servlists.UpdateParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the list ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to update this list.
+		list, err := ac.Services.Lists.UpdateByIDAndMemberID(id, member.ID, workspace.GetWorkspaceFromRequest(r), &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servlists.ErrListNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servlists.ErrOwnerOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("lists.UpdateByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultUpdate{
+			Data: newList(list),
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleDelete handles the /api/v1/lists/:id DELETE route of the API.
+//
+// The todos of the list are moved to the inbox, unless ?todos=delete is
+// given, in which case they are deleted along with the list.
+func HandleDelete(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the list ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new DeleteParams.
+		params := &servlists.DeleteParams{
+			Todos: r.URL.Query().Get("todos"),
+		}
+
+		// Try to delete this list.
+		err = ac.Services.Lists.DeleteByIDAndMemberID(id, member.ID, workspace.GetWorkspaceFromRequest(r), params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servlists.ErrListNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servlists.ErrOwnerOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("lists.DeleteByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// newList copies the given service list over to the API type.
+func newList(l *servlists.List) *List {
+	return &List{
+		ID:          l.ID,
+		MemberID:    l.MemberID,
+		WorkspaceID: l.WorkspaceID,
+		Created:     l.Created,
+		Name:        l.Name,
+		Owned:       l.Permission == servlists.PermissionOwner,
+		Permission:  l.Permission,
+	}
+}
@@ -2,30 +2,54 @@ package login
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	apictx "gotodo/api/context"
 	"gotodo/api/errors"
 	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/ratelimit"
 	"gotodo/api/render"
 	serverrors "gotodo/services/errors"
 	"gotodo/services/members"
+	"gotodo/throttle"
 
 	"github.com/beeker1121/httprouter"
 )
 
 // ResultPost defines the response data for the HandlePost handler.
 type ResultPost struct {
-	Data string `json:"data"`
+	Data *auth.Tokens `json:"data"`
+}
+
+// Challenge defines the response data when the member has two-factor
+// authentication enabled, in place of the tokens. The MFA token is used to
+// finish logging in with the HandleMFA handler.
+type Challenge struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// ResultChallenge defines the response data for the HandlePost handler when
+// the member has two-factor authentication enabled.
+type ResultChallenge struct {
+	Data *Challenge `json:"data"`
 }
 
 // New creates the routes for the login endpoints of the API.
 func New(ac *apictx.Context, router *httprouter.Router) {
 	// Handle the routes.
-	router.POST("/api/v1/login", HandlePost(ac))
+	router.POST("/api/v1/login", ratelimit.LimitEndpoint(ac, ratelimit.GroupAuth, HandlePost(ac)))
+	router.POST("/api/v1/login/mfa", ratelimit.LimitEndpoint(ac, ratelimit.GroupAuth, HandleMFA(ac)))
 }
 
 // HandlePost handles the /api/v1/login POST route of the API.
+//
+// When the member has two-factor authentication enabled, a challenge is
+// returned instead of the tokens.
 func HandlePost(ac *apictx.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Parse the parameters from the request body.
@@ -35,11 +59,20 @@ func HandlePost(ac *apictx.Context) http.HandlerFunc {
 			return
 		}
 
+		// Set the client IP.
+		params.IP = ratelimit.ClientIP(r)
+
 		// Try to log this member in.
 		member, err := ac.Services.Members.Login(&params)
 		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
 			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
 			return
+		} else if te, ok := err.(*throttle.Error); ok && err != nil {
+			handleThrottled(ac, w, te)
+			return
+		} else if err == members.ErrSuspended {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
 		} else if err == members.ErrInvalidLogin {
 			errors.Default(ac.Logger, w, errors.New(http.StatusUnauthorized, "", err.Error()))
 			return
@@ -49,17 +82,106 @@ func HandlePost(ac *apictx.Context) http.HandlerFunc {
 			return
 		}
 
-		// Issue a new JWT for this member.
-		token, err := auth.NewJWT(ac, member.Password, member.ID)
+		// Ask for the second factor if enabled.
+		if member.TOTPEnabledAt != nil {
+			handleChallenge(ac, w, member)
+			return
+		}
+
+		// Start a new session for this member.
+		tokens, err := auth.NewTokens(ac, member)
+		if err != nil {
+			ac.Logger.Printf("auth.NewTokens() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultPost{
+			Data: tokens,
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// handleChallenge renders the challenge used to finish logging in the given
+// member with two-factor authentication.
+func handleChallenge(ac *apictx.Context, w http.ResponseWriter, member *members.Member) {
+	// Create the MFA token.
+	token, err := ac.Services.Members.NewMFAChallenge(member.ID, time.Minute*ac.Config.MFAExpiryTime)
+	if err != nil {
+		ac.Logger.Printf("members.NewMFAChallenge() service error: %s\n", err)
+		errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+		return
+	}
+
+	// Create a new Result.
+	result := ResultChallenge{
+		Data: &Challenge{
+			MFARequired: true,
+			MFAToken:    token,
+			ExpiresIn:   int(time.Minute * ac.Config.MFAExpiryTime / time.Second),
+		},
+	}
+
+	// Render output.
+	if err := render.JSON(w, true, result); err != nil {
+		ac.Logger.Printf("render.JSON() error: %s\n", err)
+		errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+		return
+	}
+}
+
+// HandleMFA handles the /api/v1/login/mfa POST route of the API.
+//
+// This finishes logging in a member with two-factor authentication, given
+// the MFA token of the challenge and either a TOTP code or a recovery code.
+func HandleMFA(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params members.LoginMFAParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Set the client IP.
+		params.IP = ratelimit.ClientIP(r)
+
+		// Try to log this member in.
+		member, err := ac.Services.Members.LoginMFA(&params)
+		if te, ok := err.(*throttle.Error); ok && err != nil {
+			handleThrottled(ac, w, te)
+			return
+		} else if err == members.ErrSuspended {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err == members.ErrMFATokenInvalid || err == members.ErrCodeInvalid {
+			errors.Default(ac.Logger, w, errors.New(http.StatusUnauthorized, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.LoginMFA() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Start a new session for this member.
+		tokens, err := auth.NewTokens(ac, member)
 		if err != nil {
-			ac.Logger.Printf("auth.NewJWT() error: %s\n", err)
+			ac.Logger.Printf("auth.NewTokens() error: %s\n", err)
 			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
 			return
 		}
 
 		// Create a new Result.
 		result := ResultPost{
-			Data: token,
+			Data: tokens,
 		}
 
 		// Render output.
@@ -70,3 +192,19 @@ func HandlePost(ac *apictx.Context) http.HandlerFunc {
 		}
 	}
 }
+
+// handleThrottled renders the error of a login attempt that has to wait,
+// which is 423 Locked when the account is locked out and 429 Too Many
+// Requests otherwise. The Retry-After header gives the number of seconds
+// to wait.
+func handleThrottled(ac *apictx.Context, w http.ResponseWriter, te *throttle.Error) {
+	// Set the Retry-After header.
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(te.RetryAfter.Seconds()))))
+
+	status := http.StatusTooManyRequests
+	if te.Locked {
+		status = http.StatusLocked
+	}
+
+	errors.Default(ac.Logger, w, errors.New(status, "", te.Error()))
+}
@@ -0,0 +1,9 @@
+package logout
+
+import "errors"
+
+var (
+	// ErrNoSession is returned when logging out of a request which was not
+	// authenticated using a JWT issued with a session.
+	ErrNoSession = errors.New("Request is not authenticated with a session, use /api/v1/logout/all to log out everywhere")
+)
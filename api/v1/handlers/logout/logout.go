@@ -0,0 +1,68 @@
+package logout
+
+import (
+	"net/http"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/ratelimit"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// New creates the routes for the logout endpoints of the API.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.POST("/api/v1/logout", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandlePost(ac))))
+	router.POST("/api/v1/logout/all", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandlePostAll(ac))))
+}
+
+// HandlePost handles the /api/v1/logout POST route of the API.
+//
+// The session of the JWT used for the request is ended, revoking its
+// refresh tokens along with the JWT itself.
+func HandlePost(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get the session from the request context.
+		sid := auth.GetSessionFromRequest(r)
+		if sid == "" {
+			errors.Default(ac.Logger, w, errors.New(http.StatusBadRequest, "", ErrNoSession.Error()))
+			return
+		}
+
+		// Try to revoke this session.
+		if err := ac.Services.Tokens.RevokeByFamily(sid); err != nil {
+			ac.Logger.Printf("tokens.RevokeByFamily() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandlePostAll handles the /api/v1/logout/all POST route of the API.
+//
+// Every session of the member is ended. API keys are not affected.
+func HandlePostAll(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to revoke every session of this member.
+		if err := ac.Services.Tokens.RevokeByMemberID(member.ID); err != nil {
+			ac.Logger.Printf("tokens.RevokeByMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
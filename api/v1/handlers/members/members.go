@@ -0,0 +1,495 @@
+package members
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/ratelimit"
+	"gotodo/api/render"
+	serverrors "gotodo/services/errors"
+	"gotodo/services/members"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// ResultPassword defines the response data for the HandlePassword handler.
+type ResultPassword struct {
+	Data *auth.Tokens `json:"data"`
+}
+
+// ResultMember defines the response data for the HandleGet, HandlePatch,
+// HandleDelete, HandleVerify and HandleEmail handlers.
+type ResultMember struct {
+	Data *members.Member `json:"data"`
+}
+
+// ResultTOTP defines the response data for the HandleTOTP handler.
+type ResultTOTP struct {
+	Data *members.TOTPEnrollment `json:"data"`
+}
+
+// RecoveryCodes defines the recovery codes given once two-factor
+// authentication is enabled.
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ResultTOTPConfirm defines the response data for the HandleTOTPConfirm
+// handler.
+type ResultTOTPConfirm struct {
+	Data *RecoveryCodes `json:"data"`
+}
+
+// New creates the routes for the member endpoints of the API.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.GET("/api/v1/members/me", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandleGet(ac))))
+	router.PATCH("/api/v1/members/me", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandlePatch(ac))))
+	router.DELETE("/api/v1/members/me", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandleDelete(ac))))
+	router.GET("/api/v1/members/verify", ratelimit.LimitEndpoint(ac, ratelimit.GroupAuth, HandleVerify(ac)))
+	router.POST("/api/v1/members/me/password", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandlePassword(ac))))
+	router.POST("/api/v1/members/me/email", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandleEmail(ac))))
+	router.POST("/api/v1/members/me/verification", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandleVerification(ac))))
+	router.POST("/api/v1/members/me/totp", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandleTOTP(ac))))
+	router.POST("/api/v1/members/me/totp/confirm", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandleTOTPConfirm(ac))))
+	router.POST("/api/v1/members/me/totp/disable", auth.AuthenticateEndpoint(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupAccount, HandleTOTPDisable(ac))))
+}
+
+// HandlePassword handles the /api/v1/members/me/password POST route of the
+// API.
+//
+// Changing the password logs the member out everywhere, so a new session is
+// started and its tokens are returned.
+func HandlePassword(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params members.ChangePasswordParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to change the password.
+		member, err = ac.Services.Members.ChangePassword(member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.ChangePassword() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Start a new session for this member.
+		tokens, err := auth.NewTokens(ac, member)
+		if err != nil {
+			ac.Logger.Printf("auth.NewTokens() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultPassword{
+			Data: tokens,
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleGet handles the /api/v1/members/me GET route of the API.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultMember{
+			Data: member,
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandlePatch handles the /api/v1/members/me PATCH route of the API.
+//
+// Changing the email requires the password, and a verification email is
+// sent to the new email.
+func HandlePatch(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params members.UpdateProfileParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+		email := member.Email
+
+		// Try to update the profile.
+		member, err = ac.Services.Members.UpdateProfile(member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.UpdateProfile() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send the verification email.
+		if member.Email != email {
+			sendVerification(ac, member)
+		}
+
+		// Create a new Result.
+		result := ResultMember{
+			Data: member,
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleDelete handles the /api/v1/members/me DELETE route of the API.
+//
+// This closes the account, which requires the password, and a code when
+// two-factor authentication is enabled. With a grace period the account is
+// returned along with when it will be deleted, and the member is logged out
+// everywhere. Otherwise it is deleted right away. The last owner of a
+// workspace with other members cannot close their account.
+func HandleDelete(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params members.CloseParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to close the account.
+		closed, err := ac.Services.Members.Close(member.ID, &params, time.Minute*ac.Config.DeleteGraceTime)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == members.ErrLastOwner {
+			errors.Default(ac.Logger, w, errors.New(http.StatusConflict, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.Close() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response if the account was deleted.
+		if closed == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultMember{
+			Data: closed,
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleVerify handles the /api/v1/members/verify GET route of the API.
+//
+// This is the link sent to verify an email, with the verification token
+// given as the token query parameter.
+func HandleVerify(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Create the parameters from the query string.
+		params := members.VerifyParams{
+			Token: r.URL.Query().Get("token"),
+		}
+
+		// Try to verify the email.
+		member, err := ac.Services.Members.Verify(&params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.Verify() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultMember{
+			Data: member,
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleEmail handles the /api/v1/members/me/email POST route of the API.
+//
+// The new email must be verified again, so a verification email is sent to
+// it. The email can also be changed with the HandlePatch handler.
+func HandleEmail(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params members.ChangeEmailParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+		email := member.Email
+
+		// Try to change the email.
+		member, err = ac.Services.Members.ChangeEmail(member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.ChangeEmail() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send the verification email.
+		if member.Email != email {
+			sendVerification(ac, member)
+		}
+
+		// Create a new Result.
+		result := ResultMember{
+			Data: member,
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleVerification handles the /api/v1/members/me/verification POST route
+// of the API, sending the verification email again.
+func HandleVerification(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to send the verification email.
+		err = ac.Services.Members.SendVerification(member.ID, ac.Config.VerifyURL, time.Minute*ac.Config.VerifyExpiryTime)
+		if err == members.ErrEmailVerified {
+			errors.Default(ac.Logger, w, errors.New(http.StatusBadRequest, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.SendVerification() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleTOTP handles the /api/v1/members/me/totp POST route of the API.
+//
+// This starts enrolling the member in two-factor authentication, returning
+// the TOTP secret to add to their authenticator app. Enrolling again before
+// confirming replaces the secret.
+func HandleTOTP(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to enroll this member.
+		enrollment, err := ac.Services.Members.EnrollTOTP(member.ID)
+		if err == members.ErrTOTPEnabled {
+			errors.Default(ac.Logger, w, errors.New(http.StatusBadRequest, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.EnrollTOTP() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultTOTP{
+			Data: enrollment,
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleTOTPConfirm handles the /api/v1/members/me/totp/confirm POST route
+// of the API.
+//
+// This enables two-factor authentication given the first code of the
+// authenticator app, returning the recovery codes of the member.
+func HandleTOTPConfirm(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params members.ConfirmTOTPParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to enable two-factor authentication.
+		codes, err := ac.Services.Members.ConfirmTOTP(member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == members.ErrTOTPEnabled || err == members.ErrTOTPNotEnrolled {
+			errors.Default(ac.Logger, w, errors.New(http.StatusBadRequest, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.ConfirmTOTP() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultTOTPConfirm{
+			Data: &RecoveryCodes{
+				RecoveryCodes: codes,
+			},
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleTOTPDisable handles the /api/v1/members/me/totp/disable POST route
+// of the API.
+func HandleTOTPDisable(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params members.DisableTOTPParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to disable two-factor authentication.
+		err = ac.Services.Members.DisableTOTP(member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == members.ErrTOTPNotEnabled {
+			errors.Default(ac.Logger, w, errors.New(http.StatusBadRequest, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.DisableTOTP() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// sendVerification sends the verification email to the given member. The
+// member can ask for it again, so failing to send it is only logged.
+func sendVerification(ac *apictx.Context, member *members.Member) {
+	if err := ac.Services.Members.SendVerification(member.ID, ac.Config.VerifyURL, time.Minute*ac.Config.VerifyExpiryTime); err != nil {
+		ac.Logger.Printf("members.SendVerification() service error: %s\n", err)
+	}
+}
@@ -0,0 +1,79 @@
+package password
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/ratelimit"
+	serverrors "gotodo/services/errors"
+	"gotodo/services/members"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// New creates the routes for the password reset endpoints of the API.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.POST("/api/v1/password/forgot", ratelimit.LimitEndpoint(ac, ratelimit.GroupAuth, HandleForgot(ac)))
+	router.POST("/api/v1/password/reset", ratelimit.LimitEndpoint(ac, ratelimit.GroupAuth, HandleReset(ac)))
+}
+
+// HandleForgot handles the /api/v1/password/forgot POST route of the API.
+//
+// A password reset token is emailed to the member with the given email. The
+// response is the same whether or not such a member exists.
+func HandleForgot(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params members.ForgotPasswordParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to send a password reset token.
+		err := ac.Services.Members.ForgotPassword(&params, time.Minute*ac.Config.ResetExpiryTime)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.ForgotPassword() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleReset handles the /api/v1/password/reset POST route of the API.
+//
+// Resetting the password logs the member out everywhere.
+func HandleReset(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params members.ResetPasswordParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to reset the password.
+		_, err := ac.Services.Members.ResetPassword(&params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.ResetPassword() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,262 @@
+package series
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/ratelimit"
+	"gotodo/api/render"
+	serverrors "gotodo/services/errors"
+	servtodos "gotodo/services/todos"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// Series defines the recurring todo series API type.
+//
+// This mirrors the service Series type, which mirrors the database Series
+// type. However, we specify that the MemberID should not be included when
+// encoding to JSON.
+type Series struct {
+	ID          int        `json:"id"`
+	MemberID    int        `json:"-"`
+	Created     time.Time  `json:"created"`
+	Rule        string     `json:"rule"`
+	StartsAt    time.Time  `json:"starts_at"`
+	Occurrences int        `json:"occurrences"`
+	LatestID    int        `json:"latest_id"`
+	StoppedAt   *time.Time `json:"stopped_at"`
+}
+
+// ResultGet defines the response data for the HandleGet handler.
+type ResultGet struct {
+	Data []*Series `json:"data"`
+}
+
+// ResultGetSeries defines the response data for the HandleGetSeries handler.
+type ResultGetSeries struct {
+	Data *Series `json:"data"`
+}
+
+// ResultUpdate defines the response data for the HandleUpdate handler.
+type ResultUpdate struct {
+	Data *Series `json:"data"`
+}
+
+// ResultStop defines the response data for the HandleStop handler.
+type ResultStop struct {
+	Data *Series `json:"data"`
+}
+
+// New creates the routes for the recurring todo series endpoints of the
+// API.
+//
+// Series are started by giving a recurrence rule when creating or updating
+// a todo through the todos endpoints.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.GET("/api/v1/series", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, HandleGet(ac))))
+	router.GET("/api/v1/series/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, HandleGetSeries(ac))))
+	router.POST("/api/v1/series/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleUpdate(ac)))))
+	router.POST("/api/v1/series/:id/stop", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleStop(ac)))))
+}
+
+// HandleGet handles the /api/v1/series GET route of the API.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get the series.
+		set, err := ac.Services.Todos.GetSeriesByMemberID(member.ID)
+		if err != nil {
+			ac.Logger.Printf("todos.GetSeriesByMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultGet{
+			Data: []*Series{},
+		}
+
+		// Loop through the series.
+		for _, s := range set.Series {
+			result.Data = append(result.Data, newSeries(s))
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleGetSeries handles the /api/v1/series/:id GET route of the API.
+func HandleGetSeries(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the series ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get this series.
+		series, err := ac.Services.Todos.GetSeriesByIDAndMemberID(id, member.ID)
+		if err == servtodos.ErrSeriesNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.GetSeriesByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultGetSeries{
+			Data: newSeries(series),
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleUpdate handles the /api/v1/series/:id POST route of the API.
+func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servtodos.UpdateSeriesParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the series ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to update this series.
+		series, err := ac.Services.Todos.UpdateSeriesByIDAndMemberID(id, member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servtodos.ErrSeriesNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.UpdateSeriesByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultUpdate{
+			Data: newSeries(series),
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleStop handles the /api/v1/series/:id/stop POST route of the API.
+//
+// Stopping a series keeps its existing todos, no more are created.
+func HandleStop(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the series ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to stop this series.
+		series, err := ac.Services.Todos.StopSeriesByIDAndMemberID(id, member.ID)
+		if err == servtodos.ErrSeriesNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.StopSeriesByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultStop{
+			Data: newSeries(series),
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// newSeries copies the given service Series type over to the API type.
+func newSeries(s *servtodos.Series) *Series {
+	return &Series{
+		ID:          s.ID,
+		MemberID:    s.MemberID,
+		Created:     s.Created,
+		Rule:        s.Rule,
+		StartsAt:    s.StartsAt,
+		Occurrences: s.Occurrences,
+		LatestID:    s.LatestID,
+		StoppedAt:   s.StoppedAt,
+	}
+}
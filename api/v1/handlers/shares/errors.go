@@ -0,0 +1,9 @@
+package shares
+
+import "errors"
+
+var (
+	// ErrAcceptedInvalid is returned when the accepted parameter is
+	// invalid.
+	ErrAcceptedInvalid = errors.New("Accepted parameter is invalid, must be a boolean")
+)
@@ -0,0 +1,421 @@
+package shares
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/ratelimit"
+	"gotodo/api/render"
+	serverrors "gotodo/services/errors"
+	"gotodo/services/shares"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// ResultGet defines the response data for the handlers returning a set of
+// shares.
+type ResultGet struct {
+	Data []*shares.Share `json:"data"`
+}
+
+// ResultShare defines the response data for the handlers returning a
+// share.
+type ResultShare struct {
+	Data *shares.Share `json:"data"`
+}
+
+// New creates the routes for the share endpoints of the API.
+//
+// Lists and todos are shared by their owner, and the member they are shared
+// with accepts or declines the invitation.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.GET("/api/v1/shares", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, HandleGet(ac))))
+	router.POST("/api/v1/shares/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleUpdate(ac)))))
+	router.DELETE("/api/v1/shares/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleDelete(ac)))))
+	router.POST("/api/v1/shares/:id/accept", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleAccept(ac)))))
+	router.POST("/api/v1/shares/:id/decline", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleDecline(ac)))))
+	router.GET("/api/v1/lists/:id/shares", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, HandleGetList(ac))))
+	router.POST("/api/v1/lists/:id/shares", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandlePostList(ac)))))
+	router.GET("/api/v1/todos/:id/shares", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, HandleGetTodo(ac))))
+	router.POST("/api/v1/todos/:id/shares", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandlePostTodo(ac)))))
+}
+
+// HandleGet handles the /api/v1/shares GET route of the API, which lists the
+// shares given to the member.
+//
+// The pending invitations are listed with ?accepted=false.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new GetParams.
+		params := &shares.GetParams{}
+
+		// Handle accepted.
+		if acceptedqs, ok := r.URL.Query()["accepted"]; ok && len(acceptedqs) == 1 {
+			accepted, err := strconv.ParseBool(acceptedqs[0])
+			if err != nil {
+				errors.Default(ac.Logger, w, errors.New(http.StatusBadRequest, "accepted", ErrAcceptedInvalid.Error()))
+				return
+			}
+			params.Accepted = &accepted
+		}
+
+		// Try to get the shares.
+		ss, err := ac.Services.Shares.GetByMemberID(member.ID, params)
+		if err != nil {
+			ac.Logger.Printf("shares.GetByMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderShares(ac, w, ss)
+	}
+}
+
+// HandleGetList handles the /api/v1/lists/:id/shares GET route of the API.
+func HandleGetList(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the list ID.
+		id, ok := getID(ac, w, r)
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get the shares.
+		ss, err := ac.Services.Shares.GetByListID(id, member.ID)
+		if err == shares.ErrListNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("shares.GetByListID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderShares(ac, w, ss)
+	}
+}
+
+// HandleGetTodo handles the /api/v1/todos/:id/shares GET route of the API.
+func HandleGetTodo(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the todo ID.
+		id, ok := getID(ac, w, r)
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get the shares.
+		ss, err := ac.Services.Shares.GetByTodoID(id, member.ID)
+		if err == shares.ErrTodoNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("shares.GetByTodoID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderShares(ac, w, ss)
+	}
+}
+
+// HandlePostList handles the /api/v1/lists/:id/shares POST route of the API.
+//
+// The same response is sent whether or not the email belongs to a member,
+// so it cannot be used to find out which emails are used.
+func HandlePostList(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params shares.NewParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the list ID.
+		id, ok := getID(ac, w, r)
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to share this list.
+		err = ac.Services.Shares.ShareList(id, member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == shares.ErrListNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("shares.ShareList() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandlePostTodo handles the /api/v1/todos/:id/shares POST route of the API.
+//
+// The same response is sent whether or not the email belongs to a member,
+// so it cannot be used to find out which emails are used.
+func HandlePostTodo(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params shares.NewParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the todo ID.
+		id, ok := getID(ac, w, r)
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to share this todo.
+		err = ac.Services.Shares.ShareTodo(id, member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == shares.ErrTodoNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("shares.ShareTodo() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleUpdate handles the /api/v1/shares/:id POST route of the API, which
+// changes the permission of a share.
+func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params shares.UpdateParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the share ID.
+		id, ok := getID(ac, w, r)
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to update this share.
+		share, err := ac.Services.Shares.UpdateByIDAndOwnerID(id, member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == shares.ErrShareNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("shares.UpdateByIDAndOwnerID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderShare(ac, w, share)
+	}
+}
+
+// HandleDelete handles the /api/v1/shares/:id DELETE route of the API,
+// which either revokes a share or leaves it.
+func HandleDelete(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the share ID.
+		id, ok := getID(ac, w, r)
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to delete this share.
+		if err := ac.Services.Shares.DeleteByIDAndMemberID(id, member.ID); err == shares.ErrShareNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("shares.DeleteByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleAccept handles the /api/v1/shares/:id/accept POST route of the API.
+func HandleAccept(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the share ID.
+		id, ok := getID(ac, w, r)
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to accept this invitation.
+		share, err := ac.Services.Shares.Accept(id, member.ID)
+		if err == shares.ErrShareNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == shares.ErrShareAccepted {
+			errors.Default(ac.Logger, w, errors.New(http.StatusConflict, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("shares.Accept() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderShare(ac, w, share)
+	}
+}
+
+// HandleDecline handles the /api/v1/shares/:id/decline POST route of the
+// API.
+func HandleDecline(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the share ID.
+		id, ok := getID(ac, w, r)
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to decline this invitation.
+		if err := ac.Services.Shares.Decline(id, member.ID); err == shares.ErrShareNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == shares.ErrShareAccepted {
+			errors.Default(ac.Logger, w, errors.New(http.StatusConflict, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("shares.Decline() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// getID gets the ID from the path of the given request, rendering an error
+// if it is invalid.
+func getID(ac *apictx.Context, w http.ResponseWriter, r *http.Request) (int, bool) {
+	id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+	if err != nil {
+		errors.Default(ac.Logger, w, errors.ErrBadRequest)
+		return 0, false
+	}
+
+	return int(id64), true
+}
+
+// renderShares renders the given set of shares.
+func renderShares(ac *apictx.Context, w http.ResponseWriter, ss *shares.Shares) {
+	// Create a new Result.
+	result := ResultGet{
+		Data: ss.Shares,
+	}
+
+	// Render output.
+	if err := render.JSON(w, true, result); err != nil {
+		ac.Logger.Printf("render.JSON() error: %s\n", err)
+		errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+		return
+	}
+}
+
+// renderShare renders the given share.
+func renderShare(ac *apictx.Context, w http.ResponseWriter, share *shares.Share) {
+	// Create a new Result.
+	result := ResultShare{
+		Data: share,
+	}
+
+	// Render output.
+	if err := render.JSON(w, true, result); err != nil {
+		ac.Logger.Printf("render.JSON() error: %s\n", err)
+		errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+		return
+	}
+}
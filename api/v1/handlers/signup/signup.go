@@ -3,10 +3,12 @@ package signup
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	apictx "gotodo/api/context"
 	"gotodo/api/errors"
 	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/ratelimit"
 	"gotodo/api/render"
 	serverrors "gotodo/services/errors"
 	"gotodo/services/members"
@@ -16,13 +18,13 @@ import (
 
 // ResultPost defines the response data for the HandlePost handler.
 type ResultPost struct {
-	Data string `json:"data"`
+	Data *auth.Tokens `json:"data"`
 }
 
 // New creates the routes for the signup endpoints of the API.
 func New(ac *apictx.Context, router *httprouter.Router) {
 	// Handle the routes.
-	router.POST("/api/v1/signup", HandlePost(ac))
+	router.POST("/api/v1/signup", ratelimit.LimitEndpoint(ac, ratelimit.GroupAuth, HandlePost(ac)))
 }
 
 // HandlePost handles the /api/v1/signup POST route of the API.
@@ -46,17 +48,23 @@ func HandlePost(ac *apictx.Context) http.HandlerFunc {
 			return
 		}
 
-		// Issue a new JWT for this member.
-		token, err := auth.NewJWT(ac, member.Password, member.ID)
+		// Send the verification email. The member can ask for
+		// it again, so failing to send it is only logged.
+		if err := ac.Services.Members.SendVerification(member.ID, ac.Config.VerifyURL, time.Minute*ac.Config.VerifyExpiryTime); err != nil {
+			ac.Logger.Printf("members.SendVerification() service error: %s\n", err)
+		}
+
+		// Start a new session for this member.
+		tokens, err := auth.NewTokens(ac, member)
 		if err != nil {
-			ac.Logger.Printf("auth.NewJWT() error: %s\n", err)
+			ac.Logger.Printf("auth.NewTokens() error: %s\n", err)
 			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
 			return
 		}
 
 		// Create a new Result.
 		result := ResultPost{
-			Data: token,
+			Data: tokens,
 		}
 
 		// Render output.
@@ -0,0 +1,254 @@
+package tags
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/ratelimit"
+	"gotodo/api/render"
+	serverrors "gotodo/services/errors"
+	servtodos "gotodo/services/todos"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// Tag defines the tag API type.
+//
+// This mirrors the service Tag type, which mirrors the database Tag type.
+// However, we specify that the MemberID should not be included when encoding
+// to JSON.
+type Tag struct {
+	ID       int    `json:"id"`
+	MemberID int    `json:"-"`
+	Name     string `json:"name"`
+	Todos    int    `json:"todos"`
+}
+
+// ResultGet defines the response data for the HandleGet handler.
+type ResultGet struct {
+	Data []*Tag `json:"data"`
+}
+
+// ResultUpdate defines the response data for the HandleUpdate handler.
+type ResultUpdate struct {
+	Data *Tag `json:"data"`
+}
+
+// ResultMerge defines the response data for the HandleMerge handler.
+type ResultMerge struct {
+	Data *Tag `json:"data"`
+}
+
+// New creates the routes for the tag endpoints of the API.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.GET("/api/v1/tags", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, HandleGet(ac))))
+	router.POST("/api/v1/tags/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleUpdate(ac)))))
+	router.POST("/api/v1/tags/:id/merge", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleMerge(ac)))))
+	router.DELETE("/api/v1/tags/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleDelete(ac)))))
+}
+
+// HandleGet handles the /api/v1/tags GET route of the API.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get the tags.
+		tags, err := ac.Services.Todos.GetTagsByMemberID(member.ID)
+		if err != nil {
+			ac.Logger.Printf("todos.GetTagsByMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultGet{
+			Data: []*Tag{},
+		}
+
+		// Loop through the tags.
+		for _, t := range tags.Tags {
+			// Copy the Tag type over.
+			tag := &Tag{
+				ID:       t.ID,
+				MemberID: t.MemberID,
+				Name:     t.Name,
+				Todos:    t.Todos,
+			}
+
+			result.Data = append(result.Data, tag)
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleUpdate handles the /api/v1/tags/:id POST route of the API.
+func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servtodos.UpdateTagParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the tag ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to rename this tag.
+		tag, err := ac.Services.Todos.UpdateTagByIDAndMemberID(id, member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servtodos.ErrTagNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.UpdateTagByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultUpdate{
+			Data: &Tag{
+				ID:       tag.ID,
+				MemberID: tag.MemberID,
+				Name:     tag.Name,
+				Todos:    tag.Todos,
+			},
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleMerge handles the /api/v1/tags/:id/merge POST route of the API.
+//
+// The tags with the IDs given in the request body are merged into the tag
+// with the ID given in the path.
+func HandleMerge(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servtodos.MergeTagsParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the tag ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to merge the tags.
+		tag, err := ac.Services.Todos.MergeTagsByIDAndMemberID(id, member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servtodos.ErrTagNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.MergeTagsByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultMerge{
+			Data: &Tag{
+				ID:       tag.ID,
+				MemberID: tag.MemberID,
+				Name:     tag.Name,
+				Todos:    tag.Todos,
+			},
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleDelete handles the /api/v1/tags/:id DELETE route of the API.
+func HandleDelete(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the tag ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to delete this tag.
+		err = ac.Services.Todos.DeleteTagByIDAndMemberID(id, member.ID)
+		if err == servtodos.ErrTagNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.DeleteTagByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,117 @@
+package todos
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/workspace"
+	"gotodo/api/render"
+	serverrors "gotodo/services/errors"
+	servtodos "gotodo/services/todos"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// ResultAssign defines the response data for the HandleAssign handler.
+type ResultAssign struct {
+	Data *Todo `json:"data"`
+}
+
+// HandleAssign handles the /api/v1/todos/:id/assignee POST route of the API.
+func HandleAssign(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servtodos.AssignParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the todo ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to assign this todo.
+		todo, err := ac.Services.Todos.AssignByIDAndMemberID(id, member.ID, workspace.GetWorkspaceFromRequest(r), &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servtodos.ErrTodoNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servtodos.ErrTodoReadOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.AssignByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultAssign{
+			Data: newTodo(todo),
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleUnassign handles the /api/v1/todos/:id/assignee DELETE route of the
+// API.
+func HandleUnassign(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the todo ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to unassign this todo.
+		if err := ac.Services.Todos.UnassignByIDAndMemberID(id, member.ID, workspace.GetWorkspaceFromRequest(r)); err == servtodos.ErrTodoNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servtodos.ErrTodoReadOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.UnassignByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,343 @@
+package todos
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/workspace"
+	"gotodo/api/render"
+	serverrors "gotodo/services/errors"
+	servtodos "gotodo/services/todos"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// uploadMemory is the number of bytes of an upload kept in memory, the rest
+// is written to temporary files.
+const uploadMemory = 1 << 20
+
+// sniffLength is the number of bytes used to detect the type of a file.
+const sniffLength = 512
+
+// Attachment defines the attachment API type.
+//
+// This mirrors the service Attachment type. However, we specify that the
+// Key of the blob holding the bytes should not be included when encoding to
+// JSON.
+type Attachment struct {
+	ID          int       `json:"id"`
+	TodoID      int       `json:"todo_id"`
+	MemberID    int       `json:"member_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Key         string    `json:"-"`
+	Created     time.Time `json:"created"`
+}
+
+// ResultGetAttachments defines the response data for the
+// HandleGetAttachments handler.
+type ResultGetAttachments struct {
+	Data []*Attachment `json:"data"`
+}
+
+// ResultPostAttachment defines the response data for the
+// HandlePostAttachment handler.
+type ResultPostAttachment struct {
+	Data *Attachment `json:"data"`
+}
+
+// HandleGetAttachments handles the /api/v1/todos/:id/attachments GET route
+// of the API.
+func HandleGetAttachments(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the todo ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get the attachments.
+		attachments, err := ac.Services.Todos.GetAttachmentsByTodoID(id, member.ID, workspace.GetWorkspaceFromRequest(r))
+		if err == servtodos.ErrTodoNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.GetAttachmentsByTodoID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultGetAttachments{
+			Data: []*Attachment{},
+		}
+
+		// Loop through the attachments.
+		for _, a := range attachments.Attachments {
+			result.Data = append(result.Data, (*Attachment)(a))
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandlePostAttachment handles the /api/v1/todos/:id/attachments POST route
+// of the API.
+//
+// The file is uploaded as multipart/form-data in the file field. Its type is
+// detected from its bytes, and must be one of the attachment types allowed
+// by the configuration.
+func HandlePostAttachment(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the todo ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Check the member can edit the todo
+		// before reading the upload.
+		if err := ac.Services.Todos.CanEditByIDAndMemberID(id, member.ID, workspace.GetWorkspaceFromRequest(r)); err == servtodos.ErrTodoNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servtodos.ErrTodoReadOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.CanEditByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create the error returned for files
+		// over the maximum size.
+		errTooLarge := errors.New(http.StatusRequestEntityTooLarge, "file", fmt.Sprintf("%s, must be %d bytes or less", ErrFileTooLarge, ac.Config.AttachmentMaxSize))
+
+		// Parse the multipart form, allowing room
+		// for the other parts of the form.
+		r.Body = http.MaxBytesReader(w, r.Body, ac.Config.AttachmentMaxSize+uploadMemory)
+		if err := r.ParseMultipartForm(uploadMemory); err != nil {
+			if _, ok := err.(*http.MaxBytesError); ok {
+				errors.Default(ac.Logger, w, errTooLarge)
+				return
+			}
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		// Try to get the file.
+		file, header, err := r.FormFile("file")
+		if err == http.ErrMissingFile {
+			errors.Default(ac.Logger, w, errors.New(http.StatusBadRequest, "file", ErrFileEmpty.Error()))
+			return
+		} else if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		defer file.Close()
+
+		// Check the size of the file.
+		if header.Size > ac.Config.AttachmentMaxSize {
+			errors.Default(ac.Logger, w, errTooLarge)
+			return
+		}
+
+		// Detect the type of the file.
+		contentType, err := detectContentType(file)
+		if err != nil {
+			ac.Logger.Printf("detectContentType() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Check the type of the file.
+		if !allowedType(ac.Config.AttachmentTypes, contentType) {
+			errors.Default(ac.Logger, w, errors.New(http.StatusUnsupportedMediaType, "file", fmt.Sprintf("%s, must be one of %s", ErrFileTypeInvalid, strings.Join(ac.Config.AttachmentTypes, ", "))))
+			return
+		}
+
+		// Try to attach the file.
+		attachment, err := ac.Services.Todos.NewAttachment(id, member.ID, workspace.GetWorkspaceFromRequest(r), &servtodos.NewAttachmentParams{
+			Name:        header.Filename,
+			ContentType: contentType,
+			Size:        header.Size,
+			Body:        file,
+		})
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servtodos.ErrTodoNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servtodos.ErrTodoReadOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.NewAttachment() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultPostAttachment{
+			Data: (*Attachment)(attachment),
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleGetAttachment handles the /api/v1/todos/:id/attachments/:attachment_id
+// GET route of the API, which downloads the file.
+func HandleGetAttachment(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the todo and attachment IDs.
+		id, aid, ok := getIDs(r, "attachment_id")
+		if !ok {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to open this attachment.
+		attachment, rc, err := ac.Services.Todos.OpenAttachmentByIDAndMemberID(aid, id, member.ID, workspace.GetWorkspaceFromRequest(r))
+		if err == servtodos.ErrTodoNotFound || err == servtodos.ErrAttachmentNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.OpenAttachmentByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+		defer rc.Close()
+
+		// Set the headers, always downloading the file
+		// rather than displaying it in the browser.
+		w.Header().Set("Content-Type", attachment.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		// Send the file.
+		if _, err := io.Copy(w, rc); err != nil {
+			ac.Logger.Printf("io.Copy() error: %s\n", err)
+		}
+	}
+}
+
+// HandleDeleteAttachment handles the
+// /api/v1/todos/:id/attachments/:attachment_id DELETE route of the API.
+func HandleDeleteAttachment(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the todo and attachment IDs.
+		id, aid, ok := getIDs(r, "attachment_id")
+		if !ok {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to delete this attachment.
+		if err := ac.Services.Todos.DeleteAttachmentByIDAndMemberID(aid, id, member.ID, workspace.GetWorkspaceFromRequest(r)); err == servtodos.ErrTodoNotFound || err == servtodos.ErrAttachmentNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servtodos.ErrTodoReadOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.DeleteAttachmentByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// detectContentType detects the MIME type of the given file from its first
+// bytes, then rewinds it.
+func detectContentType(file io.ReadSeeker) (string, error) {
+	b := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, b)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	// Drop the parameters of the type, such
+	// as the charset of text files.
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(b[:n]))
+	if err != nil {
+		return "", err
+	}
+
+	return contentType, nil
+}
+
+// allowedType returns whether the given MIME type is one of the given
+// allowed types.
+func allowedType(types []string, contentType string) bool {
+	for _, t := range types {
+		if strings.EqualFold(t, contentType) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,275 @@
+package todos
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/workspace"
+	"gotodo/api/pagination"
+	"gotodo/api/render"
+	serverrors "gotodo/services/errors"
+	servtodos "gotodo/services/todos"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// Comment defines the comment API type.
+//
+// This mirrors the service Comment type. UpdatedAt is only set once the
+// comment has been edited.
+type Comment struct {
+	ID        int        `json:"id"`
+	TodoID    int        `json:"todo_id"`
+	MemberID  int        `json:"member_id"`
+	Body      string     `json:"body"`
+	Created   time.Time  `json:"created"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// ResultGetComments defines the response data for the HandleGetComments
+// handler.
+type ResultGetComments struct {
+	Data  []*Comment       `json:"data"`
+	Meta  pagination.Meta  `json:"meta"`
+	Links pagination.Links `json:"links"`
+}
+
+// ResultComment defines the response data for the HandlePostComment and
+// HandleUpdateComment handlers.
+type ResultComment struct {
+	Data *Comment `json:"data"`
+}
+
+// HandleGetComments handles the /api/v1/todos/:id/comments GET route of the
+// API.
+func HandleGetComments(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Create a new API Errors.
+		errs := &errors.Errors{}
+
+		// Try to get the todo ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Handle offset and limit.
+		page := pagination.Parse(ac.Config, r, errs)
+
+		// Return if there were errors.
+		if errs.Length() > 0 {
+			errors.Multiple(ac.Logger, w, http.StatusBadRequest, errs)
+			return
+		}
+
+		// Try to get the comments.
+		comments, err := ac.Services.Todos.GetCommentsByTodoID(id, member.ID, workspace.GetWorkspaceFromRequest(r), &servtodos.GetCommentsParams{
+			Offset: page.Offset,
+			Limit:  page.Limit,
+		})
+		if err == servtodos.ErrTodoNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.GetCommentsByTodoID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultGetComments{
+			Data: []*Comment{},
+		}
+		result.Meta, result.Links = pagination.New(ac.Config, r, page, comments.Total)
+
+		// Loop through the comments.
+		for _, c := range comments.Comments {
+			result.Data = append(result.Data, (*Comment)(c))
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandlePostComment handles the /api/v1/todos/:id/comments POST route of
+// the API.
+func HandlePostComment(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servtodos.CommentParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the todo ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to create a new comment.
+		comment, err := ac.Services.Todos.NewComment(id, member.ID, workspace.GetWorkspaceFromRequest(r), &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servtodos.ErrTodoNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.NewComment() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultComment{
+			Data: (*Comment)(comment),
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleUpdateComment handles the /api/v1/todos/:id/comments/:comment_id
+// POST route of the API.
+func HandleUpdateComment(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servtodos.CommentParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the todo and comment IDs.
+		id, cid, ok := getIDs(r, "comment_id")
+		if !ok {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to update this comment.
+		comment, err := ac.Services.Todos.UpdateCommentByIDAndMemberID(cid, id, member.ID, workspace.GetWorkspaceFromRequest(r), &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servtodos.ErrTodoNotFound || err == servtodos.ErrCommentNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servtodos.ErrCommentAuthorOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.UpdateCommentByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultComment{
+			Data: (*Comment)(comment),
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleDeleteComment handles the /api/v1/todos/:id/comments/:comment_id
+// DELETE route of the API.
+func HandleDeleteComment(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the todo and comment IDs.
+		id, cid, ok := getIDs(r, "comment_id")
+		if !ok {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to delete this comment.
+		if err := ac.Services.Todos.DeleteCommentByIDAndMemberID(cid, id, member.ID, workspace.GetWorkspaceFromRequest(r)); err == servtodos.ErrTodoNotFound || err == servtodos.ErrCommentNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servtodos.ErrCommentAuthorOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.DeleteCommentByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// getIDs gets the todo ID and the ID in the route parameter with the given
+// name, such as the ID of a comment on the todo, from the request.
+func getIDs(r *http.Request, name string) (int, int, bool) {
+	id, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	sid, err := strconv.ParseInt(httprouter.GetParam(r, name), 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return int(id), int(sid), true
+}
@@ -6,10 +6,40 @@ var (
 	// ErrCreatedInvalid is returned when the created parameter is invalid.
 	ErrCreatedInvalid = errors.New("Created parameter is invalid, must be a datetime string in RFC3339 format")
 
-	// ErrLimitInvalid is returned when the limit parameter is invalid.
-	ErrLimitInvalid = errors.New("Limit parameter is invalid, must be an integer")
+	// ErrDueBeforeInvalid is returned when the due_before parameter is invalid.
+	ErrDueBeforeInvalid = errors.New("Due before parameter is invalid, must be a datetime string in RFC3339 format")
 
-	// ErrLimitMax is returned when the limit parameter is greater than the
-	// maximum allowable limit.
-	ErrLimitMax = errors.New("Limit parameter is greater than maximum allowable limit")
+	// ErrDueAfterInvalid is returned when the due_after parameter is invalid.
+	ErrDueAfterInvalid = errors.New("Due after parameter is invalid, must be a datetime string in RFC3339 format")
+
+	// ErrOverdueInvalid is returned when the overdue parameter is invalid.
+	ErrOverdueInvalid = errors.New("Overdue parameter is invalid, must be a boolean")
+
+	// ErrTagMatchInvalid is returned when the tag_match parameter is invalid.
+	ErrTagMatchInvalid = errors.New("Tag match parameter is invalid, must be either any or all")
+
+	// ErrListIDInvalid is returned when the list_id parameter is invalid.
+	ErrListIDInvalid = errors.New("List ID parameter is invalid, must be an integer or inbox")
+
+	// ErrAssigneeInvalid is returned when the assignee parameter is invalid.
+	ErrAssigneeInvalid = errors.New("Assignee parameter is invalid, must be me, none or a member ID")
+
+	// ErrIncludeInvalid is returned when the include parameter is invalid.
+	ErrIncludeInvalid = errors.New("Include parameter is invalid, must be children")
+
+	// ErrIDInvalid is returned when the id parameter is invalid.
+	ErrIDInvalid = errors.New("ID parameter is invalid, must be an integer")
+
+	// ErrCompletedInvalid is returned when the completed parameter is invalid.
+	ErrCompletedInvalid = errors.New("Completed parameter is invalid, must be a boolean")
+
+	// ErrFileEmpty is returned when no file is uploaded.
+	ErrFileEmpty = errors.New("File parameter is empty, must be a file uploaded as multipart/form-data")
+
+	// ErrFileTooLarge is returned when an uploaded file is too large.
+	ErrFileTooLarge = errors.New("File is too large")
+
+	// ErrFileTypeInvalid is returned when an uploaded file is not of one of
+	// the allowed types.
+	ErrFileTypeInvalid = errors.New("File type is not allowed")
 )
@@ -0,0 +1,156 @@
+package todos
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/workspace"
+	"gotodo/api/pagination"
+	"gotodo/api/render"
+	servtodos "gotodo/services/todos"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// Event defines the todo history event API type.
+//
+// This mirrors the service Event type. Changes holds the previous and new
+// value of each field which changed, by field name, where both are null for
+// fields which did not exist at the time.
+type Event struct {
+	ID       int                          `json:"id"`
+	TodoID   int                          `json:"todo_id"`
+	MemberID int                          `json:"member_id"`
+	Action   string                       `json:"action"`
+	Changes  map[string]*servtodos.Change `json:"changes"`
+	Created  time.Time                    `json:"created"`
+}
+
+// ResultGetHistory defines the response data for the HandleGetHistory
+// handler.
+type ResultGetHistory struct {
+	Data  []*Event         `json:"data"`
+	Meta  pagination.Meta  `json:"meta"`
+	Links pagination.Links `json:"links"`
+}
+
+// ResultRestore defines the response data for the HandleRestore handler.
+type ResultRestore struct {
+	Data *Todo `json:"data"`
+}
+
+// HandleGetHistory handles the /api/v1/todos/:id/history GET route of the
+// API.
+func HandleGetHistory(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Create a new API Errors.
+		errs := &errors.Errors{}
+
+		// Try to get the todo ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Handle offset and limit.
+		page := pagination.Parse(ac.Config, r, errs)
+
+		// Return if there were errors.
+		if errs.Length() > 0 {
+			errors.Multiple(ac.Logger, w, http.StatusBadRequest, errs)
+			return
+		}
+
+		// Try to get the history.
+		history, err := ac.Services.Todos.GetHistoryByTodoID(id, member.ID, workspace.GetWorkspaceFromRequest(r), &servtodos.GetHistoryParams{
+			Offset: page.Offset,
+			Limit:  page.Limit,
+		})
+		if err == servtodos.ErrTodoNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.GetHistoryByTodoID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultGetHistory{
+			Data: []*Event{},
+		}
+		result.Meta, result.Links = pagination.New(ac.Config, r, page, history.Total)
+
+		// Loop through the events.
+		for _, e := range history.Events {
+			result.Data = append(result.Data, (*Event)(e))
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleRestore handles the /api/v1/todos/:id/history/:event_id/restore
+// POST route of the API.
+func HandleRestore(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the todo and event IDs.
+		id, eid, ok := getIDs(r, "event_id")
+		if !ok {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to restore this todo.
+		todo, err := ac.Services.Todos.RestoreByIDAndMemberID(id, eid, member.ID, workspace.GetWorkspaceFromRequest(r))
+		if err == servtodos.ErrTodoNotFound || err == servtodos.ErrEventNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servtodos.ErrTodoReadOnly || err == servtodos.ErrOwnerOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.RestoreByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultRestore{
+			Data: newTodo(todo),
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
@@ -9,8 +9,12 @@ import (
 	apictx "gotodo/api/context"
 	"gotodo/api/errors"
 	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/ratelimit"
+	"gotodo/api/middleware/workspace"
+	"gotodo/api/pagination"
 	"gotodo/api/render"
 	serverrors "gotodo/services/errors"
+	servlists "gotodo/services/lists"
 	servtodos "gotodo/services/todos"
 
 	"github.com/beeker1121/httprouter"
@@ -18,35 +22,42 @@ import (
 
 // Todo defines the todo API type.
 //
-// This mirrors the service Todo type, which mirrors the database Todo type.
-// However, we specify that the MemberID should not be included when encoding
-// to JSON.
+// This mirrors the service Todo type. However, we specify that the MemberID
+// should not be included when encoding to JSON.
+//
+// Owned tells whether the todo belongs to the member or was shared with
+// them, in which case Permission is either viewer or editor.
 type Todo struct {
-	ID        int       `json:"id"`
-	MemberID  int       `json:"-"`
-	Created   time.Time `json:"created"`
-	Detail    string    `json:"detail"`
-	Completed bool      `json:"completed"`
+	ID          int        `json:"id"`
+	MemberID    int        `json:"-"`
+	WorkspaceID *int       `json:"workspace_id"`
+	AssigneeID  *int       `json:"assignee_id"`
+	ListID      *int       `json:"list_id"`
+	ParentID    *int       `json:"parent_id"`
+	SeriesID    *int       `json:"series_id"`
+	Created     time.Time  `json:"created"`
+	Detail      string     `json:"detail"`
+	Completed   bool       `json:"completed"`
+	DueAt       *time.Time `json:"due_at"`
+	RemindAt    *time.Time `json:"remind_at"`
+	Tags        []string   `json:"tags"`
+	Owned       bool       `json:"owned"`
+	Permission  string     `json:"permission"`
 }
 
-// Meta defines the response top level meta object.
-type Meta struct {
-	Offset int `json:"offset"`
-	Limit  int `json:"limit"`
-	Total  int `json:"total"`
+// ResultGet defines the response data for the HandleGet and HandleGetList
+// handlers.
+type ResultGet struct {
+	Data  []*Todo          `json:"data"`
+	Meta  pagination.Meta  `json:"meta"`
+	Links pagination.Links `json:"links"`
 }
 
-// Links defines the response top level links object.
-type Links struct {
-	Prev *string `json:"prev"`
-	Next *string `json:"next"`
-}
-
-// ResultGet defines the response data for the HandleGet handler.
-type ResultGet struct {
-	Data  []*Todo `json:"data"`
-	Meta  Meta    `json:"meta"`
-	Links Links   `json:"links"`
+// Tree defines the todo tree API type, which is a todo along with its
+// subtasks.
+type Tree struct {
+	*Todo
+	Children []*Tree `json:"children"`
 }
 
 // ResultGetTodo defines the response data for the HandleGetTodo handler.
@@ -54,6 +65,12 @@ type ResultGetTodo struct {
 	Data *Todo `json:"data"`
 }
 
+// ResultGetTree defines the response data for the HandleGetTodo handler
+// when the subtasks of the todo are included.
+type ResultGetTree struct {
+	Data *Tree `json:"data"`
+}
+
 // ResultPost defines the response data for the HandlePost handler.
 type ResultPost struct {
 	Data *Todo `json:"data"`
@@ -64,16 +81,46 @@ type ResultUpdate struct {
 	Data *Todo `json:"data"`
 }
 
+// Deleted defines the response data for the delete handlers.
+type Deleted struct {
+	Deleted int `json:"deleted"`
+}
+
+// ResultDelete defines the response data for the HandleDelete and
+// HandleDeleteTodo handlers.
+type ResultDelete struct {
+	Data Deleted `json:"data"`
+}
+
 // New creates the routes for the todo endpoints of the API.
 func New(ac *apictx.Context, router *httprouter.Router) {
 	// Handle the routes.
-	router.GET("/api/v1/todos", auth.AuthenticateEndpoint(ac, HandleGet(ac)))
-	router.GET("/api/v1/todos/:id", auth.AuthenticateEndpoint(ac, HandleGetTodo(ac)))
-	router.POST("/api/v1/todos", auth.AuthenticateEndpoint(ac, HandlePost(ac)))
-	router.POST("/api/v1/todos/:id", auth.AuthenticateEndpoint(ac, HandleUpdate(ac)))
+	router.GET("/api/v1/todos", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, HandleGet(ac)))))
+	router.GET("/api/v1/todos/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, HandleGetTodo(ac)))))
+	router.GET("/api/v1/lists/:id/todos", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, HandleGetList(ac)))))
+	router.POST("/api/v1/todos", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandlePost(ac))))))
+	router.POST("/api/v1/todos/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleUpdate(ac))))))
+	router.DELETE("/api/v1/todos", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleDelete(ac))))))
+	router.DELETE("/api/v1/todos/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleDeleteTodo(ac))))))
+	router.POST("/api/v1/todos/:id/assignee", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleAssign(ac))))))
+	router.DELETE("/api/v1/todos/:id/assignee", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleUnassign(ac))))))
+	router.GET("/api/v1/todos/:id/comments", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, HandleGetComments(ac)))))
+	router.POST("/api/v1/todos/:id/comments", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandlePostComment(ac))))))
+	router.POST("/api/v1/todos/:id/comments/:comment_id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleUpdateComment(ac))))))
+	router.DELETE("/api/v1/todos/:id/comments/:comment_id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleDeleteComment(ac))))))
+	router.GET("/api/v1/todos/:id/attachments", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, HandleGetAttachments(ac)))))
+	router.GET("/api/v1/todos/:id/attachments/:attachment_id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, HandleGetAttachment(ac)))))
+	router.POST("/api/v1/todos/:id/attachments", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandlePostAttachment(ac))))))
+	router.DELETE("/api/v1/todos/:id/attachments/:attachment_id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleDeleteAttachment(ac))))))
+	router.GET("/api/v1/todos/:id/history", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, HandleGetHistory(ac)))))
+	router.POST("/api/v1/todos/:id/history/:event_id/restore", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, workspace.Select(ac, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleRestore(ac))))))
 }
 
 // HandleGet handles the /api/v1/todos GET route of the API.
+//
+// The todos assigned to the member are matched with ?assignee=me, those
+// assigned to another member with ?assignee=<id>, and those which are not
+// assigned to anyone with ?assignee=none.
 func HandleGet(ac *apictx.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Get this member from the request context.
@@ -85,123 +132,195 @@ func HandleGet(ac *apictx.Context) http.HandlerFunc {
 
 		// Create a new GetParams.
 		params := &servtodos.GetParams{
-			MemberID: &member.ID,
+			MemberID:    &member.ID,
+			WorkspaceID: workspace.GetWorkspaceFromRequest(r),
 		}
 
 		// Create a new API Errors.
 		errs := &errors.Errors{}
 
-		// Handle created.
-		if createdqs, ok := r.URL.Query()["created"]; ok && len(createdqs) == 1 {
-			t, err := time.Parse(time.RFC3339, createdqs[0])
-			if err != nil {
-				errs.Add(errors.New(http.StatusBadRequest, "created", ErrCreatedInvalid.Error()))
+		// Handle list ID, where the inbox holds the
+		// todos which are not in any list.
+		if listIDqs, ok := r.URL.Query()["list_id"]; ok && len(listIDqs) == 1 {
+			if listIDqs[0] == "inbox" {
+				params.Inbox = true
+			} else if listID64, err := strconv.ParseInt(listIDqs[0], 10, 32); err != nil {
+				errs.Add(errors.New(http.StatusBadRequest, "list_id", ErrListIDInvalid.Error()))
 			} else {
-				params.Created = &t
+				listID := int(listID64)
+				params.ListID = &listID
 			}
 		}
 
-		// Handle offset.
-		if offsetqs, ok := r.URL.Query()["offset"]; ok && len(offsetqs) == 1 {
-			offset64, err := strconv.ParseInt(offsetqs[0], 10, 32)
-			if err != nil {
-				errs.Add(errors.New(http.StatusBadRequest, "offset", ErrLimitInvalid.Error()))
+		// Handle assignee.
+		if assigneeqs, ok := r.URL.Query()["assignee"]; ok && len(assigneeqs) == 1 {
+			if assigneeqs[0] == "me" {
+				params.AssigneeID = &member.ID
+			} else if assigneeqs[0] == "none" {
+				params.Unassigned = true
+			} else if assignee64, err := strconv.ParseInt(assigneeqs[0], 10, 32); err != nil {
+				errs.Add(errors.New(http.StatusBadRequest, "assignee", ErrAssigneeInvalid.Error()))
 			} else {
-				params.Offset = int(offset64)
+				assignee := int(assignee64)
+				params.AssigneeID = &assignee
 			}
-		} else {
-			params.Offset = 0
 		}
 
-		// Handle limit.
-		if limitqs, ok := r.URL.Query()["limit"]; ok && len(limitqs) == 1 {
-			limit64, err := strconv.ParseInt(limitqs[0], 10, 32)
-			if err != nil {
-				errs.Add(errors.New(http.StatusBadRequest, "limit", ErrLimitInvalid.Error()))
-			} else {
-				if int(limit64) > ac.Config.LimitMax {
-					errs.Add(errors.New(http.StatusBadRequest, "limit", ErrLimitMax.Error()+" of "+strconv.FormatUint(uint64(ac.Config.LimitMax), 10)))
-				} else {
-					params.Limit = int(limit64)
-				}
-			}
-		} else {
-			params.Limit = ac.Config.LimitDefault
-		}
+		// Get the todos.
+		getTodos(ac, w, r, params, errs)
+	}
+}
 
-		// Return if there were errors.
-		if errs.Length() > 0 {
-			errors.Multiple(ac.Logger, w, http.StatusBadRequest, errs)
+// HandleGetList handles the /api/v1/lists/:id/todos GET route of the API.
+func HandleGetList(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the list ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
 			return
 		}
+		id = int(id64)
 
-		// Try to get the todos.
-		todos, err := ac.Services.Todos.Get(params)
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
 		if err != nil {
-			ac.Logger.Printf("todos.Get() service error: %s\n", err)
 			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
 			return
 		}
 
-		// Create a new Result.
-		result := ResultGet{
-			Data: []*Todo{},
-			Meta: Meta{
-				Offset: params.Offset,
-				Limit:  params.Limit,
-				Total:  todos.Total,
-			},
-			Links: Links{},
-		}
-
-		// Loop through the todos.
-		for _, t := range todos.Todos {
-			// Copy the Todo type over.
-			todo := &Todo{
-				ID:        t.ID,
-				MemberID:  t.MemberID,
-				Created:   t.Created,
-				Detail:    t.Detail,
-				Completed: t.Completed,
-			}
+		// Get the workspace from the request context.
+		wid := workspace.GetWorkspaceFromRequest(r)
 
-			result.Data = append(result.Data, todo)
+		// Make sure this list exists.
+		if _, err := ac.Services.Lists.GetByIDAndMemberID(id, member.ID, wid); err == servlists.ErrListNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("lists.GetByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
 		}
 
-		// Handle previous link.
-		if params.Offset > 0 {
-			limitstr := "&limit=" + strconv.FormatInt(int64(params.Limit), 10)
+		// Create a new GetParams.
+		params := &servtodos.GetParams{
+			MemberID:    &member.ID,
+			WorkspaceID: wid,
+			ListID:      &id,
+		}
 
-			offsetstr := "?offset="
-			if params.Offset-params.Limit < 0 {
-				offsetstr += "0"
-			} else {
-				offsetstr += strconv.FormatInt(int64(params.Offset-params.Limit), 10)
-			}
+		// Get the todos.
+		getTodos(ac, w, r, params, &errors.Errors{})
+	}
+}
+
+// getTodos handles the query string filters shared by the routes listing
+// todos, then gets and renders the matching page of todos.
+func getTodos(ac *apictx.Context, w http.ResponseWriter, r *http.Request, params *servtodos.GetParams, errs *errors.Errors) {
+	// Handle created.
+	if createdqs, ok := r.URL.Query()["created"]; ok && len(createdqs) == 1 {
+		t, err := time.Parse(time.RFC3339, createdqs[0])
+		if err != nil {
+			errs.Add(errors.New(http.StatusBadRequest, "created", ErrCreatedInvalid.Error()))
+		} else {
+			params.Created = &t
+		}
+	}
 
-			prev := "https://" + ac.Config.APIHost + "/api/v1/todos" + offsetstr + limitstr
-			result.Links.Prev = &prev
+	// Handle due before.
+	if dueBeforeqs, ok := r.URL.Query()["due_before"]; ok && len(dueBeforeqs) == 1 {
+		t, err := time.Parse(time.RFC3339, dueBeforeqs[0])
+		if err != nil {
+			errs.Add(errors.New(http.StatusBadRequest, "due_before", ErrDueBeforeInvalid.Error()))
+		} else {
+			params.DueBefore = &t
 		}
+	}
 
-		// Handle next link.
-		if params.Offset+params.Limit < todos.Total {
-			offsetstr := "?offset=" + strconv.FormatInt(int64(params.Offset+params.Limit), 10)
-			limitstr := "&limit=" + strconv.FormatInt(int64(params.Limit), 10)
+	// Handle due after.
+	if dueAfterqs, ok := r.URL.Query()["due_after"]; ok && len(dueAfterqs) == 1 {
+		t, err := time.Parse(time.RFC3339, dueAfterqs[0])
+		if err != nil {
+			errs.Add(errors.New(http.StatusBadRequest, "due_after", ErrDueAfterInvalid.Error()))
+		} else {
+			params.DueAfter = &t
+		}
+	}
 
-			next := "https://" + ac.Config.APIHost + "/api/v1/todos" + offsetstr + limitstr
-			result.Links.Next = &next
+	// Handle overdue.
+	if overdueqs, ok := r.URL.Query()["overdue"]; ok && len(overdueqs) == 1 {
+		overdue, err := strconv.ParseBool(overdueqs[0])
+		if err != nil {
+			errs.Add(errors.New(http.StatusBadRequest, "overdue", ErrOverdueInvalid.Error()))
+		} else {
+			params.Overdue = &overdue
 		}
+	}
 
-		// Render output.
-		if err := render.JSON(w, true, result); err != nil {
-			ac.Logger.Printf("render.JSON() error: %s\n", err)
-			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
-			return
+	// Handle tags.
+	params.Tags = r.URL.Query()["tag"]
+
+	// Handle tag match.
+	if tagMatchqs, ok := r.URL.Query()["tag_match"]; ok && len(tagMatchqs) == 1 {
+		switch tagMatchqs[0] {
+		case "any":
+			params.TagsAll = false
+		case "all":
+			params.TagsAll = true
+		default:
+			errs.Add(errors.New(http.StatusBadRequest, "tag_match", ErrTagMatchInvalid.Error()))
 		}
 	}
+
+	// Handle offset and limit.
+	page := pagination.Parse(ac.Config, r, errs)
+	params.Offset = page.Offset
+	params.Limit = page.Limit
+
+	// Return if there were errors.
+	if errs.Length() > 0 {
+		errors.Multiple(ac.Logger, w, http.StatusBadRequest, errs)
+		return
+	}
+
+	// Try to get the todos.
+	todos, err := ac.Services.Todos.Get(params)
+	if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+		errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+		return
+	} else if err == servtodos.ErrWorkspaceNotFound {
+		errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+		return
+	} else if err != nil {
+		ac.Logger.Printf("todos.Get() service error: %s\n", err)
+		errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+		return
+	}
+
+	// Create a new Result.
+	result := ResultGet{
+		Data: []*Todo{},
+	}
+	result.Meta, result.Links = pagination.New(ac.Config, r, page, todos.Total)
+
+	// Loop through the todos.
+	for _, t := range todos.Todos {
+		result.Data = append(result.Data, newTodo(t))
+	}
+
+	// Render output.
+	if err := render.JSON(w, true, result); err != nil {
+		ac.Logger.Printf("render.JSON() error: %s\n", err)
+		errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+		return
+	}
 }
 
 // HandleGetTodo handles the /api/v1/todos/:id GET route of the API.
+//
+// All of the subtasks of the todo are included as a tree when the request
+// has ?include=children.
 func HandleGetTodo(ac *apictx.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Try to get the todo ID.
@@ -220,8 +339,22 @@ func HandleGetTodo(ac *apictx.Context) http.HandlerFunc {
 			return
 		}
 
+		// Get the workspace from the request context.
+		wid := workspace.GetWorkspaceFromRequest(r)
+
+		// Handle include.
+		if includeqs, ok := r.URL.Query()["include"]; ok && len(includeqs) == 1 {
+			if includeqs[0] != "children" {
+				errors.Default(ac.Logger, w, errors.New(http.StatusBadRequest, "include", ErrIncludeInvalid.Error()))
+				return
+			}
+
+			getTree(ac, w, id, member.ID, wid)
+			return
+		}
+
 		// Try to get this todo.
-		todo, err := ac.Services.Todos.GetByIDAndMemberID(id, member.ID)
+		todo, err := ac.Services.Todos.GetByIDAndMemberID(id, member.ID, wid)
 		if err == servtodos.ErrTodoNotFound {
 			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
 			return
@@ -233,13 +366,7 @@ func HandleGetTodo(ac *apictx.Context) http.HandlerFunc {
 
 		// Create a new Result.
 		result := ResultGetTodo{
-			Data: &Todo{
-				ID:        todo.ID,
-				MemberID:  todo.MemberID,
-				Created:   todo.Created,
-				Detail:    todo.Detail,
-				Completed: todo.Completed,
-			},
+			Data: newTodo(todo),
 		}
 
 		// Render output.
@@ -251,6 +378,48 @@ func HandleGetTodo(ac *apictx.Context) http.HandlerFunc {
 	}
 }
 
+// getTree gets and renders a todo along with all of its subtasks.
+func getTree(ac *apictx.Context, w http.ResponseWriter, id, mid int, wid *int) {
+	// Try to get this todo tree.
+	tree, err := ac.Services.Todos.GetTreeByIDAndMemberID(id, mid, wid)
+	if err == servtodos.ErrTodoNotFound || err == servtodos.ErrWorkspaceNotFound {
+		errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+		return
+	} else if err != nil {
+		ac.Logger.Printf("todos.GetTreeByIDAndMemberID() service error: %s\n", err)
+		errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+		return
+	}
+
+	// Create a new Result.
+	result := ResultGetTree{
+		Data: newTree(tree),
+	}
+
+	// Render output.
+	if err := render.JSON(w, true, result); err != nil {
+		ac.Logger.Printf("render.JSON() error: %s\n", err)
+		errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+		return
+	}
+}
+
+// newTree copies the given service todo tree over to the API type.
+func newTree(tree *servtodos.Tree) *Tree {
+	// Copy the Todo type over.
+	t := &Tree{
+		Todo:     newTodo(tree.Todo),
+		Children: []*Tree{},
+	}
+
+	// Copy the subtasks over.
+	for _, child := range tree.Children {
+		t.Children = append(t.Children, newTree(child))
+	}
+
+	return t
+}
+
 // HandlePost handles the /api/v1/todos POST route of the API.
 func HandlePost(ac *apictx.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -269,10 +438,13 @@ func HandlePost(ac *apictx.Context) http.HandlerFunc {
 		}
 
 		// Try to create a new todo.
-		todo, err := ac.Services.Todos.New(member.ID, &params)
+		todo, err := ac.Services.Todos.New(member.ID, workspace.GetWorkspaceFromRequest(r), &params)
 		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
 			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
 			return
+		} else if err == servtodos.ErrWorkspaceNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
 		} else if err != nil {
 			ac.Logger.Printf("todos.New() service error: %s\n", err)
 			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
@@ -281,13 +453,7 @@ func HandlePost(ac *apictx.Context) http.HandlerFunc {
 
 		// Create a new Result.
 		result := ResultPost{
-			Data: &Todo{
-				ID:        todo.ID,
-				MemberID:  todo.MemberID,
-				Created:   todo.Created,
-				Detail:    todo.Detail,
-				Completed: todo.Completed,
-			},
+			Data: newTodo(todo),
 		}
 
 		// Render output.
@@ -326,13 +492,16 @@ func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
 		}
 
 		// Try to update this todo.
-		todo, err := ac.Services.Todos.UpdateByIDAndMemberID(id, member.ID, &params)
+		todo, err := ac.Services.Todos.UpdateByIDAndMemberID(id, member.ID, workspace.GetWorkspaceFromRequest(r), &params)
 		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
 			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
 			return
 		} else if err == servtodos.ErrTodoNotFound {
 			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
 			return
+		} else if err == servtodos.ErrTodoReadOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
 		} else if err != nil {
 			ac.Logger.Printf("todos.New() service error: %s\n", err)
 			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
@@ -341,12 +510,151 @@ func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
 
 		// Create a new Result.
 		result := ResultUpdate{
-			Data: &Todo{
-				ID:        todo.ID,
-				MemberID:  todo.MemberID,
-				Created:   todo.Created,
-				Detail:    todo.Detail,
-				Completed: todo.Completed,
+			Data: newTodo(todo),
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleDelete handles the /api/v1/todos DELETE route of the API.
+//
+// The todos to delete are selected using query string filters, either by
+// a list of IDs, e.g. ?id=1&id=2, or by a filter such as ?completed=true.
+func HandleDelete(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new DeleteParams.
+		params := &servtodos.DeleteParams{}
+
+		// Create a new API Errors.
+		errs := &errors.Errors{}
+
+		// Handle IDs.
+		for _, idqs := range r.URL.Query()["id"] {
+			id64, err := strconv.ParseInt(idqs, 10, 32)
+			if err != nil {
+				errs.Add(errors.New(http.StatusBadRequest, "id", ErrIDInvalid.Error()))
+				break
+			}
+			params.IDs = append(params.IDs, int(id64))
+		}
+
+		// Handle list ID.
+		if listIDqs, ok := r.URL.Query()["list_id"]; ok && len(listIDqs) == 1 {
+			listID64, err := strconv.ParseInt(listIDqs[0], 10, 32)
+			if err != nil {
+				errs.Add(errors.New(http.StatusBadRequest, "list_id", ErrListIDInvalid.Error()))
+			} else {
+				listID := int(listID64)
+				params.ListID = &listID
+			}
+		}
+
+		// Handle created.
+		if createdqs, ok := r.URL.Query()["created"]; ok && len(createdqs) == 1 {
+			t, err := time.Parse(time.RFC3339, createdqs[0])
+			if err != nil {
+				errs.Add(errors.New(http.StatusBadRequest, "created", ErrCreatedInvalid.Error()))
+			} else {
+				params.Created = &t
+			}
+		}
+
+		// Handle completed.
+		if completedqs, ok := r.URL.Query()["completed"]; ok && len(completedqs) == 1 {
+			completed, err := strconv.ParseBool(completedqs[0])
+			if err != nil {
+				errs.Add(errors.New(http.StatusBadRequest, "completed", ErrCompletedInvalid.Error()))
+			} else {
+				params.Completed = &completed
+			}
+		}
+
+		// Return if there were errors.
+		if errs.Length() > 0 {
+			errors.Multiple(ac.Logger, w, http.StatusBadRequest, errs)
+			return
+		}
+
+		// Try to delete the todos.
+		deleted, err := ac.Services.Todos.DeleteByMemberID(member.ID, workspace.GetWorkspaceFromRequest(r), params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servtodos.ErrWorkspaceNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.DeleteByMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultDelete{
+			Data: Deleted{
+				Deleted: deleted,
+			},
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
+
+// HandleDeleteTodo handles the /api/v1/todos/:id DELETE route of the API.
+func HandleDeleteTodo(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the todo ID.
+		var id int
+		id64, err := strconv.ParseInt(httprouter.GetParam(r, "id"), 10, 32)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+		id = int(id64)
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to delete this todo.
+		deleted, err := ac.Services.Todos.DeleteByIDAndMemberID(id, member.ID, workspace.GetWorkspaceFromRequest(r))
+		if err == servtodos.ErrTodoNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servtodos.ErrOwnerOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("todos.DeleteByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultDelete{
+			Data: Deleted{
+				Deleted: deleted,
 			},
 		}
 
@@ -358,3 +666,24 @@ func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
 		}
 	}
 }
+
+// newTodo copies the given service todo over to the API type.
+func newTodo(t *servtodos.Todo) *Todo {
+	return &Todo{
+		ID:          t.ID,
+		MemberID:    t.MemberID,
+		WorkspaceID: t.WorkspaceID,
+		AssigneeID:  t.AssigneeID,
+		ListID:      t.ListID,
+		ParentID:    t.ParentID,
+		SeriesID:    t.SeriesID,
+		Created:     t.Created,
+		Detail:      t.Detail,
+		Completed:   t.Completed,
+		DueAt:       t.DueAt,
+		RemindAt:    t.RemindAt,
+		Tags:        t.Tags,
+		Owned:       t.Permission == servtodos.PermissionOwner,
+		Permission:  t.Permission,
+	}
+}
@@ -0,0 +1,90 @@
+package token
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/ratelimit"
+	"gotodo/api/render"
+	serverrors "gotodo/services/errors"
+	"gotodo/services/members"
+	"gotodo/services/tokens"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// ResultRefresh defines the response data for the HandleRefresh handler.
+type ResultRefresh struct {
+	Data *auth.Tokens `json:"data"`
+}
+
+// New creates the routes for the token endpoints of the API.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.POST("/api/v1/token/refresh", ratelimit.LimitEndpoint(ac, ratelimit.GroupAuth, HandleRefresh(ac)))
+}
+
+// HandleRefresh handles the /api/v1/token/refresh POST route of the API.
+//
+// The refresh token given in the request body is exchanged for a new JWT
+// and the next refresh token of its family. Every refresh token can only
+// be used once.
+func HandleRefresh(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params tokens.RefreshParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to refresh the token.
+		token, secret, err := ac.Services.Tokens.Refresh(&params, time.Minute*ac.Config.RefreshExpiryTime)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == tokens.ErrInvalidToken || err == tokens.ErrTokenReused {
+			errors.Default(ac.Logger, w, errors.New(http.StatusUnauthorized, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("tokens.Refresh() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Get the member owning this token.
+		member, err := ac.Services.Members.GetByID(token.MemberID)
+		if err == members.ErrMemberNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusUnauthorized, "", tokens.ErrInvalidToken.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("members.GetByID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Issue the tokens for this member.
+		t, err := auth.IssueTokens(ac, member, token, secret)
+		if err != nil {
+			ac.Logger.Printf("auth.IssueTokens() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultRefresh{
+			Data: t,
+		}
+
+		// Render output.
+		if err := render.JSON(w, true, result); err != nil {
+			ac.Logger.Printf("render.JSON() error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+	}
+}
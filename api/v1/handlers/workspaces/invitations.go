@@ -0,0 +1,228 @@
+package workspaces
+
+import (
+	"encoding/json"
+	"net/http"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	serverrors "gotodo/services/errors"
+	servworkspaces "gotodo/services/workspaces"
+)
+
+// ResultGetInvitations defines the response data for the handlers listing
+// invitations.
+type ResultGetInvitations struct {
+	Data []*servworkspaces.Invitation `json:"data"`
+}
+
+// ResultInvitation defines the response data for the HandlePostInvitation
+// handler.
+type ResultInvitation struct {
+	Data *servworkspaces.Invitation `json:"data"`
+}
+
+// HandleGetInvitations handles the /api/v1/invitations GET route of the
+// API, which lists the invitations sent to the email of the member.
+func HandleGetInvitations(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get the invitations.
+		is, err := ac.Services.Workspaces.GetInvitationsByMemberID(member.ID)
+		if err != nil {
+			ac.Logger.Printf("workspaces.GetInvitationsByMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderResult(ac, w, ResultGetInvitations{Data: is.Invitations})
+	}
+}
+
+// HandleGetWorkspaceInvitations handles the
+// /api/v1/workspaces/:id/invitations GET route of the API, which lists the
+// pending invitations of a workspace.
+func HandleGetWorkspaceInvitations(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the workspace ID.
+		id, ok := getID(ac, w, r, "id")
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get the invitations.
+		is, err := ac.Services.Workspaces.GetInvitations(id, member.ID)
+		if err == servworkspaces.ErrWorkspaceNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servworkspaces.ErrAdminOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("workspaces.GetInvitations() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderResult(ac, w, ResultGetInvitations{Data: is.Invitations})
+	}
+}
+
+// HandlePostInvitation handles the /api/v1/workspaces/:id/invitations POST
+// route of the API, which invites an email to join a workspace.
+func HandlePostInvitation(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servworkspaces.InviteParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the workspace ID.
+		id, ok := getID(ac, w, r, "id")
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to invite this email.
+		invitation, err := ac.Services.Workspaces.Invite(id, member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servworkspaces.ErrWorkspaceNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servworkspaces.ErrAdminOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("workspaces.Invite() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderResult(ac, w, ResultInvitation{Data: invitation})
+	}
+}
+
+// HandleAccept handles the /api/v1/invitations/:id/accept POST route of the
+// API, returning the workspace the member joined.
+func HandleAccept(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the invitation ID.
+		id, ok := getID(ac, w, r, "id")
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to accept this invitation.
+		workspace, err := ac.Services.Workspaces.AcceptInvitation(id, member.ID)
+		if err == servworkspaces.ErrInvitationNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("workspaces.AcceptInvitation() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderResult(ac, w, ResultWorkspace{Data: workspace})
+	}
+}
+
+// HandleDecline handles the /api/v1/invitations/:id/decline POST route of
+// the API.
+func HandleDecline(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the invitation ID.
+		id, ok := getID(ac, w, r, "id")
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to decline this invitation.
+		if err := ac.Services.Workspaces.DeclineInvitation(id, member.ID); err == servworkspaces.ErrInvitationNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("workspaces.DeclineInvitation() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleDeleteInvitation handles the /api/v1/invitations/:id DELETE route of
+// the API, which lets the owners and admins of a workspace revoke an
+// invitation.
+func HandleDeleteInvitation(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the invitation ID.
+		id, ok := getID(ac, w, r, "id")
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to delete this invitation.
+		if err := ac.Services.Workspaces.DeleteInvitation(id, member.ID); err == servworkspaces.ErrInvitationNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servworkspaces.ErrAdminOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("workspaces.DeleteInvitation() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,387 @@
+package workspaces
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	apictx "gotodo/api/context"
+	"gotodo/api/errors"
+	"gotodo/api/middleware/auth"
+	"gotodo/api/middleware/ratelimit"
+	"gotodo/api/render"
+	serverrors "gotodo/services/errors"
+	servworkspaces "gotodo/services/workspaces"
+
+	"github.com/beeker1121/httprouter"
+)
+
+// ResultGet defines the response data for the HandleGet handler.
+type ResultGet struct {
+	Data []*servworkspaces.Workspace `json:"data"`
+}
+
+// ResultWorkspace defines the response data for the handlers returning a
+// workspace.
+type ResultWorkspace struct {
+	Data *servworkspaces.Workspace `json:"data"`
+}
+
+// ResultGetMembers defines the response data for the HandleGetMembers
+// handler.
+type ResultGetMembers struct {
+	Data []*servworkspaces.Member `json:"data"`
+}
+
+// ResultMember defines the response data for the HandleUpdateMember
+// handler.
+type ResultMember struct {
+	Data *servworkspaces.Member `json:"data"`
+}
+
+// New creates the routes for the workspace endpoints of the API.
+//
+// The todos and lists of a workspace are served by the todos and lists
+// endpoints, either with the X-Workspace-ID header or under the
+// /api/v1/workspaces/:id prefix.
+func New(ac *apictx.Context, router *httprouter.Router) {
+	// Handle the routes.
+	router.GET("/api/v1/workspaces", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, HandleGet(ac))))
+	router.POST("/api/v1/workspaces", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandlePost(ac)))))
+	router.GET("/api/v1/workspaces/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, HandleGetWorkspace(ac))))
+	router.POST("/api/v1/workspaces/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleUpdate(ac)))))
+	router.DELETE("/api/v1/workspaces/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleDelete(ac)))))
+	router.GET("/api/v1/workspaces/:id/members", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, HandleGetMembers(ac))))
+	router.POST("/api/v1/workspaces/:id/members/:member_id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleUpdateMember(ac)))))
+	router.DELETE("/api/v1/workspaces/:id/members/:member_id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleDeleteMember(ac)))))
+	router.GET("/api/v1/workspaces/:id/invitations", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, HandleGetWorkspaceInvitations(ac))))
+	router.POST("/api/v1/workspaces/:id/invitations", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandlePostInvitation(ac)))))
+	router.GET("/api/v1/invitations", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, HandleGetInvitations(ac))))
+	router.DELETE("/api/v1/invitations/:id", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleDeleteInvitation(ac)))))
+	router.POST("/api/v1/invitations/:id/accept", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleAccept(ac)))))
+	router.POST("/api/v1/invitations/:id/decline", auth.AuthenticateVerified(ac, ratelimit.LimitEndpoint(ac, ratelimit.GroupTodos, auth.AuthorizeRoles(ac, auth.WriteRoles, HandleDecline(ac)))))
+}
+
+// HandleGet handles the /api/v1/workspaces GET route of the API, which
+// lists the workspaces the member belongs to.
+func HandleGet(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get the workspaces.
+		ws, err := ac.Services.Workspaces.GetByMemberID(member.ID)
+		if err != nil {
+			ac.Logger.Printf("workspaces.GetByMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Create a new Result.
+		result := ResultGet{
+			Data: ws.Workspaces,
+		}
+
+		renderResult(ac, w, result)
+	}
+}
+
+// HandleGetWorkspace handles the /api/v1/workspaces/:id GET route of the
+// API.
+func HandleGetWorkspace(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the workspace ID.
+		id, ok := getID(ac, w, r, "id")
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get this workspace.
+		workspace, err := ac.Services.Workspaces.GetByIDAndMemberID(id, member.ID)
+		if err == servworkspaces.ErrWorkspaceNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("workspaces.GetByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderResult(ac, w, ResultWorkspace{Data: workspace})
+	}
+}
+
+// HandlePost handles the /api/v1/workspaces POST route of the API, which
+// creates a workspace owned by the member.
+func HandlePost(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servworkspaces.NewParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to create a new workspace.
+		workspace, err := ac.Services.Workspaces.New(member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err != nil {
+			ac.Logger.Printf("workspaces.New() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderResult(ac, w, ResultWorkspace{Data: workspace})
+	}
+}
+
+// HandleUpdate handles the /api/v1/workspaces/:id POST route of the API.
+func HandleUpdate(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servworkspaces.UpdateParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the workspace ID.
+		id, ok := getID(ac, w, r, "id")
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to update this workspace.
+		workspace, err := ac.Services.Workspaces.UpdateByIDAndMemberID(id, member.ID, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servworkspaces.ErrWorkspaceNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servworkspaces.ErrAdminOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("workspaces.UpdateByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderResult(ac, w, ResultWorkspace{Data: workspace})
+	}
+}
+
+// HandleDelete handles the /api/v1/workspaces/:id DELETE route of the API.
+//
+// The lists, todos and invitations of the workspace are deleted along with
+// it.
+func HandleDelete(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the workspace ID.
+		id, ok := getID(ac, w, r, "id")
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to delete this workspace.
+		if err := ac.Services.Workspaces.DeleteByIDAndMemberID(id, member.ID); err == servworkspaces.ErrWorkspaceNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servworkspaces.ErrOwnerOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("workspaces.DeleteByIDAndMemberID() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleGetMembers handles the /api/v1/workspaces/:id/members GET route of
+// the API.
+func HandleGetMembers(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the workspace ID.
+		id, ok := getID(ac, w, r, "id")
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to get the members.
+		ms, err := ac.Services.Workspaces.GetMembers(id, member.ID)
+		if err == servworkspaces.ErrWorkspaceNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("workspaces.GetMembers() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderResult(ac, w, ResultGetMembers{Data: ms.Members})
+	}
+}
+
+// HandleUpdateMember handles the /api/v1/workspaces/:id/members/:member_id
+// POST route of the API, which changes the role of a member.
+func HandleUpdateMember(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse the parameters from the request body.
+		var params servworkspaces.UpdateMemberParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			errors.Default(ac.Logger, w, errors.ErrBadRequest)
+			return
+		}
+
+		// Try to get the workspace and member IDs.
+		id, ok := getID(ac, w, r, "id")
+		if !ok {
+			return
+		}
+		target, ok := getID(ac, w, r, "member_id")
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to update this member.
+		m, err := ac.Services.Workspaces.UpdateMember(id, member.ID, target, &params)
+		if pes, ok := err.(*serverrors.ParamErrors); ok && err != nil {
+			errors.Params(ac.Logger, w, http.StatusBadRequest, pes)
+			return
+		} else if err == servworkspaces.ErrWorkspaceNotFound || err == servworkspaces.ErrMemberNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servworkspaces.ErrAdminOnly || err == servworkspaces.ErrOwnerOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err == servworkspaces.ErrLastOwner {
+			errors.Default(ac.Logger, w, errors.New(http.StatusConflict, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("workspaces.UpdateMember() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		renderResult(ac, w, ResultMember{Data: m})
+	}
+}
+
+// HandleDeleteMember handles the /api/v1/workspaces/:id/members/:member_id
+// DELETE route of the API, which either removes a member from the workspace
+// or, with their own ID, lets the member leave it.
+func HandleDeleteMember(ac *apictx.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Try to get the workspace and member IDs.
+		id, ok := getID(ac, w, r, "id")
+		if !ok {
+			return
+		}
+		target, ok := getID(ac, w, r, "member_id")
+		if !ok {
+			return
+		}
+
+		// Get this member from the request context.
+		member, err := auth.GetMemberFromRequest(r)
+		if err != nil {
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Try to delete this member.
+		err = ac.Services.Workspaces.DeleteMember(id, member.ID, target)
+		if err == servworkspaces.ErrWorkspaceNotFound || err == servworkspaces.ErrMemberNotFound {
+			errors.Default(ac.Logger, w, errors.New(http.StatusNotFound, "", err.Error()))
+			return
+		} else if err == servworkspaces.ErrAdminOnly || err == servworkspaces.ErrOwnerOnly {
+			errors.Default(ac.Logger, w, errors.New(http.StatusForbidden, "", err.Error()))
+			return
+		} else if err == servworkspaces.ErrLastOwner {
+			errors.Default(ac.Logger, w, errors.New(http.StatusConflict, "", err.Error()))
+			return
+		} else if err != nil {
+			ac.Logger.Printf("workspaces.DeleteMember() service error: %s\n", err)
+			errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+			return
+		}
+
+		// Send 204 response.
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// getID gets the ID with the given name from the path of the given request,
+// rendering an error if it is invalid.
+func getID(ac *apictx.Context, w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	id64, err := strconv.ParseInt(httprouter.GetParam(r, name), 10, 32)
+	if err != nil {
+		errors.Default(ac.Logger, w, errors.ErrBadRequest)
+		return 0, false
+	}
+
+	return int(id64), true
+}
+
+// renderResult renders the given result.
+func renderResult(ac *apictx.Context, w http.ResponseWriter, result interface{}) {
+	// Render output.
+	if err := render.JSON(w, true, result); err != nil {
+		ac.Logger.Printf("render.JSON() error: %s\n", err)
+		errors.Default(ac.Logger, w, errors.ErrInternalServerError)
+		return
+	}
+}
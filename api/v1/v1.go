@@ -2,9 +2,20 @@ package v1
 
 import (
 	apictx "gotodo/api/context"
+	"gotodo/api/v1/handlers/admin"
+	"gotodo/api/v1/handlers/keys"
+	"gotodo/api/v1/handlers/lists"
 	"gotodo/api/v1/handlers/login"
+	"gotodo/api/v1/handlers/logout"
+	"gotodo/api/v1/handlers/members"
+	"gotodo/api/v1/handlers/password"
+	"gotodo/api/v1/handlers/series"
+	"gotodo/api/v1/handlers/shares"
 	"gotodo/api/v1/handlers/signup"
+	"gotodo/api/v1/handlers/tags"
 	"gotodo/api/v1/handlers/todos"
+	"gotodo/api/v1/handlers/token"
+	"gotodo/api/v1/handlers/workspaces"
 
 	"github.com/beeker1121/httprouter"
 )
@@ -17,5 +28,16 @@ func New(ac *apictx.Context, router *httprouter.Router) {
 	// Create all of the API v1 routes.
 	signup.New(ac, router)
 	login.New(ac, router)
+	logout.New(ac, router)
+	token.New(ac, router)
+	members.New(ac, router)
+	password.New(ac, router)
 	todos.New(ac, router)
+	keys.New(ac, router)
+	tags.New(ac, router)
+	lists.New(ac, router)
+	series.New(ac, router)
+	shares.New(ac, router)
+	admin.New(ac, router)
+	workspaces.New(ac, router)
 }
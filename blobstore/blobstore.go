@@ -0,0 +1,47 @@
+// Package blobstore stores the bytes of the files attached to todos.
+//
+// The BlobStore interface is implemented by Local, which keeps the bytes in
+// a directory of the local filesystem. Other stores, such as an
+// S3-compatible one, only need to implement BlobStore to be used instead.
+package blobstore
+
+import (
+	"io"
+	"regexp"
+)
+
+const (
+	// DriverLocal is the name of the blob store driver keeping the bytes
+	// on the local filesystem.
+	DriverLocal = "local"
+)
+
+// BlobStore defines a blob store.
+//
+// Blobs are stored under keys made of lowercase letters and digits, which
+// are chosen by the caller and never reused.
+type BlobStore interface {
+	// Put stores the bytes read from r under the given key.
+	Put(key string, r io.Reader) error
+
+	// Get opens the bytes stored under the given key, returning
+	// ErrBlobNotFound if there are none.
+	Get(key string) (io.ReadCloser, error)
+
+	// Delete deletes the bytes stored under the given key. Deleting a key
+	// with no bytes stored under it is not an error.
+	Delete(key string) error
+}
+
+// keyRegexp matches the keys blobs can be stored under.
+var keyRegexp = regexp.MustCompile(`^[a-z0-9]{8,128}$`)
+
+// checkKey returns an error if the given key cannot be used to store a
+// blob, so keys can never point outside of the store.
+func checkKey(key string) error {
+	if !keyRegexp.MatchString(key) {
+		return ErrKeyInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package blobstore
+
+import "errors"
+
+var (
+	// ErrKeyInvalid is returned when a blob key contains characters other
+	// than lowercase letters and digits, or is too short or too long.
+	ErrKeyInvalid = errors.New("Blob key must be 8 to 128 lowercase letters and digits")
+
+	// ErrBlobNotFound is returned when a blob could not be found.
+	ErrBlobNotFound = errors.New("Blob could not be found")
+)
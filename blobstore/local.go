@@ -0,0 +1,104 @@
+package blobstore
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Local defines the blob store keeping the bytes of each blob in a file
+// within a directory of the local filesystem.
+//
+// Blobs are spread over subdirectories named after the first two characters
+// of their key, so no single directory grows too large.
+type Local struct {
+	dir string
+}
+
+// NewLocal creates a new Local blob store within the given directory, which
+// is created when the first blob is stored.
+func NewLocal(dir string) *Local {
+	return &Local{
+		dir: dir,
+	}
+}
+
+// Put stores the bytes read from r under the given key.
+//
+// The bytes are written to a temporary file first, which is only moved in
+// place once all of them were written, so a failed upload never leaves a
+// partial blob behind.
+func (l *Local) Put(key string, r io.Reader) error {
+	// Check the key.
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	// Create the directory of the blob.
+	dir := filepath.Dir(l.path(key))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	// Write the bytes to a temporary file.
+	f, err := ioutil.TempFile(dir, "tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	// Move the file in place.
+	if err := os.Rename(f.Name(), l.path(key)); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return nil
+}
+
+// Get opens the bytes stored under the given key, returning ErrBlobNotFound
+// if there are none.
+func (l *Local) Get(key string) (io.ReadCloser, error) {
+	// Check the key.
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(l.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrBlobNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Delete deletes the bytes stored under the given key. Deleting a key with
+// no bytes stored under it is not an error.
+func (l *Local) Delete(key string) error {
+	// Check the key.
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	if err := os.Remove(l.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// path returns the path of the file of the blob with the given key.
+func (l *Local) path(key string) string {
+	return filepath.Join(l.dir, key[:2], key)
+}
@@ -0,0 +1,119 @@
+// Package bucket limits the rate of requests with token buckets.
+//
+// Each key has a bucket holding up to a burst of tokens, which is refilled
+// at a steady rate. Every request takes a token from the bucket of its key,
+// and is denied once the bucket is empty.
+package bucket
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the Limiter removes the buckets that are full
+// again.
+const sweepInterval = time.Minute
+
+// Limit defines the limit of a bucket, which is refilled with Rate tokens
+// every Per and holds up to Burst tokens.
+type Limit struct {
+	Rate  int
+	Per   time.Duration
+	Burst int
+}
+
+// refill returns how long it takes to refill the given number of tokens.
+func (l *Limit) refill(tokens float64) time.Duration {
+	return time.Duration(tokens * float64(l.Per) / float64(l.Rate))
+}
+
+// Result defines the result of taking a token.
+//
+// Remaining is the number of tokens left in the bucket, and Reset is how
+// long until it is full again. RetryAfter is how long until the next token
+// when the request was denied.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// bucket defines a token bucket.
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+// Limiter defines the token buckets limiter backed by memory.
+//
+// It is safe for concurrent use, but is not shared between processes, so
+// each API server limits its own requests.
+type Limiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewLimiter creates a new in-memory token buckets limiter.
+func NewLimiter() *Limiter {
+	return &Limiter{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Take takes a token from the bucket of the given key, which has the given
+// limit.
+func (l *Limiter) Take(key string, limit *Limit) *Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	// Remove the buckets that are full
+	// again once in a while.
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		for k, b := range l.buckets {
+			if !now.Before(b.full) {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	// Refill the bucket, new buckets being full.
+	burst := float64(limit.Burst)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst}
+		l.buckets[key] = b
+	} else {
+		b.tokens += float64(now.Sub(b.last)) * float64(limit.Rate) / float64(limit.Per)
+		if b.tokens > burst {
+			b.tokens = burst
+		}
+	}
+	b.last = now
+
+	// Create a new Result.
+	result := &Result{
+		Limit: limit.Burst,
+	}
+
+	// Take a token if there is one left.
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = limit.refill(1 - b.tokens)
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = limit.refill(burst - b.tokens)
+	b.full = now.Add(result.Reset)
+
+	return result
+}
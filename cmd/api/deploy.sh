@@ -49,6 +49,7 @@ fi
 
 echo "Exporting GOPATH and PATH variables..."
 export GOPATH="$GO_PATH"
+export GO111MODULE="off"
 export PATH="$PATH:/usr/local/go/bin:/usr/local/nodejs/bin"
 
 # *****************
@@ -5,16 +5,24 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
+	_ "time/tzdata"
 
 	"gotodo/api"
 	"gotodo/api/config"
+	"gotodo/blobstore"
 	"gotodo/database"
+	"gotodo/database/migrations"
+	"gotodo/mailer"
+	"gotodo/throttle"
 
 	"github.com/beeker1121/creek"
 	"github.com/beeker1121/httprouter"
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
 func main() {
@@ -25,6 +33,9 @@ func main() {
 	}
 
 	// Get the configuration environment variables.
+	if driver := os.Getenv("DB_DRIVER"); driver != "" {
+		cfg.DBDriver = driver
+	}
 	cfg.DBHost = os.Getenv("DB_HOST")
 	cfg.DBPort = os.Getenv("DB_PORT")
 	cfg.DBName = os.Getenv("DB_NAME")
@@ -33,29 +44,90 @@ func main() {
 	cfg.APIHost = os.Getenv("API_HOST")
 	cfg.APIPort = os.Getenv("API_PORT")
 	cfg.JWTSecret = os.Getenv("JWT_SECRET")
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		cfg.SMTPHost = host
+	}
+	cfg.SMTPUser = os.Getenv("SMTP_USER")
+	cfg.SMTPPass = os.Getenv("SMTP_PASS")
+
+	// Default to the MySQL database driver.
+	if cfg.DBDriver == "" {
+		cfg.DBDriver = database.DriverMySQL
+	}
+
+	// Default to the local blob store driver.
+	if cfg.BlobDriver == "" {
+		cfg.BlobDriver = blobstore.DriverLocal
+	}
+
+	// Handle the migrate command.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate(cfg, os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle the role command.
+	if len(os.Args) > 1 && os.Args[1] == "role" {
+		if err := role(cfg, os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Create new creek logger with 10 MB max file size.
 	logger := log.New(creek.New(cfg.LogFile, 10), "Go Todo API: ", log.Llongfile|log.LstdFlags)
 	logger.Printf("Starting Go Todo API server at %s\n", time.Now().UTC().Format(time.RFC3339))
 
-	// Connect to the MySQL database.
-	db, err := sql.Open("mysql", cfg.DBUser+":"+cfg.DBPass+"@tcp("+cfg.DBHost+":"+cfg.DBPort+")/"+cfg.DBName+"?parseTime=true")
+	// Create a new Go Todo database.
+	var gdb *database.Database
+	if cfg.DBDriver == database.DriverMemory {
+		gdb = database.NewMemory()
+	} else {
+		// Connect to the database.
+		db, err := openDB(cfg)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		defer db.Close()
+
+		// Refuse to start if the database schema is behind.
+		if cfg.DBCheckMigrations {
+			migrator, err := migrations.New(cfg.DBDriver, db)
+			if err != nil {
+				logger.Fatal(err)
+			}
+
+			pending, err := migrator.Pending()
+			if err != nil {
+				logger.Fatal(err)
+			}
+			if pending > 0 {
+				logger.Fatalf("Database schema is behind by %d migration(s), run the migrate up command\n", pending)
+			}
+		}
+
+		gdb = database.New(cfg.DBDriver, db)
+	}
+
+	// Create a new mailer.
+	m, err := newMailer(cfg)
 	if err != nil {
 		logger.Fatal(err)
 	}
-	defer db.Close()
 
-	// Test database connection.
-	if err := db.Ping(); err != nil {
+	// Create a new blob store.
+	blobs, err := newBlobStore(cfg)
+	if err != nil {
 		logger.Fatal(err)
 	}
 
-	// Create a new Go Todo database.
-	gdb := database.New(db)
-
 	// Create a new API.
 	router := httprouter.New()
-	api.New(cfg, logger, gdb, router)
+	api.New(cfg, logger, gdb, m, throttle.NewMemory(), blobs, router)
 
 	// Create a new HTTP server.
 	server := &http.Server{
@@ -73,3 +145,86 @@ func main() {
 		logger.Fatal(err)
 	}
 }
+
+// openDB connects to the SQL database of the configured database driver.
+func openDB(cfg *config.Config) (*sql.DB, error) {
+	// Build the data source name for the database driver.
+	dsn, err := dataSourceName(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Connect to the database.
+	db, err := sql.Open(cfg.DBDriver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite only allows a single writer at a time, so use a single
+	// connection to avoid "database is locked" errors.
+	if cfg.DBDriver == database.DriverSQLite {
+		db.SetMaxOpenConns(1)
+	}
+
+	// Test database connection.
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// dataSourceName builds the data source name used to connect to the database
+// for the configured database driver.
+func dataSourceName(cfg *config.Config) (string, error) {
+	switch cfg.DBDriver {
+	case database.DriverMySQL:
+		return cfg.DBUser + ":" + cfg.DBPass + "@tcp(" + cfg.DBHost + ":" + cfg.DBPort + ")/" + cfg.DBName + "?parseTime=true", nil
+	case database.DriverSQLite:
+		// The database name is the path to the SQLite database file.
+		return "file:" + cfg.DBName + "?_busy_timeout=5000", nil
+	case database.DriverPostgres:
+		dsn := &url.URL{
+			Scheme: "postgres",
+			User:   url.UserPassword(cfg.DBUser, cfg.DBPass),
+			Host:   cfg.DBHost + ":" + cfg.DBPort,
+			Path:   "/" + cfg.DBName,
+		}
+		return dsn.String(), nil
+	}
+
+	return "", fmt.Errorf("Unsupported database driver %s", cfg.DBDriver)
+}
+
+// newMailer creates the mailer of the configured mail driver.
+func newMailer(cfg *config.Config) (mailer.Mailer, error) {
+	switch cfg.MailDriver {
+	case mailer.DriverSMTP:
+		return mailer.NewSMTP(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPass, cfg.MailFrom), nil
+	case mailer.DriverFile:
+		// Write emails to standard output
+		// when no file is given.
+		if cfg.MailFile == "" {
+			return mailer.NewWriter(os.Stdout, cfg.MailFrom), nil
+		}
+
+		f, err := os.OpenFile(cfg.MailFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+		return mailer.NewWriter(f, cfg.MailFrom), nil
+	}
+
+	return nil, fmt.Errorf("Unsupported mail driver %s", cfg.MailDriver)
+}
+
+// newBlobStore creates the blob store of the configured blob store driver.
+func newBlobStore(cfg *config.Config) (blobstore.BlobStore, error) {
+	switch cfg.BlobDriver {
+	case blobstore.DriverLocal:
+		return blobstore.NewLocal(cfg.BlobDir), nil
+	}
+
+	return nil, fmt.Errorf("Unsupported blob store driver %s", cfg.BlobDriver)
+}
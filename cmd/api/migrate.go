@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gotodo/api/config"
+	"gotodo/database"
+	"gotodo/database/migrations"
+)
+
+// migrateUsage describes the usage of the migrate command.
+const migrateUsage = `Usage: api migrate <command>
+
+Commands:
+  up              Apply all pending migrations
+  down            Roll back the newest applied migration
+  status          Show the status of every migration
+  to <version>    Apply or roll back migrations until at the given version`
+
+// migrate runs the migrate command with the given arguments.
+func migrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	// The memory database has no schema.
+	if cfg.DBDriver == database.DriverMemory {
+		return errors.New("The memory database driver does not use migrations")
+	}
+
+	// Connect to the database.
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Create a new Migrator.
+	migrator, err := migrations.New(cfg.DBDriver, db)
+	if err != nil {
+		return err
+	}
+
+	// Handle the command.
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		err = migrator.Up()
+	case args[0] == "down" && len(args) == 1:
+		err = migrator.Down()
+	case args[0] == "to" && len(args) == 2:
+		version, perr := strconv.Atoi(args[1])
+		if perr != nil {
+			return fmt.Errorf("Invalid migration version %s", args[1])
+		}
+		err = migrator.To(version)
+	case args[0] == "status" && len(args) == 1:
+		// Nothing to apply, the status is printed below.
+	default:
+		return errors.New(migrateUsage)
+	}
+	if err != nil {
+		return err
+	}
+
+	return printStatus(migrator)
+}
+
+// printStatus prints the status of every migration.
+func printStatus(migrator *migrations.Migrator) error {
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		applied := "pending"
+		if status.Applied != nil {
+			applied = "applied " + status.Applied.UTC().Format(time.RFC3339)
+		}
+		fmt.Printf("%04d %-30s %s\n", status.Version, status.Name, applied)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"gotodo/api/config"
+	"gotodo/database"
+	"gotodo/services/members"
+)
+
+// roleUsage describes the usage of the role command.
+const roleUsage = `Usage: api role <email> <role>
+
+Sets the role of the member with the given email, which is one of member,
+admin or read-only. This is used to make the first administrator, who can
+then manage the roles of the other members through the API.`
+
+// role runs the role command with the given arguments.
+func role(cfg *config.Config, args []string) error {
+	if len(args) != 2 {
+		return errors.New(roleUsage)
+	}
+
+	// The memory database is lost once the command exits.
+	if cfg.DBDriver == database.DriverMemory {
+		return errors.New("The memory database driver does not keep roles between runs")
+	}
+
+	// Check the role.
+	if !members.IsRole(args[1]) {
+		return members.ErrRoleInvalid
+	}
+
+	// Connect to the database.
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Find the member.
+	gdb := database.New(cfg.DBDriver, db)
+	member, err := gdb.Members.GetByEmail(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Set the role of this member.
+	params := &members.SetRoleParams{
+		Role: args[1],
+	}
+	if _, err := members.New(gdb, nil, nil, nil).SetRole(member.ID, params); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is now %s\n", member.Email, args[1])
+	return nil
+}
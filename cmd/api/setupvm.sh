@@ -38,10 +38,11 @@ APP_USER="gotodoapi"
 APP_DEPLOY_DIR="/deploy"
 APP_LOG_DIR="/var/log/gotodoapi"
 APP_LOG_FILE="log.log"
+APP_BLOB_DIR="/var/lib/gotodoapi/attachments"
 
 # Download Links
-GOLANG_DL="https://dl.google.com/go/go1.10.1.linux-amd64.tar.gz"
-GOLANG_DL_NAME_EXT="go1.10.1.linux-amd64.tar.gz"
+GOLANG_DL="https://dl.google.com/go/go1.22.12.linux-amd64.tar.gz"
+GOLANG_DL_NAME_EXT="go1.22.12.linux-amd64.tar.gz"
 NODE_DL="https://nodejs.org/dist/v8.10.0/node-v8.10.0-linux-x64.tar.gz"
 NODE_DL_NAME="node-v8.10.0-linux-x64"
 NODE_DL_NAME_EXT="node-v8.10.0-linux-x64.tar.gz"
@@ -233,6 +234,19 @@ chmod -R u=rwX,go= $APP_LOG_DIR
 echo "Permissions set: -rw------- $APP_USER:$APP_USER $APP_LOG_FILE"
 echo ""
 
+# *****************
+# App Attachments
+# *****************
+
+# Create app attachments directory
+echo "Creating app attachments directory at $APP_BLOB_DIR..."
+mkdir -p $APP_BLOB_DIR > /dev/null 2>&1
+echo "Setting ownership and permissions..."
+chown -R $APP_USER:$APP_USER $APP_BLOB_DIR
+chmod -R u=rwX,go= $APP_BLOB_DIR
+echo "Permissions set: -rw------- $APP_USER:$APP_USER $APP_BLOB_DIR recursively"
+echo ""
+
 # *****************
 # Supervisor
 # *****************
@@ -290,6 +304,7 @@ echo ""
 echo "Adding new PATH and GOPATH in /etc/profile..."
 echo "export PATH=\"\$PATH:/usr/local/go/bin:/usr/local/nodejs/bin\"" >> /etc/profile
 echo "export GOPATH=\"$ORIG_HOME/work\"" >> /etc/profile
+echo "export GO111MODULE=\"off\"" >> /etc/profile
 echo "Added to /etc/profile"
 echo ""
 
@@ -309,8 +324,8 @@ echo ""
 echo "Then, modify the /etc/supervisor/conf.d/$APP_NAME.conf file so the correct values"
 echo "are set for the needed environment variables, like the database username and password."
 echo ""
-echo "Make sure to create a new MySQL user and import the cmd/api/schema.sql file to"
-echo "your database."
+echo "Make sure to create a new MySQL user and apply the schema migrations to your"
+echo "database using the api migrate up command."
 echo ""
 echo "Then, cd to the directory, chmod the deploy script, and execute:"
 echo ""
@@ -0,0 +1,53 @@
+package attachments
+
+import "time"
+
+// Database defines the attachments database.
+//
+// Attachments are files attached to a todo. Only their metadata is kept in
+// the database, while their bytes are kept in a blob store under Key.
+type Database interface {
+	// New creates a new attachment on the todo with the given ID.
+	New(tid, mid int, params *NewParams) (*Attachment, error)
+
+	// GetByTodoID gets the attachments on the todo with the given ID,
+	// oldest first.
+	GetByTodoID(tid int) (*Attachments, error)
+
+	// GetByID retrieves an attachment by its ID.
+	GetByID(id int) (*Attachment, error)
+
+	// GetOrphaned gets the attachments on todos which no longer exist.
+	GetOrphaned() (*Attachments, error)
+
+	// Delete deletes an attachment.
+	Delete(id int) error
+}
+
+// Attachment defines an attachment.
+//
+// Size is given in bytes.
+type Attachment struct {
+	ID          int       `json:"id"`
+	TodoID      int       `json:"todo_id"`
+	MemberID    int       `json:"member_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Key         string    `json:"-"`
+	Created     time.Time `json:"created"`
+}
+
+// Attachments defines a set of attachments.
+type Attachments struct {
+	Attachments []*Attachment `json:"attachments"`
+	Total       int           `json:"total"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Key         string `json:"key"`
+}
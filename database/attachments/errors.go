@@ -0,0 +1,9 @@
+package attachments
+
+import "errors"
+
+var (
+	// ErrAttachmentNotFound is returned when an attachment could not be
+	// found.
+	ErrAttachmentNotFound = errors.New("Attachment could not be found")
+)
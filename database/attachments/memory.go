@@ -0,0 +1,167 @@
+package attachments
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"gotodo/database/todos"
+)
+
+// Memory defines the attachments database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits. The todos database is used to
+// find the attachments on todos which no longer exist.
+type Memory struct {
+	mu          sync.RWMutex
+	lastID      int
+	attachments map[int]*Attachment
+	todos       todos.Database
+}
+
+// NewMemory creates a new in-memory attachments database, attaching files
+// to the todos of the given todos database.
+func NewMemory(todos todos.Database) *Memory {
+	return &Memory{
+		attachments: make(map[int]*Attachment),
+		todos:       todos,
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	attachments := make(map[int]*Attachment, len(m.attachments))
+	for id, v := range m.attachments {
+		c := *v
+		attachments[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.attachments = attachments
+	}
+}
+
+// New creates a new attachment on the todo with the given ID.
+func (m *Memory) New(tid, mid int, params *NewParams) (*Attachment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Attachment.
+	m.lastID++
+	attachment := &Attachment{
+		ID:          m.lastID,
+		TodoID:      tid,
+		MemberID:    mid,
+		Name:        params.Name,
+		ContentType: params.ContentType,
+		Size:        params.Size,
+		Key:         params.Key,
+		Created:     time.Now(),
+	}
+
+	// Store a copy of the attachment.
+	stored := *attachment
+	m.attachments[attachment.ID] = &stored
+
+	return attachment, nil
+}
+
+// GetByTodoID gets the attachments on the todo with the given ID, oldest
+// first.
+func (m *Memory) GetByTodoID(tid int) (*Attachments, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.get(func(attachment *Attachment) bool {
+		return attachment.TodoID == tid
+	}), nil
+}
+
+// GetByID retrieves an attachment by its ID.
+func (m *Memory) GetByID(id int) (*Attachment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	attachment, ok := m.attachments[id]
+	if !ok {
+		return nil, ErrAttachmentNotFound
+	}
+
+	// Return a copy of the attachment.
+	found := *attachment
+	return &found, nil
+}
+
+// GetOrphaned gets the attachments on todos which no longer exist.
+func (m *Memory) GetOrphaned() (*Attachments, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Find the todos which no longer exist.
+	orphaned := make(map[int]bool)
+	for _, attachment := range m.attachments {
+		if _, ok := orphaned[attachment.TodoID]; ok {
+			continue
+		}
+
+		_, err := m.todos.GetByID(attachment.TodoID)
+		if err != nil && err != todos.ErrTodoNotFound {
+			return nil, err
+		}
+		orphaned[attachment.TodoID] = err == todos.ErrTodoNotFound
+	}
+
+	return m.get(func(attachment *Attachment) bool {
+		return orphaned[attachment.TodoID]
+	}), nil
+}
+
+// Delete deletes an attachment.
+func (m *Memory) Delete(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.attachments[id]; !ok {
+		return ErrAttachmentNotFound
+	}
+	delete(m.attachments, id)
+
+	return nil
+}
+
+// get gets copies of the attachments matching the given function, sorted by
+// ID.
+func (m *Memory) get(match func(attachment *Attachment) bool) *Attachments {
+	// Create a new Attachments.
+	attachments := &Attachments{
+		Attachments: []*Attachment{},
+	}
+
+	// Add copies of the matching attachments.
+	for _, attachment := range m.attachments {
+		if match(attachment) {
+			found := *attachment
+			attachments.Attachments = append(attachments.Attachments, &found)
+		}
+	}
+	attachments.Total = len(attachments.Attachments)
+
+	// Sort the attachments by ID.
+	sort.Slice(attachments.Attachments, func(i, j int) bool {
+		return attachments.Attachments[i].ID < attachments.Attachments[j].ID
+	})
+
+	return attachments
+}
@@ -0,0 +1,183 @@
+package attachments
+
+import (
+	"database/sql"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the attachments database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL attachments database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// columns defines the columns selected for an
+	// attachment, in the order they are scanned.
+	columns = `id, todo_id, member_id, name, content_type, size, blob_key, created`
+
+	// stmtInsert defines the SQL statement to
+	// insert a new attachment into the database.
+	stmtInsert = `
+INSERT INTO attachments (todo_id, member_id, name, content_type, size, blob_key, created)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+	// stmtSelectByTodoID defines the SQL statement
+	// to select the attachments on a given todo.
+	stmtSelectByTodoID = `
+SELECT ` + columns + `
+FROM attachments
+WHERE todo_id=?
+ORDER BY id
+`
+
+	// stmtSelectByID defines the SQL statement to
+	// select an attachment by its ID.
+	stmtSelectByID = `
+SELECT ` + columns + `
+FROM attachments
+WHERE id=?
+`
+
+	// stmtSelectOrphaned defines the SQL statement
+	// to select the attachments on todos which no
+	// longer exist.
+	stmtSelectOrphaned = `
+SELECT ` + columns + `
+FROM attachments
+WHERE todo_id NOT IN (SELECT id FROM todos)
+ORDER BY id
+`
+
+	// stmtDelete defines the SQL statement to
+	// delete an attachment.
+	stmtDelete = `
+DELETE FROM attachments
+WHERE id=?
+`
+)
+
+// New creates a new attachment on the todo with the given ID.
+func (db *SQL) New(tid, mid int, params *NewParams) (*Attachment, error) {
+	// Create a new Attachment.
+	attachment := &Attachment{
+		TodoID:      tid,
+		MemberID:    mid,
+		Name:        params.Name,
+		ContentType: params.ContentType,
+		Size:        params.Size,
+		Key:         params.Key,
+		Created:     time.Now(),
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, attachment.TodoID, attachment.MemberID, attachment.Name, attachment.ContentType, attachment.Size, attachment.Key, attachment.Created)
+	if err != nil {
+		return nil, err
+	}
+	attachment.ID = id
+
+	return attachment, nil
+}
+
+// GetByTodoID gets the attachments on the todo with the given ID, oldest
+// first.
+func (db *SQL) GetByTodoID(tid int) (*Attachments, error) {
+	return db.get(stmtSelectByTodoID, tid)
+}
+
+// GetByID retrieves an attachment by its ID.
+func (db *SQL) GetByID(id int) (*Attachment, error) {
+	// Create a new Attachment.
+	attachment := &Attachment{}
+
+	// Execute the query.
+	err := scan(db.db.QueryRow(stmtSelectByID, id), attachment)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrAttachmentNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// GetOrphaned gets the attachments on todos which no longer exist.
+func (db *SQL) GetOrphaned() (*Attachments, error) {
+	return db.get(stmtSelectOrphaned)
+}
+
+// Delete deletes an attachment.
+func (db *SQL) Delete(id int) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtDelete, id)
+	if err != nil {
+		return err
+	}
+
+	// Check if an attachment was deleted.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrAttachmentNotFound
+	}
+
+	return nil
+}
+
+// get gets the set of attachments selected by the given statement.
+func (db *SQL) get(stmt string, args ...interface{}) (*Attachments, error) {
+	// Create a new Attachments.
+	attachments := &Attachments{
+		Attachments: []*Attachment{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the attachment rows.
+	for rows.Next() {
+		// Create a new Attachment.
+		attachment := &Attachment{}
+
+		// Scan row values into attachment struct.
+		if err := scan(rows, attachment); err != nil {
+			return nil, err
+		}
+
+		// Add to attachments set.
+		attachments.Attachments = append(attachments.Attachments, attachment)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	attachments.Total = len(attachments.Attachments)
+
+	return attachments, nil
+}
+
+// scanner defines the Scan method shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan scans an attachment row into the given attachment.
+func scan(row scanner, attachment *Attachment) error {
+	return row.Scan(&attachment.ID, &attachment.TodoID, &attachment.MemberID, &attachment.Name, &attachment.ContentType, &attachment.Size, &attachment.Key, &attachment.Created)
+}
@@ -0,0 +1,69 @@
+package comments
+
+import "time"
+
+// Database defines the comments database.
+//
+// Comments are left by members on a todo, and can only be changed by the
+// member who wrote them.
+type Database interface {
+	// New creates a new comment on the todo with the given ID.
+	New(tid, mid int, params *NewParams) (*Comment, error)
+
+	// GetByTodoID gets a set of the comments on the todo with the given
+	// ID, oldest first.
+	GetByTodoID(tid int, params *GetParams) (*Comments, error)
+
+	// GetByID retrieves a comment by its ID.
+	GetByID(id int) (*Comment, error)
+
+	// Update updates a comment.
+	Update(id int, params *UpdateParams) (*Comment, error)
+
+	// Delete deletes a comment.
+	Delete(id int) error
+
+	// DeleteByTodoID deletes every comment on the todo with the given ID.
+	DeleteByTodoID(tid int) error
+
+	// DeleteByTodoIDs deletes every comment on the todos with the given
+	// IDs.
+	DeleteByTodoIDs(tids []int) error
+
+	// DeleteByMemberID deletes every comment a given member wrote.
+	DeleteByMemberID(mid int) error
+}
+
+// Comment defines a comment.
+//
+// UpdatedAt is only set once the body of the comment has been edited.
+type Comment struct {
+	ID        int        `json:"id"`
+	TodoID    int        `json:"todo_id"`
+	MemberID  int        `json:"member_id"`
+	Body      string     `json:"body"`
+	Created   time.Time  `json:"created"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// Comments defines a set of comments.
+type Comments struct {
+	Comments []*Comment `json:"comments"`
+	Total    int        `json:"total"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams struct {
+	Body string `json:"body"`
+}
+
+// GetParams defines the parameters for the GetByTodoID method.
+type GetParams struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// UpdateParams defines the parameters for the Update method.
+type UpdateParams struct {
+	Body *string `json:"body"`
+}
@@ -0,0 +1,8 @@
+package comments
+
+import "errors"
+
+var (
+	// ErrCommentNotFound is returned when a comment could not be found.
+	ErrCommentNotFound = errors.New("Comment could not be found")
+)
@@ -0,0 +1,210 @@
+package comments
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory defines the comments database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits.
+type Memory struct {
+	mu       sync.RWMutex
+	lastID   int
+	comments map[int]*Comment
+}
+
+// NewMemory creates a new in-memory comments database.
+func NewMemory() *Memory {
+	return &Memory{
+		comments: make(map[int]*Comment),
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	comments := make(map[int]*Comment, len(m.comments))
+	for id, v := range m.comments {
+		c := *v
+		comments[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.comments = comments
+	}
+}
+
+// New creates a new comment on the todo with the given ID.
+func (m *Memory) New(tid, mid int, params *NewParams) (*Comment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Comment.
+	m.lastID++
+	comment := &Comment{
+		ID:       m.lastID,
+		TodoID:   tid,
+		MemberID: mid,
+		Body:     params.Body,
+		Created:  time.Now(),
+	}
+
+	// Store a copy of the comment.
+	m.comments[comment.ID] = copyComment(comment)
+
+	return comment, nil
+}
+
+// GetByTodoID gets a set of the comments on the todo with the given ID,
+// oldest first.
+func (m *Memory) GetByTodoID(tid int, params *GetParams) (*Comments, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Find the comments on this todo.
+	var matches []*Comment
+	for _, comment := range m.comments {
+		if comment.TodoID == tid {
+			matches = append(matches, comment)
+		}
+	}
+
+	// Sort the comments by ID so pagination is stable.
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].ID < matches[j].ID
+	})
+
+	// Create a new Comments.
+	comments := &Comments{
+		Comments: []*Comment{},
+		Total:    len(matches),
+	}
+
+	// Add copies of the requested page of comments.
+	for i := params.Offset; i < len(matches) && i < params.Offset+params.Limit; i++ {
+		comments.Comments = append(comments.Comments, copyComment(matches[i]))
+	}
+
+	return comments, nil
+}
+
+// GetByID retrieves a comment by its ID.
+func (m *Memory) GetByID(id int) (*Comment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	comment, ok := m.comments[id]
+	if !ok {
+		return nil, ErrCommentNotFound
+	}
+
+	// Return a copy of the comment.
+	return copyComment(comment), nil
+}
+
+// Update updates a comment.
+func (m *Memory) Update(id int, params *UpdateParams) (*Comment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	comment, ok := m.comments[id]
+	if !ok {
+		return nil, ErrCommentNotFound
+	}
+
+	// Handle body field, marking the
+	// comment as edited.
+	if params.Body != nil {
+		now := time.Now()
+		comment.Body = *params.Body
+		comment.UpdatedAt = &now
+	}
+
+	// Return a copy of the comment.
+	return copyComment(comment), nil
+}
+
+// Delete deletes a comment.
+func (m *Memory) Delete(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.comments[id]; !ok {
+		return ErrCommentNotFound
+	}
+	delete(m.comments, id)
+
+	return nil
+}
+
+// DeleteByTodoID deletes every comment on the todo with the given ID.
+func (m *Memory) DeleteByTodoID(tid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, comment := range m.comments {
+		if comment.TodoID == tid {
+			delete(m.comments, id)
+		}
+	}
+
+	return nil
+}
+
+// DeleteByTodoIDs deletes every comment on the todos with the given IDs.
+func (m *Memory) DeleteByTodoIDs(tids []int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a set of the todo IDs.
+	deleted := make(map[int]bool)
+	for _, tid := range tids {
+		deleted[tid] = true
+	}
+
+	for id, comment := range m.comments {
+		if deleted[comment.TodoID] {
+			delete(m.comments, id)
+		}
+	}
+
+	return nil
+}
+
+// DeleteByMemberID deletes every comment a given member wrote.
+func (m *Memory) DeleteByMemberID(mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, comment := range m.comments {
+		if comment.MemberID == mid {
+			delete(m.comments, id)
+		}
+	}
+
+	return nil
+}
+
+// copyComment returns a copy of the given comment, so stored comments never
+// share memory with the caller.
+func copyComment(comment *Comment) *Comment {
+	c := *comment
+	if comment.UpdatedAt != nil {
+		t := *comment.UpdatedAt
+		c.UpdatedAt = &t
+	}
+	return &c
+}
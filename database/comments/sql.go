@@ -0,0 +1,273 @@
+package comments
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the comments database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL comments database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// columns defines the columns selected for
+	// a comment, in the order they are scanned.
+	columns = `id, todo_id, member_id, body, created, updated_at`
+
+	// stmtInsert defines the SQL statement to
+	// insert a new comment into the database.
+	stmtInsert = `
+INSERT INTO comments (todo_id, member_id, body, created)
+VALUES (?, ?, ?, ?)
+`
+
+	// stmtSelectByTodoID defines the SQL statement
+	// to select a set of comments on a given todo.
+	stmtSelectByTodoID = `
+SELECT ` + columns + `
+FROM comments
+WHERE todo_id=?
+ORDER BY id
+%s
+`
+
+	// stmtSelectCountByTodoID defines the SQL
+	// statement to select the total number of
+	// comments on a given todo.
+	stmtSelectCountByTodoID = `
+SELECT COUNT(*)
+FROM comments
+WHERE todo_id=?
+`
+
+	// stmtSelectByID defines the SQL statement to
+	// select a comment by its ID.
+	stmtSelectByID = `
+SELECT ` + columns + `
+FROM comments
+WHERE id=?
+`
+
+	// stmtUpdate defines the SQL statement to
+	// update a comment.
+	stmtUpdate = `
+UPDATE comments
+SET %s
+WHERE id=?
+`
+
+	// stmtDelete defines the SQL statement to
+	// delete a comment.
+	stmtDelete = `
+DELETE FROM comments
+WHERE id=?
+`
+
+	// stmtDeleteByTodoID defines the SQL statement
+	// to delete every comment on a given todo.
+	stmtDeleteByTodoID = `
+DELETE FROM comments
+WHERE todo_id=?
+`
+
+	// stmtDeleteByTodoIDs defines the SQL statement
+	// to delete every comment on a set of todos.
+	stmtDeleteByTodoIDs = `
+DELETE FROM comments
+WHERE todo_id IN (%s)
+`
+
+	// stmtDeleteByMemberID defines the SQL statement
+	// to delete every comment a given member wrote.
+	stmtDeleteByMemberID = `
+DELETE FROM comments
+WHERE member_id=?
+`
+)
+
+// New creates a new comment on the todo with the given ID.
+func (db *SQL) New(tid, mid int, params *NewParams) (*Comment, error) {
+	// Create a new Comment.
+	comment := &Comment{
+		TodoID:   tid,
+		MemberID: mid,
+		Body:     params.Body,
+		Created:  time.Now(),
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, comment.TodoID, comment.MemberID, comment.Body, comment.Created)
+	if err != nil {
+		return nil, err
+	}
+	comment.ID = id
+
+	return comment, nil
+}
+
+// GetByTodoID gets a set of the comments on the todo with the given ID,
+// oldest first.
+func (db *SQL) GetByTodoID(tid int, params *GetParams) (*Comments, error) {
+	// Build the full query.
+	query := fmt.Sprintf(stmtSelectByTodoID, db.db.Dialect().Limit(params.Offset, params.Limit))
+
+	// Create a new Comments.
+	comments := &Comments{
+		Comments: []*Comment{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(query, tid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the comment rows.
+	for rows.Next() {
+		// Create a new Comment.
+		comment := &Comment{}
+
+		// Scan row values into comment struct.
+		if err := scan(rows, comment); err != nil {
+			return nil, err
+		}
+
+		// Add to comments set.
+		comments.Comments = append(comments.Comments, comment)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Get total count.
+	var total int
+	if err = db.db.QueryRow(stmtSelectCountByTodoID, tid).Scan(&total); err != nil {
+		return nil, err
+	}
+	comments.Total = total
+
+	return comments, nil
+}
+
+// GetByID retrieves a comment by its ID.
+func (db *SQL) GetByID(id int) (*Comment, error) {
+	// Create a new Comment.
+	comment := &Comment{}
+
+	// Execute the query.
+	err := scan(db.db.QueryRow(stmtSelectByID, id), comment)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrCommentNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// Update updates a comment.
+func (db *SQL) Update(id int, params *UpdateParams) (*Comment, error) {
+	// Create variables to hold the query fields
+	// being updated and their new values.
+	var queryFields string
+	var queryValues []interface{}
+
+	// Handle body field, marking the
+	// comment as edited.
+	if params.Body != nil {
+		queryFields = "body=?, updated_at=?"
+		queryValues = append(queryValues, *params.Body, time.Now().UTC())
+	}
+
+	// Check if the query is empty.
+	if queryFields == "" {
+		return db.GetByID(id)
+	}
+
+	// Build the full query.
+	query := fmt.Sprintf(stmtUpdate, queryFields)
+	queryValues = append(queryValues, id)
+
+	// Execute the query.
+	_, err := db.db.Exec(query, queryValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetByID(id)
+}
+
+// Delete deletes a comment.
+func (db *SQL) Delete(id int) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtDelete, id)
+	if err != nil {
+		return err
+	}
+
+	// Check if a comment was deleted.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrCommentNotFound
+	}
+
+	return nil
+}
+
+// DeleteByTodoID deletes every comment on the todo with the given ID.
+func (db *SQL) DeleteByTodoID(tid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByTodoID, tid)
+	return err
+}
+
+// DeleteByTodoIDs deletes every comment on the todos with the given IDs.
+func (db *SQL) DeleteByTodoIDs(tids []int) error {
+	// Check there is anything to delete.
+	if len(tids) == 0 {
+		return nil
+	}
+
+	// Get the IDs as query values.
+	queryValues := make([]interface{}, len(tids))
+	for i, tid := range tids {
+		queryValues[i] = tid
+	}
+
+	// Execute the query.
+	_, err := db.db.Exec(fmt.Sprintf(stmtDeleteByTodoIDs, dialect.Placeholders(len(tids))), queryValues...)
+	return err
+}
+
+// DeleteByMemberID deletes every comment a given member wrote.
+func (db *SQL) DeleteByMemberID(mid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByMemberID, mid)
+	return err
+}
+
+// scanner defines the Scan method shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan scans a comment row into the given comment.
+func scan(row scanner, comment *Comment) error {
+	return row.Scan(&comment.ID, &comment.TodoID, &comment.MemberID, &comment.Body, &comment.Created, &comment.UpdatedAt)
+}
@@ -2,21 +2,213 @@ package database
 
 import (
 	"database/sql"
+	"sync"
 
+	"gotodo/database/attachments"
+	"gotodo/database/comments"
+	"gotodo/database/dialect"
+	"gotodo/database/events"
+	"gotodo/database/invitations"
+	"gotodo/database/keys"
+	"gotodo/database/lists"
 	"gotodo/database/members"
+	"gotodo/database/onetime"
+	"gotodo/database/recovery"
+	"gotodo/database/series"
+	"gotodo/database/shares"
 	"gotodo/database/todos"
+	"gotodo/database/tokens"
+	"gotodo/database/workspaces"
+)
+
+const (
+	// DriverMySQL is the name of the MySQL database driver.
+	DriverMySQL = "mysql"
+
+	// DriverSQLite is the name of the SQLite database driver.
+	DriverSQLite = "sqlite3"
+
+	// DriverPostgres is the name of the PostgreSQL database driver.
+	DriverPostgres = "postgres"
+
+	// DriverMemory selects the in-memory database, which does not use an
+	// SQL database driver at all.
+	DriverMemory = "memory"
 )
 
 // Database defines the database.
+//
+// Each store is an interface, so the implementation backing it can be
+// swapped out without the services noticing.
 type Database struct {
-	Members *members.Database
-	Todos   *todos.Database
+	Attachments attachments.Database
+	Comments    comments.Database
+	Events      events.Database
+	Invitations invitations.Database
+	Keys        keys.Database
+	Lists       lists.Database
+	Members     members.Database
+	OneTime     onetime.Database
+	Recovery    recovery.Database
+	Series      series.Database
+	Shares      shares.Database
+	Todos       todos.Database
+	Tokens      tokens.Database
+	Workspaces  workspaces.Database
+
+	// sqldb is the SQL database backing the stores, which is nil for the
+	// in-memory database.
+	sqldb *dialect.DB
+
+	// memory holds the stores of the in-memory database, which are rolled
+	// back to a snapshot when a transaction fails. Its transactions are
+	// run one at a time using mu, and inTx is set on the database given
+	// to them.
+	memory []snapshotter
+	mu     *sync.Mutex
+	inTx   bool
+}
+
+// snapshotter defines an in-memory store, which can be restored to the state
+// it was in when the snapshot was taken.
+type snapshotter interface {
+	Snapshot() func()
+}
+
+// New returns a new database backed by the given SQL database, which was
+// opened using the given driver.
+//
+// The SQL stores are written using statements understood by both MySQL and
+// SQLite, which are rewritten as needed when using PostgreSQL.
+func New(driver string, db *sql.DB) *Database {
+	// Get the SQL dialect of the driver.
+	d := dialect.MySQL
+	if driver == DriverPostgres {
+		d = dialect.Postgres
+	}
+	ddb := dialect.New(db, d)
+
+	return newSQL(ddb)
 }
 
-// New returns a new database.
-func New(db *sql.DB) *Database {
+// newSQL returns a new database with stores backed by the given SQL
+// database.
+func newSQL(ddb *dialect.DB) *Database {
 	return &Database{
-		Members: members.New(db),
-		Todos:   todos.New(db),
+		Attachments: attachments.NewSQL(ddb),
+		Comments:    comments.NewSQL(ddb),
+		Events:      events.NewSQL(ddb),
+		Invitations: invitations.NewSQL(ddb),
+		Keys:        keys.NewSQL(ddb),
+		Lists:       lists.NewSQL(ddb),
+		Members:     members.NewSQL(ddb),
+		OneTime:     onetime.NewSQL(ddb),
+		Recovery:    recovery.NewSQL(ddb),
+		Series:      series.NewSQL(ddb),
+		Shares:      shares.NewSQL(ddb),
+		Todos:       todos.NewSQL(ddb),
+		Tokens:      tokens.NewSQL(ddb),
+		Workspaces:  workspaces.NewSQL(ddb),
+		sqldb:       ddb,
+	}
+}
+
+// NewMemory returns a new database that keeps all of its data in memory.
+func NewMemory() *Database {
+	// The attachments store looks up the todos
+	// to find the attachments on deleted todos.
+	t := todos.NewMemory()
+
+	db := &Database{
+		Attachments: attachments.NewMemory(t),
+		Comments:    comments.NewMemory(),
+		Events:      events.NewMemory(),
+		Invitations: invitations.NewMemory(),
+		Keys:        keys.NewMemory(),
+		Lists:       lists.NewMemory(),
+		Members:     members.NewMemory(),
+		OneTime:     onetime.NewMemory(),
+		Recovery:    recovery.NewMemory(),
+		Series:      series.NewMemory(),
+		Shares:      shares.NewMemory(),
+		Todos:       t,
+		Tokens:      tokens.NewMemory(),
+		Workspaces:  workspaces.NewMemory(),
+		mu:          &sync.Mutex{},
+	}
+
+	// Keep every store so transactions
+	// can take snapshots of them.
+	db.memory = []snapshotter{
+		db.Attachments.(snapshotter),
+		db.Comments.(snapshotter),
+		db.Events.(snapshotter),
+		db.Invitations.(snapshotter),
+		db.Keys.(snapshotter),
+		db.Lists.(snapshotter),
+		db.Members.(snapshotter),
+		db.OneTime.(snapshotter),
+		db.Recovery.(snapshotter),
+		db.Series.(snapshotter),
+		db.Shares.(snapshotter),
+		db.Todos.(snapshotter),
+		db.Tokens.(snapshotter),
+		db.Workspaces.(snapshotter),
+	}
+
+	return db
+}
+
+// Transaction runs the given function within a transaction, which is
+// committed if the function returns nil and rolled back otherwise. The
+// function must only use the stores of the database it is given. If the
+// database is already within a transaction, the function is run within it.
+//
+// The in-memory database runs its transactions one at a time, taking a
+// snapshot of every store first, which is restored if the function fails.
+// Since changes made outside of a transaction do not wait for it, those
+// made while a transaction fails may be rolled back along with it.
+func (db *Database) Transaction(fn func(tx *Database) error) error {
+	if db.sqldb != nil {
+		return db.sqldb.Transaction(func(tx *dialect.DB) error {
+			return fn(newSQL(tx))
+		})
+	}
+
+	// Run the function right away if the
+	// database is already within a transaction.
+	if db.inTx {
+		return fn(db)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	// Take a snapshot of every store.
+	restores := make([]func(), len(db.memory))
+	for i, store := range db.memory {
+		restores[i] = store.Snapshot()
 	}
+
+	// Restore the snapshots unless the function
+	// succeeds, including when it panics.
+	committed := false
+	defer func() {
+		if !committed {
+			for _, restore := range restores {
+				restore()
+			}
+		}
+	}()
+
+	// Run the function on a copy of the database
+	// marked as being within a transaction.
+	tx := *db
+	tx.inTx = true
+	if err := fn(&tx); err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
 }
@@ -0,0 +1,171 @@
+package dialect
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// Dialect defines the flavor of SQL understood by a database.
+//
+// Statements are written using ? placeholders and are rewritten for the
+// dialect right before they are executed.
+type Dialect int
+
+const (
+	// MySQL is the dialect used by MySQL. SQLite understands it as well.
+	MySQL Dialect = iota
+
+	// Postgres is the dialect used by PostgreSQL.
+	Postgres
+)
+
+// Rebind rewrites the ? placeholders of the given query into the placeholder
+// syntax of the dialect.
+func (d Dialect) Rebind(query string) string {
+	if d != Postgres {
+		return query
+	}
+
+	// Replace each placeholder with its numbered form, e.g. $1.
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// Limit returns the clause limiting a query to the given offset and limit.
+func (d Dialect) Limit(offset, limit int) string {
+	if d == Postgres {
+		return "LIMIT " + strconv.Itoa(limit) + " OFFSET " + strconv.Itoa(offset)
+	}
+
+	return "LIMIT " + strconv.Itoa(offset) + ", " + strconv.Itoa(limit)
+}
+
+// Placeholders returns a comma separated list of n placeholders, such as
+// for an IN clause.
+func Placeholders(n int) string {
+	return "?" + strings.Repeat(", ?", n-1)
+}
+
+// querier defines the methods shared by SQL databases and transactions.
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// DB wraps an SQL database, rewriting statements for its dialect right
+// before they are executed.
+//
+// Statements are executed within a transaction for the DB given to the
+// function run by the Transaction method.
+type DB struct {
+	db      *sql.DB
+	q       querier
+	dialect Dialect
+}
+
+// New returns a new DB using the given dialect.
+func New(db *sql.DB, d Dialect) *DB {
+	return &DB{
+		db:      db,
+		q:       db,
+		dialect: d,
+	}
+}
+
+// Transaction runs the given function within a transaction, which is
+// committed if the function returns nil and rolled back otherwise.
+//
+// The function is given a DB executing statements within the transaction,
+// and must only use that DB until it returns. If the DB is already within a
+// transaction, the function is run within it.
+func (db *DB) Transaction(fn func(tx *DB) error) error {
+	if _, ok := db.q.(*sql.Tx); ok {
+		return fn(db)
+	}
+
+	// Begin the transaction.
+	tx, err := db.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	// Roll back the transaction if the function panics,
+	// so it does not hold on to its connection.
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	// Run the function, rolling back
+	// the transaction if it fails.
+	if err := fn(&DB{db: db.db, q: tx, dialect: db.dialect}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Dialect returns the dialect of the database.
+func (db *DB) Dialect() Dialect {
+	return db.dialect
+}
+
+// Exec executes a statement that does not return rows.
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.q.Exec(db.dialect.Rebind(query), args...)
+}
+
+// Query executes a statement that returns rows.
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.q.Query(db.dialect.Rebind(query), args...)
+}
+
+// QueryRow executes a statement that returns at most one row.
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.q.QueryRow(db.dialect.Rebind(query), args...)
+}
+
+// Insert executes the given insert statement and returns the ID of the new
+// row.
+//
+// PostgreSQL does not support LastInsertId, so the ID is returned by the
+// statement itself using RETURNING id.
+func (db *DB) Insert(query string, args ...interface{}) (int, error) {
+	if db.dialect == Postgres {
+		var id int
+		if err := db.QueryRow(query+" RETURNING id", args...).Scan(&id); err != nil {
+			return 0, err
+		}
+
+		return id, nil
+	}
+
+	// Execute the query.
+	res, err := db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	// Get last insert ID.
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
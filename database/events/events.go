@@ -0,0 +1,94 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// ActionCreated is the action of the event recorded when a todo is
+	// created.
+	ActionCreated = "created"
+
+	// ActionUpdated is the action of the event recorded when a todo is
+	// updated.
+	ActionUpdated = "updated"
+
+	// ActionDeleted is the action of the event recorded when a todo is
+	// deleted.
+	ActionDeleted = "deleted"
+
+	// ActionRestored is the action of the event recorded when a todo is
+	// restored to a previous revision.
+	ActionRestored = "restored"
+)
+
+// Database defines the todo events database.
+//
+// Events make up the history of a todo, each one recording which member
+// changed which fields of the todo and when. Events are only ever added,
+// never deleted. The changes of the events of todos which are gone for good,
+// such as those of a deleted workspace or account, are cleared though, so
+// their content is not kept, while who changed them and when is.
+type Database interface {
+	// New creates a new event for the todo with the given ID, changed by
+	// the member with the given ID.
+	New(tid, mid int, params *NewParams) (*Event, error)
+
+	// GetByTodoID gets a set of the events of the todo with the given ID,
+	// oldest first.
+	GetByTodoID(tid int, params *GetParams) (*Events, error)
+
+	// GetByID retrieves an event by its ID.
+	GetByID(id int) (*Event, error)
+
+	// GetDeletedTodoIDsByMemberID gets the IDs of the todos the member
+	// with the given ID deleted.
+	GetDeletedTodoIDsByMemberID(mid int) ([]int, error)
+
+	// ClearChangesByTodoIDs clears the changes of every event of the
+	// todos with the given IDs.
+	ClearChangesByTodoIDs(tids []int) error
+}
+
+// Event defines an event.
+//
+// MemberID is the member who made the change, and Changes holds the
+// previous and new value of each field which changed, by field name.
+type Event struct {
+	ID       int                `json:"id"`
+	TodoID   int                `json:"todo_id"`
+	MemberID int                `json:"member_id"`
+	Action   string             `json:"action"`
+	Changes  map[string]*Change `json:"changes"`
+	Created  time.Time          `json:"created"`
+}
+
+// Change defines the change of a field of a todo, with both values encoded
+// as JSON. From is null for created todos, and To for deleted ones.
+type Change struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+// Events defines a set of events.
+type Events struct {
+	Events []*Event `json:"events"`
+	Total  int      `json:"total"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams struct {
+	Action  string             `json:"action"`
+	Changes map[string]*Change `json:"changes"`
+}
+
+// GetParams defines the parameters for the GetByTodoID method.
+//
+// MaxID only matches the events up to the event with the given ID. Every
+// event is returned when Limit is zero.
+type GetParams struct {
+	MaxID  *int `json:"max_id"`
+	Offset int  `json:"offset"`
+	Limit  int  `json:"limit"`
+}
@@ -0,0 +1,185 @@
+package events
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory defines the todo events database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits.
+type Memory struct {
+	mu     sync.RWMutex
+	lastID int
+	events map[int]*Event
+}
+
+// NewMemory creates a new in-memory todo events database.
+func NewMemory() *Memory {
+	return &Memory{
+		events: make(map[int]*Event),
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	events := make(map[int]*Event, len(m.events))
+	for id, v := range m.events {
+		c := *v
+		events[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.events = events
+	}
+}
+
+// New creates a new event for the todo with the given ID, changed by the
+// member with the given ID.
+func (m *Memory) New(tid, mid int, params *NewParams) (*Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Event.
+	m.lastID++
+	event := &Event{
+		ID:       m.lastID,
+		TodoID:   tid,
+		MemberID: mid,
+		Action:   params.Action,
+		Changes:  params.Changes,
+		Created:  time.Now(),
+	}
+	if event.Changes == nil {
+		event.Changes = map[string]*Change{}
+	}
+
+	// Store a copy of the event.
+	m.events[event.ID] = copyEvent(event)
+
+	return event, nil
+}
+
+// GetByTodoID gets a set of the events of the todo with the given ID,
+// oldest first.
+func (m *Memory) GetByTodoID(tid int, params *GetParams) (*Events, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Find the events matching the filters.
+	var matches []*Event
+	for _, event := range m.events {
+		if event.TodoID != tid {
+			continue
+		}
+		if params.MaxID != nil && event.ID > *params.MaxID {
+			continue
+		}
+
+		matches = append(matches, event)
+	}
+
+	// Sort the events by ID so pagination is stable.
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].ID < matches[j].ID
+	})
+
+	// Create a new Events.
+	events := &Events{
+		Events: []*Event{},
+		Total:  len(matches),
+	}
+
+	// Add copies of the requested page of events.
+	for i := params.Offset; i < len(matches) && (params.Limit <= 0 || i < params.Offset+params.Limit); i++ {
+		events.Events = append(events.Events, copyEvent(matches[i]))
+	}
+
+	return events, nil
+}
+
+// GetByID retrieves an event by its ID.
+func (m *Memory) GetByID(id int) (*Event, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	event, ok := m.events[id]
+	if !ok {
+		return nil, ErrEventNotFound
+	}
+
+	// Return a copy of the event.
+	return copyEvent(event), nil
+}
+
+// GetDeletedTodoIDsByMemberID gets the IDs of the todos the member with the
+// given ID deleted.
+func (m *Memory) GetDeletedTodoIDsByMemberID(mid int) ([]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Create a set of the todo IDs.
+	found := make(map[int]bool)
+	for _, event := range m.events {
+		if event.MemberID == mid && event.Action == ActionDeleted {
+			found[event.TodoID] = true
+		}
+	}
+
+	tids := []int{}
+	for tid := range found {
+		tids = append(tids, tid)
+	}
+	sort.Ints(tids)
+
+	return tids, nil
+}
+
+// ClearChangesByTodoIDs clears the changes of every event of the todos with
+// the given IDs.
+func (m *Memory) ClearChangesByTodoIDs(tids []int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a set of the todo IDs.
+	cleared := make(map[int]bool)
+	for _, tid := range tids {
+		cleared[tid] = true
+	}
+
+	for _, event := range m.events {
+		if cleared[event.TodoID] {
+			event.Changes = make(map[string]*Change)
+		}
+	}
+
+	return nil
+}
+
+// copyEvent returns a copy of the given event, so stored events never share
+// memory with the caller.
+func copyEvent(event *Event) *Event {
+	c := *event
+	c.Changes = make(map[string]*Change, len(event.Changes))
+	for name, change := range event.Changes {
+		c.Changes[name] = &Change{
+			From: append(json.RawMessage(nil), change.From...),
+			To:   append(json.RawMessage(nil), change.To...),
+		}
+	}
+	return &c
+}
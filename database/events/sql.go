@@ -0,0 +1,255 @@
+package events
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the todo events database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL todo events database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// columns defines the columns selected for
+	// an event, in the order they are scanned.
+	columns = `id, todo_id, member_id, action, changes, created`
+
+	// stmtInsert defines the SQL statement to
+	// insert a new event into the database.
+	stmtInsert = `
+INSERT INTO todo_events (todo_id, member_id, action, changes, created)
+VALUES (?, ?, ?, ?, ?)
+`
+
+	// stmtSelectByTodoID defines the SQL statement
+	// to select a set of events of a given todo.
+	stmtSelectByTodoID = `
+SELECT ` + columns + `
+FROM todo_events
+WHERE todo_id=?%s
+ORDER BY id
+%s
+`
+
+	// stmtSelectCountByTodoID defines the SQL
+	// statement to select the total number of
+	// events of a given todo.
+	stmtSelectCountByTodoID = `
+SELECT COUNT(*)
+FROM todo_events
+WHERE todo_id=?%s
+`
+
+	// stmtSelectByID defines the SQL statement to
+	// select an event by its ID.
+	stmtSelectByID = `
+SELECT ` + columns + `
+FROM todo_events
+WHERE id=?
+`
+
+	// stmtSelectDeletedTodoIDsByMemberID defines the
+	// SQL statement to select the IDs of the todos a
+	// given member deleted.
+	stmtSelectDeletedTodoIDsByMemberID = `
+SELECT DISTINCT todo_id
+FROM todo_events
+WHERE member_id=? AND action=?
+ORDER BY todo_id
+`
+
+	// stmtClearChangesByTodoIDs defines the SQL
+	// statement to clear the changes of every event
+	// of a set of todos.
+	stmtClearChangesByTodoIDs = `
+UPDATE todo_events
+SET changes='{}'
+WHERE todo_id IN (%s)
+`
+)
+
+// New creates a new event for the todo with the given ID, changed by the
+// member with the given ID.
+func (db *SQL) New(tid, mid int, params *NewParams) (*Event, error) {
+	// Create a new Event.
+	event := &Event{
+		TodoID:   tid,
+		MemberID: mid,
+		Action:   params.Action,
+		Changes:  params.Changes,
+		Created:  time.Now(),
+	}
+	if event.Changes == nil {
+		event.Changes = map[string]*Change{}
+	}
+
+	// Encode the changes.
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, event.TodoID, event.MemberID, event.Action, string(changes), event.Created)
+	if err != nil {
+		return nil, err
+	}
+	event.ID = id
+
+	return event, nil
+}
+
+// GetByTodoID gets a set of the events of the todo with the given ID,
+// oldest first.
+func (db *SQL) GetByTodoID(tid int, params *GetParams) (*Events, error) {
+	// Create variables to hold the query fields
+	// being filtered on and their values.
+	var queryFields string
+	queryValues := []interface{}{tid}
+
+	// Handle max ID field.
+	if params.MaxID != nil {
+		queryFields = " AND id<=?"
+		queryValues = append(queryValues, *params.MaxID)
+	}
+
+	// Handle offset and limit.
+	var limit string
+	if params.Limit > 0 {
+		limit = db.db.Dialect().Limit(params.Offset, params.Limit)
+	}
+
+	// Build the full query.
+	query := fmt.Sprintf(stmtSelectByTodoID, queryFields, limit)
+
+	// Create a new Events.
+	events := &Events{
+		Events: []*Event{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(query, queryValues...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the event rows.
+	for rows.Next() {
+		// Create a new Event.
+		event := &Event{}
+
+		// Scan row values into event struct.
+		if err := scan(rows, event); err != nil {
+			return nil, err
+		}
+
+		// Add to events set.
+		events.Events = append(events.Events, event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Build the total count query.
+	queryCount := fmt.Sprintf(stmtSelectCountByTodoID, queryFields)
+
+	// Get total count.
+	var total int
+	if err = db.db.QueryRow(queryCount, queryValues...).Scan(&total); err != nil {
+		return nil, err
+	}
+	events.Total = total
+
+	return events, nil
+}
+
+// GetByID retrieves an event by its ID.
+func (db *SQL) GetByID(id int) (*Event, error) {
+	// Create a new Event.
+	event := &Event{}
+
+	// Execute the query.
+	err := scan(db.db.QueryRow(stmtSelectByID, id), event)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrEventNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// GetDeletedTodoIDsByMemberID gets the IDs of the todos the member with the
+// given ID deleted.
+func (db *SQL) GetDeletedTodoIDsByMemberID(mid int) ([]int, error) {
+	// Execute the query.
+	rows, err := db.db.Query(stmtSelectDeletedTodoIDsByMemberID, mid, ActionDeleted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the todo ID rows.
+	tids := []int{}
+	for rows.Next() {
+		var tid int
+		if err := rows.Scan(&tid); err != nil {
+			return nil, err
+		}
+
+		tids = append(tids, tid)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tids, nil
+}
+
+// ClearChangesByTodoIDs clears the changes of every event of the todos with
+// the given IDs.
+func (db *SQL) ClearChangesByTodoIDs(tids []int) error {
+	// Check there is anything to clear.
+	if len(tids) == 0 {
+		return nil
+	}
+
+	// Get the IDs as query values.
+	queryValues := make([]interface{}, len(tids))
+	for i, tid := range tids {
+		queryValues[i] = tid
+	}
+
+	// Execute the query.
+	_, err := db.db.Exec(fmt.Sprintf(stmtClearChangesByTodoIDs, dialect.Placeholders(len(tids))), queryValues...)
+	return err
+}
+
+// scanner defines the Scan method shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan scans an event row into the given event, decoding its changes.
+func scan(row scanner, event *Event) error {
+	var changes string
+	if err := row.Scan(&event.ID, &event.TodoID, &event.MemberID, &event.Action, &changes, &event.Created); err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(changes), &event.Changes)
+}
@@ -0,0 +1,9 @@
+package invitations
+
+import "errors"
+
+var (
+	// ErrInvitationNotFound is returned when an invitation could not be
+	// found.
+	ErrInvitationNotFound = errors.New("Invitation could not be found")
+)
@@ -0,0 +1,59 @@
+package invitations
+
+import "time"
+
+// Database defines the invitations database.
+//
+// Invitations are sent to an email to join a workspace with a given role,
+// so people can be invited before they have an account. They are deleted
+// once accepted or declined.
+type Database interface {
+	// New creates a new invitation to the given workspace.
+	New(wid int, params *NewParams) (*Invitation, error)
+
+	// Get gets a set of invitations.
+	Get(params *GetParams) (*Invitations, error)
+
+	// GetByID retrieves an invitation by its ID.
+	GetByID(id int) (*Invitation, error)
+
+	// Delete deletes an invitation.
+	Delete(id int) error
+
+	// DeleteByWorkspaceID deletes every invitation to a given workspace.
+	DeleteByWorkspaceID(wid int) error
+
+	// DeleteByEmail deletes every invitation sent to a given email.
+	DeleteByEmail(email string) error
+}
+
+// Invitation defines an invitation to a workspace.
+//
+// InviterID is the ID of the member who sent the invitation.
+type Invitation struct {
+	ID          int       `json:"id"`
+	WorkspaceID int       `json:"workspace_id"`
+	InviterID   int       `json:"inviter_id"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	Created     time.Time `json:"created"`
+}
+
+// Invitations defines a set of invitations.
+type Invitations struct {
+	Invitations []*Invitation `json:"invitations"`
+	Total       int           `json:"total"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams struct {
+	InviterID int    `json:"inviter_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+}
+
+// GetParams defines the parameters for the Get method.
+type GetParams struct {
+	WorkspaceID *int    `json:"workspace_id"`
+	Email       *string `json:"email"`
+}
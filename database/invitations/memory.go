@@ -0,0 +1,159 @@
+package invitations
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory defines the invitations database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits.
+type Memory struct {
+	mu          sync.RWMutex
+	lastID      int
+	invitations map[int]*Invitation
+}
+
+// NewMemory creates a new in-memory invitations database.
+func NewMemory() *Memory {
+	return &Memory{
+		invitations: make(map[int]*Invitation),
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	invitations := make(map[int]*Invitation, len(m.invitations))
+	for id, v := range m.invitations {
+		c := *v
+		invitations[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.invitations = invitations
+	}
+}
+
+// New creates a new invitation to the given workspace.
+func (m *Memory) New(wid int, params *NewParams) (*Invitation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Invitation.
+	m.lastID++
+	invitation := &Invitation{
+		ID:          m.lastID,
+		WorkspaceID: wid,
+		InviterID:   params.InviterID,
+		Email:       params.Email,
+		Role:        params.Role,
+		Created:     time.Now(),
+	}
+
+	// Store a copy of the invitation.
+	stored := *invitation
+	m.invitations[invitation.ID] = &stored
+
+	return invitation, nil
+}
+
+// Get gets a set of invitations.
+func (m *Memory) Get(params *GetParams) (*Invitations, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Create a new Invitations.
+	invitations := &Invitations{
+		Invitations: []*Invitation{},
+	}
+
+	// Add copies of the invitations matching the filters.
+	for _, invitation := range m.invitations {
+		if params.WorkspaceID != nil && invitation.WorkspaceID != *params.WorkspaceID {
+			continue
+		}
+		if params.Email != nil && invitation.Email != *params.Email {
+			continue
+		}
+
+		found := *invitation
+		invitations.Invitations = append(invitations.Invitations, &found)
+	}
+	invitations.Total = len(invitations.Invitations)
+
+	// Sort the invitations by ID.
+	sort.Slice(invitations.Invitations, func(i, j int) bool {
+		return invitations.Invitations[i].ID < invitations.Invitations[j].ID
+	})
+
+	return invitations, nil
+}
+
+// GetByID retrieves an invitation by its ID.
+func (m *Memory) GetByID(id int) (*Invitation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	invitation, ok := m.invitations[id]
+	if !ok {
+		return nil, ErrInvitationNotFound
+	}
+
+	// Return a copy of the invitation.
+	found := *invitation
+	return &found, nil
+}
+
+// Delete deletes an invitation.
+func (m *Memory) Delete(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.invitations[id]; !ok {
+		return ErrInvitationNotFound
+	}
+	delete(m.invitations, id)
+
+	return nil
+}
+
+// DeleteByWorkspaceID deletes every invitation to a given workspace.
+func (m *Memory) DeleteByWorkspaceID(wid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, invitation := range m.invitations {
+		if invitation.WorkspaceID == wid {
+			delete(m.invitations, id)
+		}
+	}
+
+	return nil
+}
+
+// DeleteByEmail deletes every invitation sent to a given email.
+func (m *Memory) DeleteByEmail(email string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, invitation := range m.invitations {
+		if invitation.Email == email {
+			delete(m.invitations, id)
+		}
+	}
+
+	return nil
+}
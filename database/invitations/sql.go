@@ -0,0 +1,216 @@
+package invitations
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the invitations database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL invitations database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// columns defines the columns selected for an
+	// invitation, in the order they are scanned.
+	columns = `id, workspace_id, inviter_id, email, role, created`
+
+	// stmtInsert defines the SQL statement to
+	// insert a new invitation into the database.
+	stmtInsert = `
+INSERT INTO invitations (workspace_id, inviter_id, email, role, created)
+VALUES (?, ?, ?, ?, ?)
+`
+
+	// stmtSelect defines the SQL statement to
+	// select a set of invitations.
+	stmtSelect = `
+SELECT ` + columns + `
+FROM invitations
+%s
+ORDER BY id
+`
+
+	// stmtSelectByID defines the SQL statement to
+	// select an invitation by its ID.
+	stmtSelectByID = `
+SELECT ` + columns + `
+FROM invitations
+WHERE id=?
+`
+
+	// stmtDelete defines the SQL statement to
+	// delete an invitation.
+	stmtDelete = `
+DELETE FROM invitations
+WHERE id=?
+`
+
+	// stmtDeleteByWorkspaceID defines the SQL statement
+	// to delete every invitation to a given workspace.
+	stmtDeleteByWorkspaceID = `
+DELETE FROM invitations
+WHERE workspace_id=?
+`
+
+	// stmtDeleteByEmail defines the SQL statement to
+	// delete every invitation sent to a given email.
+	stmtDeleteByEmail = `
+DELETE FROM invitations
+WHERE email=?
+`
+)
+
+// New creates a new invitation to the given workspace.
+func (db *SQL) New(wid int, params *NewParams) (*Invitation, error) {
+	// Create a new Invitation.
+	invitation := &Invitation{
+		WorkspaceID: wid,
+		InviterID:   params.InviterID,
+		Email:       params.Email,
+		Role:        params.Role,
+		Created:     time.Now(),
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, invitation.WorkspaceID, invitation.InviterID, invitation.Email, invitation.Role, invitation.Created)
+	if err != nil {
+		return nil, err
+	}
+	invitation.ID = id
+
+	return invitation, nil
+}
+
+// Get gets a set of invitations.
+func (db *SQL) Get(params *GetParams) (*Invitations, error) {
+	// Create variables to hold the query fields
+	// being filtered on and their values.
+	var queryFields string
+	var queryValues []interface{}
+
+	// Handle workspace ID field.
+	if params.WorkspaceID != nil {
+		if queryFields == "" {
+			queryFields = "WHERE workspace_id=?"
+		} else {
+			queryFields += " AND workspace_id=?"
+		}
+
+		queryValues = append(queryValues, *params.WorkspaceID)
+	}
+
+	// Handle email field.
+	if params.Email != nil {
+		if queryFields == "" {
+			queryFields = "WHERE email=?"
+		} else {
+			queryFields += " AND email=?"
+		}
+
+		queryValues = append(queryValues, *params.Email)
+	}
+
+	// Create a new Invitations.
+	invitations := &Invitations{
+		Invitations: []*Invitation{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(fmt.Sprintf(stmtSelect, queryFields), queryValues...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the invitation rows.
+	for rows.Next() {
+		// Create a new Invitation.
+		invitation := &Invitation{}
+
+		// Scan row values into invitation struct.
+		if err := scan(rows, invitation); err != nil {
+			return nil, err
+		}
+
+		// Add to invitations set.
+		invitations.Invitations = append(invitations.Invitations, invitation)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	invitations.Total = len(invitations.Invitations)
+
+	return invitations, nil
+}
+
+// GetByID retrieves an invitation by its ID.
+func (db *SQL) GetByID(id int) (*Invitation, error) {
+	// Create a new Invitation.
+	invitation := &Invitation{}
+
+	// Execute the query.
+	err := scan(db.db.QueryRow(stmtSelectByID, id), invitation)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrInvitationNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// Delete deletes an invitation.
+func (db *SQL) Delete(id int) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtDelete, id)
+	if err != nil {
+		return err
+	}
+
+	// Check if an invitation was deleted.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// DeleteByWorkspaceID deletes every invitation to a given workspace.
+func (db *SQL) DeleteByWorkspaceID(wid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByWorkspaceID, wid)
+	return err
+}
+
+// DeleteByEmail deletes every invitation sent to a given email.
+func (db *SQL) DeleteByEmail(email string) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByEmail, email)
+	return err
+}
+
+// scanner defines the Scan method shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan scans an invitation row into the given invitation.
+func scan(row scanner, invitation *Invitation) error {
+	return row.Scan(&invitation.ID, &invitation.WorkspaceID, &invitation.InviterID, &invitation.Email, &invitation.Role, &invitation.Created)
+}
@@ -0,0 +1,8 @@
+package keys
+
+import "errors"
+
+var (
+	// ErrKeyNotFound is returned when an API key could not be found.
+	ErrKeyNotFound = errors.New("API key could not be found")
+)
@@ -0,0 +1,63 @@
+package keys
+
+import "time"
+
+// Database defines the API keys database.
+type Database interface {
+	// New creates a new API key.
+	New(mid int, params *NewParams) (*Key, error)
+
+	// GetByMemberID retrieves the API keys of a given member.
+	GetByMemberID(mid int) (*Keys, error)
+
+	// GetByID retrieves an API key by its ID.
+	GetByID(id int) (*Key, error)
+
+	// GetByIDAndMemberID retrieves an API key by its ID and member ID.
+	GetByIDAndMemberID(id, mid int) (*Key, error)
+
+	// GetByHash retrieves an API key by its hash.
+	GetByHash(hash string) (*Key, error)
+
+	// Update updates an API key.
+	Update(id int, params *UpdateParams) (*Key, error)
+
+	// DeleteByIDAndMemberID deletes an API key by its ID and member ID.
+	DeleteByIDAndMemberID(id, mid int) error
+
+	// DeleteByMemberID deletes every API key of a given member.
+	DeleteByMemberID(mid int) error
+}
+
+// Key defines an API key.
+//
+// Only the hash of the key itself is stored, the prefix is kept in plain
+// text so a member can tell their keys apart.
+type Key struct {
+	ID       int        `json:"id"`
+	MemberID int        `json:"member_id"`
+	Name     string     `json:"name"`
+	Prefix   string     `json:"prefix"`
+	Hash     string     `json:"-"`
+	Created  time.Time  `json:"created"`
+	LastUsed *time.Time `json:"last_used"`
+}
+
+// Keys defines a set of API keys.
+type Keys struct {
+	Keys  []*Key `json:"keys"`
+	Total int    `json:"total"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams struct {
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+	Hash   string `json:"hash"`
+}
+
+// UpdateParams defines the parameters for the Update method.
+type UpdateParams struct {
+	Name     *string    `json:"name"`
+	LastUsed *time.Time `json:"last_used"`
+}
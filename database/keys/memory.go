@@ -0,0 +1,196 @@
+package keys
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory defines the API keys database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits.
+type Memory struct {
+	mu     sync.RWMutex
+	lastID int
+	keys   map[int]*Key
+}
+
+// NewMemory creates a new in-memory API keys database.
+func NewMemory() *Memory {
+	return &Memory{
+		keys: make(map[int]*Key),
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	keys := make(map[int]*Key, len(m.keys))
+	for id, v := range m.keys {
+		c := *v
+		keys[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.keys = keys
+	}
+}
+
+// New creates a new API key.
+func (m *Memory) New(mid int, params *NewParams) (*Key, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Key.
+	m.lastID++
+	key := &Key{
+		ID:       m.lastID,
+		MemberID: mid,
+		Name:     params.Name,
+		Prefix:   params.Prefix,
+		Hash:     params.Hash,
+		Created:  time.Now(),
+	}
+
+	// Store a copy of the key.
+	stored := *key
+	m.keys[key.ID] = &stored
+
+	return key, nil
+}
+
+// GetByMemberID retrieves the API keys of a given member.
+func (m *Memory) GetByMemberID(mid int) (*Keys, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Create a new Keys.
+	keys := &Keys{
+		Keys: []*Key{},
+	}
+
+	// Add copies of the keys belonging to this member.
+	for _, key := range m.keys {
+		if key.MemberID == mid {
+			found := *key
+			keys.Keys = append(keys.Keys, &found)
+		}
+	}
+
+	// Sort the keys by ID.
+	sort.Slice(keys.Keys, func(i, j int) bool {
+		return keys.Keys[i].ID < keys.Keys[j].ID
+	})
+	keys.Total = len(keys.Keys)
+
+	return keys, nil
+}
+
+// GetByID retrieves an API key by its ID.
+func (m *Memory) GetByID(id int) (*Key, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key, ok := m.keys[id]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	// Return a copy of the key.
+	found := *key
+	return &found, nil
+}
+
+// GetByIDAndMemberID retrieves an API key by its ID and member ID.
+func (m *Memory) GetByIDAndMemberID(id, mid int) (*Key, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key, ok := m.keys[id]
+	if !ok || key.MemberID != mid {
+		return nil, ErrKeyNotFound
+	}
+
+	// Return a copy of the key.
+	found := *key
+	return &found, nil
+}
+
+// GetByHash retrieves an API key by its hash.
+func (m *Memory) GetByHash(hash string) (*Key, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.keys {
+		if key.Hash == hash {
+			// Return a copy of the key.
+			found := *key
+			return &found, nil
+		}
+	}
+
+	return nil, ErrKeyNotFound
+}
+
+// Update updates an API key.
+func (m *Memory) Update(id int, params *UpdateParams) (*Key, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.keys[id]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	// Handle the fields being updated.
+	if params.Name != nil {
+		key.Name = *params.Name
+	}
+	if params.LastUsed != nil {
+		lastUsed := *params.LastUsed
+		key.LastUsed = &lastUsed
+	}
+
+	// Return a copy of the key.
+	updated := *key
+	return &updated, nil
+}
+
+// DeleteByIDAndMemberID deletes an API key by its ID and member ID.
+func (m *Memory) DeleteByIDAndMemberID(id, mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.keys[id]
+	if !ok || key.MemberID != mid {
+		return ErrKeyNotFound
+	}
+	delete(m.keys, id)
+
+	return nil
+}
+
+// DeleteByMemberID deletes every API key of a given member.
+func (m *Memory) DeleteByMemberID(mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, key := range m.keys {
+		if key.MemberID == mid {
+			delete(m.keys, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package keys
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the API keys database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL API keys database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// stmtInsert defines the SQL statement to
+	// insert a new API key into the database.
+	stmtInsert = `
+INSERT INTO api_keys (member_id, name, prefix, hash, created)
+VALUES (?, ?, ?, ?, ?)
+`
+
+	// stmtSelectByMemberID defines the SQL statement
+	// to select the API keys of a given member.
+	stmtSelectByMemberID = `
+SELECT id, member_id, name, prefix, hash, created, last_used
+FROM api_keys
+WHERE member_id=?
+ORDER BY id
+`
+
+	// stmtSelectByID defines the SQL statement to
+	// select an API key by its ID.
+	stmtSelectByID = `
+SELECT id, member_id, name, prefix, hash, created, last_used
+FROM api_keys
+WHERE id=?
+`
+
+	// stmtSelectByIDAndMemberID defines the SQL statement
+	// to select an API key by its ID and member ID.
+	stmtSelectByIDAndMemberID = `
+SELECT id, member_id, name, prefix, hash, created, last_used
+FROM api_keys
+WHERE id=? AND member_id=?
+`
+
+	// stmtSelectByHash defines the SQL statement to
+	// select an API key by its hash.
+	stmtSelectByHash = `
+SELECT id, member_id, name, prefix, hash, created, last_used
+FROM api_keys
+WHERE hash=?
+`
+
+	// stmtUpdate defines the SQL statement to
+	// update an API key.
+	stmtUpdate = `
+UPDATE api_keys
+SET %s
+WHERE id=?
+`
+
+	// stmtDeleteByIDAndMemberID defines the SQL statement
+	// to delete an API key by its ID and member ID.
+	stmtDeleteByIDAndMemberID = `
+DELETE FROM api_keys
+WHERE id=? AND member_id=?
+`
+
+	// stmtDeleteByMemberID defines the SQL statement
+	// to delete every API key of a given member.
+	stmtDeleteByMemberID = `
+DELETE FROM api_keys
+WHERE member_id=?
+`
+)
+
+// New creates a new API key.
+func (db *SQL) New(mid int, params *NewParams) (*Key, error) {
+	// Create a new Key.
+	key := &Key{
+		MemberID: mid,
+		Name:     params.Name,
+		Prefix:   params.Prefix,
+		Hash:     params.Hash,
+		Created:  time.Now(),
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, key.MemberID, key.Name, key.Prefix, key.Hash, key.Created)
+	if err != nil {
+		return nil, err
+	}
+	key.ID = id
+
+	return key, nil
+}
+
+// GetByMemberID retrieves the API keys of a given member.
+func (db *SQL) GetByMemberID(mid int) (*Keys, error) {
+	// Create a new Keys.
+	keys := &Keys{
+		Keys: []*Key{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(stmtSelectByMemberID, mid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the key rows.
+	for rows.Next() {
+		// Create a new Key.
+		key := &Key{}
+
+		// Scan row values into key struct.
+		if err := rows.Scan(&key.ID, &key.MemberID, &key.Name, &key.Prefix, &key.Hash, &key.Created, &key.LastUsed); err != nil {
+			return nil, err
+		}
+
+		// Add to keys set.
+		keys.Keys = append(keys.Keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	keys.Total = len(keys.Keys)
+
+	return keys, nil
+}
+
+// GetByID retrieves an API key by its ID.
+func (db *SQL) GetByID(id int) (*Key, error) {
+	return db.getOne(stmtSelectByID, id)
+}
+
+// GetByIDAndMemberID retrieves an API key by its ID and member ID.
+func (db *SQL) GetByIDAndMemberID(id, mid int) (*Key, error) {
+	return db.getOne(stmtSelectByIDAndMemberID, id, mid)
+}
+
+// GetByHash retrieves an API key by its hash.
+func (db *SQL) GetByHash(hash string) (*Key, error) {
+	return db.getOne(stmtSelectByHash, hash)
+}
+
+// getOne retrieves a single API key using the given statement.
+func (db *SQL) getOne(stmt string, args ...interface{}) (*Key, error) {
+	// Create a new Key.
+	key := &Key{}
+
+	// Execute the query.
+	err := db.db.QueryRow(stmt, args...).Scan(&key.ID, &key.MemberID, &key.Name, &key.Prefix, &key.Hash, &key.Created, &key.LastUsed)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrKeyNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Update updates an API key.
+func (db *SQL) Update(id int, params *UpdateParams) (*Key, error) {
+	// Create variables to hold the query fields
+	// being updated and their new values.
+	var queryFields string
+	var queryValues []interface{}
+
+	// Handle name field.
+	if params.Name != nil {
+		if queryFields == "" {
+			queryFields = "name=?"
+		} else {
+			queryFields += ", name=?"
+		}
+
+		queryValues = append(queryValues, *params.Name)
+	}
+
+	// Handle last used field.
+	if params.LastUsed != nil {
+		if queryFields == "" {
+			queryFields = "last_used=?"
+		} else {
+			queryFields += ", last_used=?"
+		}
+
+		queryValues = append(queryValues, *params.LastUsed)
+	}
+
+	// Check if the query is empty.
+	if queryFields == "" {
+		return db.GetByID(id)
+	}
+
+	// Build the full query.
+	query := fmt.Sprintf(stmtUpdate, queryFields)
+	queryValues = append(queryValues, id)
+
+	// Execute the query.
+	_, err := db.db.Exec(query, queryValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetByID(id)
+}
+
+// DeleteByIDAndMemberID deletes an API key by its ID and member ID.
+func (db *SQL) DeleteByIDAndMemberID(id, mid int) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtDeleteByIDAndMemberID, id, mid)
+	if err != nil {
+		return err
+	}
+
+	// Check if a key was deleted.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrKeyNotFound
+	}
+
+	return nil
+}
+
+// DeleteByMemberID deletes every API key of a given member.
+func (db *SQL) DeleteByMemberID(mid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByMemberID, mid)
+	return err
+}
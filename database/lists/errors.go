@@ -0,0 +1,8 @@
+package lists
+
+import "errors"
+
+var (
+	// ErrListNotFound is returned when a list could not be found.
+	ErrListNotFound = errors.New("List could not be found")
+)
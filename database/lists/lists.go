@@ -0,0 +1,64 @@
+package lists
+
+import "time"
+
+// Database defines the lists database.
+type Database interface {
+	// New creates a new list.
+	New(mid int, params *NewParams) (*List, error)
+
+	// GetByMemberID retrieves the lists of a given member.
+	GetByMemberID(mid int) (*Lists, error)
+
+	// GetByWorkspaceID retrieves the lists of a given workspace.
+	GetByWorkspaceID(wid int) (*Lists, error)
+
+	// GetByID retrieves a list by its ID.
+	GetByID(id int) (*List, error)
+
+	// GetByIDAndMemberID retrieves a list by its ID and member ID.
+	GetByIDAndMemberID(id, mid int) (*List, error)
+
+	// Update updates a list.
+	Update(id int, params *UpdateParams) (*List, error)
+
+	// DeleteByIDAndMemberID deletes a list by its ID and member ID.
+	DeleteByIDAndMemberID(id, mid int) error
+
+	// DeleteByMemberID deletes every list of a given member which is not
+	// in a workspace.
+	DeleteByMemberID(mid int) error
+
+	// DeleteByWorkspaceID deletes every list of a given workspace.
+	DeleteByWorkspaceID(wid int) error
+}
+
+// List defines a todo list.
+//
+// Lists with a WorkspaceID belong to that workspace, while the MemberID is
+// the member who created them. Lists without one are in the personal space
+// of the member.
+type List struct {
+	ID          int       `json:"id"`
+	MemberID    int       `json:"member_id"`
+	WorkspaceID *int      `json:"workspace_id"`
+	Created     time.Time `json:"created"`
+	Name        string    `json:"name"`
+}
+
+// Lists defines a set of todo lists.
+type Lists struct {
+	Lists []*List `json:"lists"`
+	Total int     `json:"total"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams struct {
+	WorkspaceID *int   `json:"workspace_id"`
+	Name        string `json:"name"`
+}
+
+// UpdateParams defines the parameters for the Update method.
+type UpdateParams struct {
+	Name *string `json:"name"`
+}
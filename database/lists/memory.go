@@ -0,0 +1,227 @@
+package lists
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory defines the lists database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits.
+type Memory struct {
+	mu     sync.RWMutex
+	lastID int
+	lists  map[int]*List
+}
+
+// NewMemory creates a new in-memory lists database.
+func NewMemory() *Memory {
+	return &Memory{
+		lists: make(map[int]*List),
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	lists := make(map[int]*List, len(m.lists))
+	for id, v := range m.lists {
+		c := *v
+		lists[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.lists = lists
+	}
+}
+
+// New creates a new list.
+func (m *Memory) New(mid int, params *NewParams) (*List, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new List.
+	m.lastID++
+	list := &List{
+		ID:          m.lastID,
+		MemberID:    mid,
+		WorkspaceID: copyInt(params.WorkspaceID),
+		Created:     time.Now(),
+		Name:        params.Name,
+	}
+
+	// Store a copy of the list.
+	stored := *list
+	m.lists[list.ID] = &stored
+
+	return list, nil
+}
+
+// GetByMemberID retrieves the lists of a given member.
+func (m *Memory) GetByMemberID(mid int) (*Lists, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Create a new Lists.
+	lists := &Lists{
+		Lists: []*List{},
+	}
+
+	// Add copies of the lists belonging to this member.
+	for _, list := range m.lists {
+		if list.MemberID == mid {
+			found := *list
+			lists.Lists = append(lists.Lists, &found)
+		}
+	}
+
+	// Sort the lists by ID.
+	sort.Slice(lists.Lists, func(i, j int) bool {
+		return lists.Lists[i].ID < lists.Lists[j].ID
+	})
+	lists.Total = len(lists.Lists)
+
+	return lists, nil
+}
+
+// GetByWorkspaceID retrieves the lists of a given workspace.
+func (m *Memory) GetByWorkspaceID(wid int) (*Lists, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Create a new Lists.
+	lists := &Lists{
+		Lists: []*List{},
+	}
+
+	// Add copies of the lists belonging to this workspace.
+	for _, list := range m.lists {
+		if list.WorkspaceID != nil && *list.WorkspaceID == wid {
+			found := *list
+			lists.Lists = append(lists.Lists, &found)
+		}
+	}
+
+	// Sort the lists by ID.
+	sort.Slice(lists.Lists, func(i, j int) bool {
+		return lists.Lists[i].ID < lists.Lists[j].ID
+	})
+	lists.Total = len(lists.Lists)
+
+	return lists, nil
+}
+
+// GetByID retrieves a list by its ID.
+func (m *Memory) GetByID(id int) (*List, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list, ok := m.lists[id]
+	if !ok {
+		return nil, ErrListNotFound
+	}
+
+	// Return a copy of the list.
+	found := *list
+	return &found, nil
+}
+
+// GetByIDAndMemberID retrieves a list by its ID and member ID.
+func (m *Memory) GetByIDAndMemberID(id, mid int) (*List, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list, ok := m.lists[id]
+	if !ok || list.MemberID != mid {
+		return nil, ErrListNotFound
+	}
+
+	// Return a copy of the list.
+	found := *list
+	return &found, nil
+}
+
+// Update updates a list.
+func (m *Memory) Update(id int, params *UpdateParams) (*List, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list, ok := m.lists[id]
+	if !ok {
+		return nil, ErrListNotFound
+	}
+
+	// Handle the fields being updated.
+	if params.Name != nil {
+		list.Name = *params.Name
+	}
+
+	// Return a copy of the list.
+	updated := *list
+	return &updated, nil
+}
+
+// DeleteByIDAndMemberID deletes a list by its ID and member ID.
+func (m *Memory) DeleteByIDAndMemberID(id, mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list, ok := m.lists[id]
+	if !ok || list.MemberID != mid {
+		return ErrListNotFound
+	}
+	delete(m.lists, id)
+
+	return nil
+}
+
+// DeleteByMemberID deletes every list of a given member which is not in a
+// workspace.
+func (m *Memory) DeleteByMemberID(mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, list := range m.lists {
+		if list.MemberID == mid && list.WorkspaceID == nil {
+			delete(m.lists, id)
+		}
+	}
+
+	return nil
+}
+
+// DeleteByWorkspaceID deletes every list of a given workspace.
+func (m *Memory) DeleteByWorkspaceID(wid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, list := range m.lists {
+		if list.WorkspaceID != nil && *list.WorkspaceID == wid {
+			delete(m.lists, id)
+		}
+	}
+
+	return nil
+}
+
+// copyInt returns a copy of the given int.
+func copyInt(i *int) *int {
+	if i == nil {
+		return nil
+	}
+
+	c := *i
+	return &c
+}
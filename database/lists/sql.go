@@ -0,0 +1,271 @@
+package lists
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the lists database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL lists database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// columns defines the columns selected for
+	// a list, in the order they are scanned.
+	columns = `id, member_id, workspace_id, created, name`
+
+	// stmtInsert defines the SQL statement to
+	// insert a new list into the database.
+	stmtInsert = `
+INSERT INTO lists (member_id, workspace_id, created, name)
+VALUES (?, ?, ?, ?)
+`
+
+	// stmtSelectByMemberID defines the SQL statement
+	// to select the lists of a given member.
+	stmtSelectByMemberID = `
+SELECT ` + columns + `
+FROM lists
+WHERE member_id=?
+ORDER BY id
+`
+
+	// stmtSelectByWorkspaceID defines the SQL statement
+	// to select the lists of a given workspace.
+	stmtSelectByWorkspaceID = `
+SELECT ` + columns + `
+FROM lists
+WHERE workspace_id=?
+ORDER BY id
+`
+
+	// stmtSelectByID defines the SQL statement to
+	// select a list by its ID.
+	stmtSelectByID = `
+SELECT ` + columns + `
+FROM lists
+WHERE id=?
+`
+
+	// stmtSelectByIDAndMemberID defines the SQL statement
+	// to select a list by its ID and member ID.
+	stmtSelectByIDAndMemberID = `
+SELECT ` + columns + `
+FROM lists
+WHERE id=? AND member_id=?
+`
+
+	// stmtUpdate defines the SQL statement to
+	// update a list.
+	stmtUpdate = `
+UPDATE lists
+SET %s
+WHERE id=?
+`
+
+	// stmtDeleteByIDAndMemberID defines the SQL statement
+	// to delete a list by its ID and member ID.
+	stmtDeleteByIDAndMemberID = `
+DELETE FROM lists
+WHERE id=? AND member_id=?
+`
+
+	// stmtDeleteByMemberID defines the SQL statement
+	// to delete every list of a given member which
+	// is not in a workspace.
+	stmtDeleteByMemberID = `
+DELETE FROM lists
+WHERE member_id=? AND workspace_id IS NULL
+`
+
+	// stmtDeleteByWorkspaceID defines the SQL statement
+	// to delete every list of a given workspace.
+	stmtDeleteByWorkspaceID = `
+DELETE FROM lists
+WHERE workspace_id=?
+`
+)
+
+// New creates a new list.
+func (db *SQL) New(mid int, params *NewParams) (*List, error) {
+	// Create a new List.
+	list := &List{
+		MemberID:    mid,
+		WorkspaceID: params.WorkspaceID,
+		Created:     time.Now(),
+		Name:        params.Name,
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, list.MemberID, list.WorkspaceID, list.Created, list.Name)
+	if err != nil {
+		return nil, err
+	}
+	list.ID = id
+
+	return list, nil
+}
+
+// GetByMemberID retrieves the lists of a given member.
+func (db *SQL) GetByMemberID(mid int) (*Lists, error) {
+	return db.get(stmtSelectByMemberID, mid)
+}
+
+// GetByWorkspaceID retrieves the lists of a given workspace.
+func (db *SQL) GetByWorkspaceID(wid int) (*Lists, error) {
+	return db.get(stmtSelectByWorkspaceID, wid)
+}
+
+// get retrieves a set of lists using the given statement.
+func (db *SQL) get(stmt string, args ...interface{}) (*Lists, error) {
+	// Create a new Lists.
+	lists := &Lists{
+		Lists: []*List{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the list rows.
+	for rows.Next() {
+		// Create a new List.
+		list := &List{}
+
+		// Scan row values into list struct.
+		if err := scan(rows, list); err != nil {
+			return nil, err
+		}
+
+		// Add to lists set.
+		lists.Lists = append(lists.Lists, list)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	lists.Total = len(lists.Lists)
+
+	return lists, nil
+}
+
+// GetByID retrieves a list by its ID.
+func (db *SQL) GetByID(id int) (*List, error) {
+	return db.getOne(stmtSelectByID, id)
+}
+
+// GetByIDAndMemberID retrieves a list by its ID and member ID.
+func (db *SQL) GetByIDAndMemberID(id, mid int) (*List, error) {
+	return db.getOne(stmtSelectByIDAndMemberID, id, mid)
+}
+
+// getOne retrieves a single list using the given statement.
+func (db *SQL) getOne(stmt string, args ...interface{}) (*List, error) {
+	// Create a new List.
+	list := &List{}
+
+	// Execute the query.
+	err := scan(db.db.QueryRow(stmt, args...), list)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrListNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Update updates a list.
+func (db *SQL) Update(id int, params *UpdateParams) (*List, error) {
+	// Create variables to hold the query fields
+	// being updated and their new values.
+	var queryFields string
+	var queryValues []interface{}
+
+	// Handle name field.
+	if params.Name != nil {
+		if queryFields == "" {
+			queryFields = "name=?"
+		} else {
+			queryFields += ", name=?"
+		}
+
+		queryValues = append(queryValues, *params.Name)
+	}
+
+	// Check if the query is empty.
+	if queryFields == "" {
+		return db.GetByID(id)
+	}
+
+	// Build the full query.
+	query := fmt.Sprintf(stmtUpdate, queryFields)
+	queryValues = append(queryValues, id)
+
+	// Execute the query.
+	_, err := db.db.Exec(query, queryValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetByID(id)
+}
+
+// DeleteByIDAndMemberID deletes a list by its ID and member ID.
+func (db *SQL) DeleteByIDAndMemberID(id, mid int) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtDeleteByIDAndMemberID, id, mid)
+	if err != nil {
+		return err
+	}
+
+	// Check if a list was deleted.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrListNotFound
+	}
+
+	return nil
+}
+
+// DeleteByMemberID deletes every list of a given member which is not in a
+// workspace.
+func (db *SQL) DeleteByMemberID(mid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByMemberID, mid)
+	return err
+}
+
+// DeleteByWorkspaceID deletes every list of a given workspace.
+func (db *SQL) DeleteByWorkspaceID(wid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByWorkspaceID, wid)
+	return err
+}
+
+// scanner defines the Scan method shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan scans a row selected using the columns constant into a list.
+func scan(row scanner, list *List) error {
+	return row.Scan(&list.ID, &list.MemberID, &list.WorkspaceID, &list.Created, &list.Name)
+}
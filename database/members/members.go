@@ -1,50 +1,81 @@
 package members
 
-import "database/sql"
+import "time"
 
 // Database defines the members database.
-type Database struct {
-	db *sql.DB
-}
+type Database interface {
+	// New creates a new member.
+	New(params *NewParams) (*Member, error)
+
+	// Get retrieves a set of members.
+	Get(params *GetParams) (*Members, error)
+
+	// GetByID retrieves a member by their ID.
+	GetByID(id int) (*Member, error)
+
+	// GetByEmail retrieves a member by their email.
+	GetByEmail(email string) (*Member, error)
+
+	// GetByDeleteAtBefore retrieves the members whose account is set to be
+	// deleted at or before the given time.
+	GetByDeleteAtBefore(t time.Time) ([]*Member, error)
 
-// New creates a new members database.
-func New(db *sql.DB) *Database {
-	return &Database{
-		db: db,
-	}
+	// Update updates a member.
+	Update(id int, params *UpdateParams) (*Member, error)
+
+	// Delete deletes a member.
+	Delete(id int) error
 }
 
+// DefaultTimezone is the timezone of new members.
+const DefaultTimezone = "UTC"
+
+const (
+	// RoleMember is the role of regular members, which is the role of new
+	// members.
+	RoleMember = "member"
+
+	// RoleAdmin is the role of the administrators, who can manage the
+	// other members.
+	RoleAdmin = "admin"
+
+	// RoleReadOnly is the role of the members who can read but not change
+	// their todos, lists, tags and series.
+	RoleReadOnly = "read-only"
+)
+
 // Member defines a member.
+//
+// TOTPSecret is set once the member starts enrolling in two-factor
+// authentication, which is enabled once TOTPEnabledAt is set. TOTPCounter
+// is the time step of the last TOTP code used, so codes cannot be used
+// twice.
+//
+// DeleteAt is set once the member closes their account, which is then
+// deleted after a grace period.
+//
+// SuspendedAt is set once an administrator suspends the member, who cannot
+// log in or use the API until the suspension is lifted.
 type Member struct {
-	ID       int    `json:"id"`
-	Email    string `json:"email"`
-	Password string `json:"-"`
+	ID            int        `json:"id"`
+	Email         string     `json:"email"`
+	Password      string     `json:"-"`
+	VerifiedAt    *time.Time `json:"verified_at"`
+	TOTPSecret    *string    `json:"-"`
+	TOTPEnabledAt *time.Time `json:"totp_enabled_at"`
+	TOTPCounter   int64      `json:"-"`
+	DisplayName   string     `json:"display_name"`
+	Timezone      string     `json:"timezone"`
+	DeleteAt      *time.Time `json:"delete_at"`
+	Role          string     `json:"role"`
+	SuspendedAt   *time.Time `json:"suspended_at"`
 }
 
-const (
-	// stmtInsert defines the SQL statement to
-	// insert a new member into the database.
-	stmtInsert = `
-INSERT INTO members (email, password)
-VALUES (?, ?)
-`
-
-	// stmtSelectByID defines the SQL statement to
-	// select a member by their ID.
-	stmtSelectByID = `
-SELECT id, email, password
-FROM members
-WHERE id=?
-`
-
-	// stmtSelectByEmail defines the SQL statement
-	// to select a member by their email address.
-	stmtSelectByEmail = `
-SELECT id, email, password
-FROM members
-WHERE email=?
-`
-)
+// Members defines a set of members.
+type Members struct {
+	Members []*Member `json:"members"`
+	Total   int       `json:"total"`
+}
 
 // NewParams defines the parameters for the New method.
 type NewParams struct {
@@ -52,63 +83,35 @@ type NewParams struct {
 	Password string `json:"password"`
 }
 
-// New creates a new member.
-func (db *Database) New(params *NewParams) (*Member, error) {
-	// Create a new Member.
-	member := &Member{
-		Email:    params.Email,
-		Password: params.Password,
-	}
-
-	// Create variable to hold the result.
-	var res sql.Result
-	var err error
-
-	// Execute the query.
-	if res, err = db.db.Exec(stmtInsert, member.Email, member.Password); err != nil {
-		return nil, err
-	}
-
-	// Get last insert ID.
-	id, err := res.LastInsertId()
-	if err != nil {
-		return nil, err
-	}
-	member.ID = int(id)
-
-	return member, nil
-}
-
-// GetByID retrieves a member by their ID.
-func (db *Database) GetByID(id int) (*Member, error) {
-	// Create a new Member.
-	member := &Member{}
-
-	// Execute the query.
-	err := db.db.QueryRow(stmtSelectByID, id).Scan(&member.ID, &member.Email, &member.Password)
-	switch {
-	case err == sql.ErrNoRows:
-		return nil, ErrMemberNotFound
-	case err != nil:
-		return nil, err
-	}
-
-	return member, nil
+// GetParams defines the parameters for the Get method.
+type GetParams struct {
+	Role      *string `json:"role"`
+	Suspended *bool   `json:"suspended"`
+	Offset    int     `json:"offset"`
+	Limit     int     `json:"limit"`
 }
 
-// GetByEmail retrieves a member by their email.
-func (db *Database) GetByEmail(email string) (*Member, error) {
-	// Create a new Member.
-	member := &Member{}
-
-	// Execute the query.
-	err := db.db.QueryRow(stmtSelectByEmail, email).Scan(&member.ID, &member.Email, &member.Password)
-	switch {
-	case err == sql.ErrNoRows:
-		return nil, ErrMemberNotFound
-	case err != nil:
-		return nil, err
-	}
-
-	return member, nil
+// UpdateParams defines the parameters for the Update method.
+//
+// The ClearVerifiedAt field marks the member as unverified again, and the
+// ClearTOTPSecret and ClearTOTPEnabledAt fields remove two-factor
+// authentication. The ClearDeleteAt field cancels the deletion of the
+// account, and the ClearSuspendedAt field lifts a suspension.
+type UpdateParams struct {
+	Email              *string    `json:"email"`
+	Password           *string    `json:"password"`
+	VerifiedAt         *time.Time `json:"verified_at"`
+	ClearVerifiedAt    bool       `json:"clear_verified_at"`
+	TOTPSecret         *string    `json:"totp_secret"`
+	ClearTOTPSecret    bool       `json:"clear_totp_secret"`
+	TOTPEnabledAt      *time.Time `json:"totp_enabled_at"`
+	ClearTOTPEnabledAt bool       `json:"clear_totp_enabled_at"`
+	TOTPCounter        *int64     `json:"totp_counter"`
+	DisplayName        *string    `json:"display_name"`
+	Timezone           *string    `json:"timezone"`
+	DeleteAt           *time.Time `json:"delete_at"`
+	ClearDeleteAt      bool       `json:"clear_delete_at"`
+	Role               *string    `json:"role"`
+	SuspendedAt        *time.Time `json:"suspended_at"`
+	ClearSuspendedAt   bool       `json:"clear_suspended_at"`
 }
@@ -0,0 +1,249 @@
+package members
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memory defines the members database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits.
+type Memory struct {
+	mu      sync.RWMutex
+	lastID  int
+	members map[int]*Member
+}
+
+// NewMemory creates a new in-memory members database.
+func NewMemory() *Memory {
+	return &Memory{
+		members: make(map[int]*Member),
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	members := make(map[int]*Member, len(m.members))
+	for id, v := range m.members {
+		c := *v
+		members[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.members = members
+	}
+}
+
+// New creates a new member.
+func (m *Memory) New(params *NewParams) (*Member, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Member.
+	m.lastID++
+	member := &Member{
+		ID:       m.lastID,
+		Email:    params.Email,
+		Password: params.Password,
+		Timezone: DefaultTimezone,
+		Role:     RoleMember,
+	}
+
+	// Store a copy of the member.
+	stored := *member
+	m.members[member.ID] = &stored
+
+	return member, nil
+}
+
+// Get retrieves a set of members.
+func (m *Memory) Get(params *GetParams) (*Members, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Find all of the members matching the filters.
+	var matches []*Member
+	for _, member := range m.members {
+		if params.Role != nil && member.Role != *params.Role {
+			continue
+		}
+		if params.Suspended != nil && (member.SuspendedAt != nil) != *params.Suspended {
+			continue
+		}
+
+		matches = append(matches, member)
+	}
+
+	// Sort the members by ID so pagination is stable.
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].ID < matches[j].ID
+	})
+
+	// Create a new Members.
+	members := &Members{
+		Members: []*Member{},
+		Total:   len(matches),
+	}
+
+	// Add copies of the requested page of members.
+	for i := params.Offset; i < len(matches) && i < params.Offset+params.Limit; i++ {
+		member := *matches[i]
+		members.Members = append(members.Members, &member)
+	}
+
+	return members, nil
+}
+
+// GetByID retrieves a member by their ID.
+func (m *Memory) GetByID(id int) (*Member, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	member, ok := m.members[id]
+	if !ok {
+		return nil, ErrMemberNotFound
+	}
+
+	// Return a copy of the member.
+	found := *member
+	return &found, nil
+}
+
+// GetByEmail retrieves a member by their email.
+//
+// Emails are matched case-insensitively, as with the collation used by the
+// SQL schema.
+func (m *Memory) GetByEmail(email string) (*Member, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, member := range m.members {
+		if strings.EqualFold(member.Email, email) {
+			// Return a copy of the member.
+			found := *member
+			return &found, nil
+		}
+	}
+
+	return nil, ErrMemberNotFound
+}
+
+// GetByDeleteAtBefore retrieves the members whose account is set to be
+// deleted at or before the given time.
+func (m *Memory) GetByDeleteAtBefore(t time.Time) ([]*Member, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	members := []*Member{}
+	for _, member := range m.members {
+		if member.DeleteAt != nil && !member.DeleteAt.After(t) {
+			// Add a copy of the member.
+			found := *member
+			members = append(members, &found)
+		}
+	}
+
+	// Sort the members by their ID.
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID < members[j].ID
+	})
+
+	return members, nil
+}
+
+// Update updates a member.
+func (m *Memory) Update(id int, params *UpdateParams) (*Member, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	member, ok := m.members[id]
+	if !ok {
+		return nil, ErrMemberNotFound
+	}
+
+	// Handle the fields being updated.
+	if params.Email != nil {
+		member.Email = *params.Email
+	}
+	if params.Password != nil {
+		member.Password = *params.Password
+	}
+	if params.VerifiedAt != nil {
+		verifiedAt := *params.VerifiedAt
+		member.VerifiedAt = &verifiedAt
+	}
+	if params.ClearVerifiedAt {
+		member.VerifiedAt = nil
+	}
+	if params.TOTPSecret != nil {
+		secret := *params.TOTPSecret
+		member.TOTPSecret = &secret
+	}
+	if params.ClearTOTPSecret {
+		member.TOTPSecret = nil
+	}
+	if params.TOTPEnabledAt != nil {
+		enabledAt := *params.TOTPEnabledAt
+		member.TOTPEnabledAt = &enabledAt
+	}
+	if params.ClearTOTPEnabledAt {
+		member.TOTPEnabledAt = nil
+	}
+	if params.TOTPCounter != nil {
+		member.TOTPCounter = *params.TOTPCounter
+	}
+	if params.DisplayName != nil {
+		member.DisplayName = *params.DisplayName
+	}
+	if params.Timezone != nil {
+		member.Timezone = *params.Timezone
+	}
+	if params.DeleteAt != nil {
+		deleteAt := *params.DeleteAt
+		member.DeleteAt = &deleteAt
+	}
+	if params.ClearDeleteAt {
+		member.DeleteAt = nil
+	}
+	if params.Role != nil {
+		member.Role = *params.Role
+	}
+	if params.SuspendedAt != nil {
+		suspendedAt := *params.SuspendedAt
+		member.SuspendedAt = &suspendedAt
+	}
+	if params.ClearSuspendedAt {
+		member.SuspendedAt = nil
+	}
+
+	// Return a copy of the member.
+	updated := *member
+	return &updated, nil
+}
+
+// Delete deletes a member.
+func (m *Memory) Delete(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.members[id]; !ok {
+		return ErrMemberNotFound
+	}
+	delete(m.members, id)
+
+	return nil
+}
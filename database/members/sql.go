@@ -0,0 +1,439 @@
+package members
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the members database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL members database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// columns defines the columns selected for
+	// a member, in the order they are scanned.
+	columns = `id, email, password, verified_at, totp_secret, totp_enabled_at, totp_counter, display_name, timezone, delete_at, role, suspended_at`
+
+	// stmtInsert defines the SQL statement to
+	// insert a new member into the database.
+	stmtInsert = `
+INSERT INTO members (email, password, timezone, role)
+VALUES (?, ?, ?, ?)
+`
+
+	// stmtSelect defines the SQL statement to
+	// select a set of members.
+	stmtSelect = `
+SELECT ` + columns + `
+FROM members
+%s
+ORDER BY id
+%s
+`
+
+	// stmtSelectCount defines the SQL statement to
+	// select the total number of members found,
+	// according to the filters.
+	stmtSelectCount = `
+SELECT COUNT(*)
+FROM members
+%s
+`
+
+	// stmtSelectByID defines the SQL statement to
+	// select a member by their ID.
+	stmtSelectByID = `
+SELECT ` + columns + `
+FROM members
+WHERE id=?
+`
+
+	// stmtSelectByEmail defines the SQL statement
+	// to select a member by their email address.
+	stmtSelectByEmail = `
+SELECT ` + columns + `
+FROM members
+WHERE email=?
+`
+
+	// stmtSelectByDeleteAtBefore defines the SQL
+	// statement to select the members whose account
+	// is set to be deleted at or before a given time.
+	stmtSelectByDeleteAtBefore = `
+SELECT ` + columns + `
+FROM members
+WHERE delete_at<=?
+ORDER BY id
+`
+
+	// stmtUpdate defines the SQL statement to
+	// update a member.
+	stmtUpdate = `
+UPDATE members
+SET %s
+WHERE id=?
+`
+
+	// stmtDelete defines the SQL statement to
+	// delete a member.
+	stmtDelete = `
+DELETE FROM members
+WHERE id=?
+`
+)
+
+// New creates a new member.
+func (db *SQL) New(params *NewParams) (*Member, error) {
+	// Create a new Member.
+	member := &Member{
+		Email:    params.Email,
+		Password: params.Password,
+		Timezone: DefaultTimezone,
+		Role:     RoleMember,
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, member.Email, member.Password, member.Timezone, member.Role)
+	if err != nil {
+		return nil, err
+	}
+	member.ID = id
+
+	return member, nil
+}
+
+// Get retrieves a set of members.
+func (db *SQL) Get(params *GetParams) (*Members, error) {
+	// Create variables to hold the query fields
+	// being filtered on and their values.
+	var queryFields string
+	var queryValues []interface{}
+
+	// Handle role field.
+	if params.Role != nil {
+		if queryFields == "" {
+			queryFields = "WHERE role=?"
+		} else {
+			queryFields += " AND role=?"
+		}
+
+		queryValues = append(queryValues, *params.Role)
+	}
+
+	// Handle suspended field.
+	if params.Suspended != nil {
+		filter := "suspended_at IS NOT NULL"
+		if !*params.Suspended {
+			filter = "suspended_at IS NULL"
+		}
+
+		if queryFields == "" {
+			queryFields = "WHERE " + filter
+		} else {
+			queryFields += " AND " + filter
+		}
+	}
+
+	// Build the full query.
+	query := fmt.Sprintf(stmtSelect, queryFields, db.db.Dialect().Limit(params.Offset, params.Limit))
+
+	// Create a new Members.
+	members := &Members{
+		Members: []*Member{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(query, queryValues...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the member rows.
+	for rows.Next() {
+		// Create a new Member.
+		member := &Member{}
+
+		// Scan row values into member struct.
+		if err := scan(rows, member); err != nil {
+			return nil, err
+		}
+
+		// Add to members set.
+		members.Members = append(members.Members, member)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Build the total count query.
+	queryCount := fmt.Sprintf(stmtSelectCount, queryFields)
+
+	// Get total count.
+	var total int
+	if err = db.db.QueryRow(queryCount, queryValues...).Scan(&total); err != nil {
+		return nil, err
+	}
+	members.Total = total
+
+	return members, nil
+}
+
+// GetByID retrieves a member by their ID.
+func (db *SQL) GetByID(id int) (*Member, error) {
+	// Create a new Member.
+	member := &Member{}
+
+	// Execute the query.
+	err := scan(db.db.QueryRow(stmtSelectByID, id), member)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrMemberNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// GetByEmail retrieves a member by their email.
+func (db *SQL) GetByEmail(email string) (*Member, error) {
+	// Create a new Member.
+	member := &Member{}
+
+	// Execute the query.
+	err := scan(db.db.QueryRow(stmtSelectByEmail, email), member)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrMemberNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// GetByDeleteAtBefore retrieves the members whose account is set to be
+// deleted at or before the given time.
+func (db *SQL) GetByDeleteAtBefore(t time.Time) ([]*Member, error) {
+	// Execute the query.
+	rows, err := db.db.Query(stmtSelectByDeleteAtBefore, t.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the member rows.
+	members := []*Member{}
+	for rows.Next() {
+		// Create a new Member.
+		member := &Member{}
+
+		// Scan row values into member struct.
+		if err := scan(rows, member); err != nil {
+			return nil, err
+		}
+
+		// Add to members slice.
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// Update updates a member.
+func (db *SQL) Update(id int, params *UpdateParams) (*Member, error) {
+	// Create variables to hold the query fields
+	// being updated and their new values.
+	var queryFields string
+	var queryValues []interface{}
+
+	// Handle email field.
+	if params.Email != nil {
+		if queryFields == "" {
+			queryFields = "email=?"
+		} else {
+			queryFields += ", email=?"
+		}
+
+		queryValues = append(queryValues, *params.Email)
+	}
+
+	// Handle password field.
+	if params.Password != nil {
+		if queryFields == "" {
+			queryFields = "password=?"
+		} else {
+			queryFields += ", password=?"
+		}
+
+		queryValues = append(queryValues, *params.Password)
+	}
+
+	// Handle verified at field.
+	if params.VerifiedAt != nil || params.ClearVerifiedAt {
+		if queryFields == "" {
+			queryFields = "verified_at=?"
+		} else {
+			queryFields += ", verified_at=?"
+		}
+
+		if params.ClearVerifiedAt {
+			queryValues = append(queryValues, nil)
+		} else {
+			queryValues = append(queryValues, params.VerifiedAt.UTC())
+		}
+	}
+
+	// Handle TOTP secret field.
+	if params.TOTPSecret != nil || params.ClearTOTPSecret {
+		if queryFields == "" {
+			queryFields = "totp_secret=?"
+		} else {
+			queryFields += ", totp_secret=?"
+		}
+
+		if params.ClearTOTPSecret {
+			queryValues = append(queryValues, nil)
+		} else {
+			queryValues = append(queryValues, *params.TOTPSecret)
+		}
+	}
+
+	// Handle TOTP enabled at field.
+	if params.TOTPEnabledAt != nil || params.ClearTOTPEnabledAt {
+		if queryFields == "" {
+			queryFields = "totp_enabled_at=?"
+		} else {
+			queryFields += ", totp_enabled_at=?"
+		}
+
+		if params.ClearTOTPEnabledAt {
+			queryValues = append(queryValues, nil)
+		} else {
+			queryValues = append(queryValues, params.TOTPEnabledAt.UTC())
+		}
+	}
+
+	// Handle TOTP counter field.
+	if params.TOTPCounter != nil {
+		if queryFields == "" {
+			queryFields = "totp_counter=?"
+		} else {
+			queryFields += ", totp_counter=?"
+		}
+
+		queryValues = append(queryValues, *params.TOTPCounter)
+	}
+
+	// Handle display name field.
+	if params.DisplayName != nil {
+		if queryFields == "" {
+			queryFields = "display_name=?"
+		} else {
+			queryFields += ", display_name=?"
+		}
+
+		queryValues = append(queryValues, *params.DisplayName)
+	}
+
+	// Handle timezone field.
+	if params.Timezone != nil {
+		if queryFields == "" {
+			queryFields = "timezone=?"
+		} else {
+			queryFields += ", timezone=?"
+		}
+
+		queryValues = append(queryValues, *params.Timezone)
+	}
+
+	// Handle delete at field.
+	if params.DeleteAt != nil || params.ClearDeleteAt {
+		if queryFields == "" {
+			queryFields = "delete_at=?"
+		} else {
+			queryFields += ", delete_at=?"
+		}
+
+		if params.ClearDeleteAt {
+			queryValues = append(queryValues, nil)
+		} else {
+			queryValues = append(queryValues, params.DeleteAt.UTC())
+		}
+	}
+
+	// Handle role field.
+	if params.Role != nil {
+		if queryFields == "" {
+			queryFields = "role=?"
+		} else {
+			queryFields += ", role=?"
+		}
+
+		queryValues = append(queryValues, *params.Role)
+	}
+
+	// Handle suspended at field.
+	if params.SuspendedAt != nil || params.ClearSuspendedAt {
+		if queryFields == "" {
+			queryFields = "suspended_at=?"
+		} else {
+			queryFields += ", suspended_at=?"
+		}
+
+		if params.ClearSuspendedAt {
+			queryValues = append(queryValues, nil)
+		} else {
+			queryValues = append(queryValues, params.SuspendedAt.UTC())
+		}
+	}
+
+	// Check if the query is empty.
+	if queryFields == "" {
+		return db.GetByID(id)
+	}
+
+	// Build the full query.
+	query := fmt.Sprintf(stmtUpdate, queryFields)
+	queryValues = append(queryValues, id)
+
+	// Execute the query.
+	_, err := db.db.Exec(query, queryValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetByID(id)
+}
+
+// Delete deletes a member.
+func (db *SQL) Delete(id int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDelete, id)
+	return err
+}
+
+// scanner defines the Scan method shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan scans a row selected using the columns constant into a member.
+func scan(row scanner, member *Member) error {
+	return row.Scan(&member.ID, &member.Email, &member.Password, &member.VerifiedAt, &member.TOTPSecret, &member.TOTPEnabledAt, &member.TOTPCounter, &member.DisplayName, &member.Timezone, &member.DeleteAt, &member.Role, &member.SuspendedAt)
+}
@@ -0,0 +1,387 @@
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gotodo/database"
+	"gotodo/database/dialect"
+)
+
+// files holds the migration files of every supported database driver.
+//
+// Migrations are named <version>_<name>.up.sql and <version>_<name>.down.sql
+// and live in a directory named after the driver they are written for.
+//
+//go:embed mysql/*.sql sqlite/*.sql postgres/*.sql
+var files embed.FS
+
+// dirs maps each database driver to its migrations directory.
+var dirs = map[string]string{
+	database.DriverMySQL:    "mysql",
+	database.DriverSQLite:   "sqlite",
+	database.DriverPostgres: "postgres",
+}
+
+const (
+	// stmtCreateTable defines the SQL statement to
+	// create the migrations tracking table.
+	stmtCreateTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version integer NOT NULL PRIMARY KEY,
+  applied timestamp NOT NULL
+)
+`
+
+	// stmtSelectApplied defines the SQL statement to
+	// select the applied migrations.
+	stmtSelectApplied = `
+SELECT version, applied
+FROM schema_migrations
+`
+
+	// stmtInsert defines the SQL statement to
+	// record an applied migration.
+	stmtInsert = `
+INSERT INTO schema_migrations (version, applied)
+VALUES (?, ?)
+`
+
+	// stmtDelete defines the SQL statement to
+	// remove a rolled back migration.
+	stmtDelete = `
+DELETE FROM schema_migrations
+WHERE version=?
+`
+)
+
+// Migration defines a single schema migration.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status defines the status of a migration.
+type Status struct {
+	Version int
+	Name    string
+	Applied *time.Time
+}
+
+// Migrator applies and rolls back the schema migrations of a database.
+type Migrator struct {
+	db         *sql.DB
+	dialect    dialect.Dialect
+	migrations []*Migration
+}
+
+// New returns a new Migrator for the given database, which was opened using
+// the given driver.
+func New(driver string, db *sql.DB) (*Migrator, error) {
+	// Get the migrations directory of the driver.
+	dir, ok := dirs[driver]
+	if !ok {
+		return nil, fmt.Errorf("No migrations exist for database driver %s", driver)
+	}
+
+	// Load the migrations.
+	migrations, err := load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the SQL dialect of the driver.
+	d := dialect.MySQL
+	if driver == database.DriverPostgres {
+		d = dialect.Postgres
+	}
+
+	return &Migrator{
+		db:         db,
+		dialect:    d,
+		migrations: migrations,
+	}, nil
+}
+
+// load loads the migrations within the given directory, sorted by version.
+func load(dir string) ([]*Migration, error) {
+	entries, err := files.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Loop through the migration files.
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		// Parse the file name, e.g. 0001_init.up.sql.
+		name := strings.TrimSuffix(entry.Name(), ".sql")
+		ext := path.Ext(name)
+		name = strings.TrimSuffix(name, ext)
+		parts := strings.SplitN(name, "_", 2)
+		if len(parts) != 2 || (ext != ".up" && ext != ".down") {
+			return nil, fmt.Errorf("Invalid migration file name %s", entry.Name())
+		}
+		version, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid migration file name %s", entry.Name())
+		}
+
+		// Read the migration file.
+		b, err := files.ReadFile(dir + "/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		// Get or create the Migration.
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{
+				Version: version,
+				Name:    parts[1],
+			}
+			byVersion[version] = m
+		}
+
+		if ext == ".up" {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+
+	// Sort the migrations by version.
+	var migrations []*Migration
+	for _, m := range byVersion {
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Latest returns the version of the newest migration.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the version of the newest applied migration, or 0 if no
+// migrations have been applied.
+func (m *Migrator) Version() (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+
+	var version int
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+
+	return version, nil
+}
+
+// Pending returns the number of migrations that have not been applied.
+func (m *Migrator) Pending() (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+
+	var pending int
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
+
+// Status returns the status of every migration.
+func (m *Migrator) Status() ([]*Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []*Status
+	for _, migration := range m.migrations {
+		status := &Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+		if t, ok := applied[migration.Version]; ok {
+			status.Applied = &t
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Up applies all of the pending migrations.
+func (m *Migrator) Up() error {
+	return m.To(m.Latest())
+}
+
+// Down rolls back the newest applied migration.
+func (m *Migrator) Down() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	// Roll back the newest applied migration.
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[m.migrations[i].Version]; ok {
+			return m.rollback(m.migrations[i])
+		}
+	}
+
+	return nil
+}
+
+// To applies or rolls back migrations until the schema is at the given
+// version. Version 0 rolls back every migration.
+func (m *Migrator) To(version int) error {
+	// Check the version exists.
+	if version != 0 {
+		var found bool
+		for _, migration := range m.migrations {
+			if migration.Version == version {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("Migration version %d does not exist", version)
+		}
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	// Roll back the applied migrations newer than the version, newest
+	// first.
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; ok && migration.Version > version {
+			if err := m.rollback(migration); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Apply the pending migrations up to and including the version.
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+			if err := m.apply(migration); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// applied returns the applied migration versions and when they were
+// applied, creating the tracking table if needed.
+func (m *Migrator) applied() (map[int]time.Time, error) {
+	// Create the tracking table.
+	if _, err := m.db.Exec(stmtCreateTable); err != nil {
+		return nil, err
+	}
+
+	// Execute the query.
+	rows, err := m.db.Query(stmtSelectApplied)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the migration rows.
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var t time.Time
+		if err := rows.Scan(&version, &t); err != nil {
+			return nil, err
+		}
+		applied[version] = t
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// apply applies the given migration.
+func (m *Migrator) apply(migration *Migration) error {
+	return m.run(migration, migration.Up, stmtInsert, migration.Version, time.Now().UTC())
+}
+
+// rollback rolls back the given migration.
+func (m *Migrator) rollback(migration *Migration) error {
+	return m.run(migration, migration.Down, stmtDelete, migration.Version)
+}
+
+// run executes the statements of a migration and then the given tracking
+// statement within a single transaction.
+//
+// MySQL commits DDL statements implicitly, so a failed migration may leave
+// a MySQL schema partially changed.
+func (m *Migrator) run(migration *Migration, script, stmt string, args ...interface{}) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	// Execute each statement of the migration.
+	for _, s := range split(script) {
+		if _, err := tx.Exec(s); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Migration %d_%s failed: %s", migration.Version, migration.Name, err)
+		}
+	}
+
+	// Record the migration.
+	if _, err := tx.Exec(m.dialect.Rebind(stmt), args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// split splits a migration script into its individual statements, since
+// not every driver can execute multiple statements at once.
+func split(script string) []string {
+	var stmts []string
+	for _, s := range strings.Split(script, ";") {
+		if s = strings.TrimSpace(s); s != "" {
+			stmts = append(stmts, s)
+		}
+	}
+
+	return stmts
+}
@@ -0,0 +1,3 @@
+DROP TABLE `todos`;
+
+DROP TABLE `members`;
@@ -0,0 +1,4 @@
+ALTER TABLE `todos`
+  DROP KEY `due_at`,
+  DROP COLUMN `remind_at`,
+  DROP COLUMN `due_at`;
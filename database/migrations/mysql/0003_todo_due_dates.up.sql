@@ -0,0 +1,4 @@
+ALTER TABLE `todos`
+  ADD COLUMN `due_at` datetime DEFAULT NULL,
+  ADD COLUMN `remind_at` datetime DEFAULT NULL,
+  ADD KEY `due_at` (`due_at`);
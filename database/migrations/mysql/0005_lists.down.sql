@@ -0,0 +1,5 @@
+ALTER TABLE `todos`
+  DROP KEY `list_id`,
+  DROP COLUMN `list_id`;
+
+DROP TABLE `lists`;
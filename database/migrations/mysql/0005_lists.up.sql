@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `lists` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `member_id` int(10) unsigned NOT NULL,
+  `created` datetime NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `member_id` (`member_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `todos`
+  ADD COLUMN `list_id` int(10) unsigned DEFAULT NULL,
+  ADD KEY `list_id` (`list_id`);
@@ -0,0 +1,3 @@
+ALTER TABLE `todos`
+  DROP KEY `parent_id`,
+  DROP COLUMN `parent_id`;
@@ -0,0 +1,3 @@
+ALTER TABLE `todos`
+  ADD COLUMN `parent_id` int(10) unsigned DEFAULT NULL,
+  ADD KEY `parent_id` (`parent_id`);
@@ -0,0 +1,5 @@
+ALTER TABLE `todos`
+  DROP KEY `series_id`,
+  DROP COLUMN `series_id`;
+
+DROP TABLE `series`;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `series` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `member_id` int(10) unsigned NOT NULL,
+  `created` datetime NOT NULL,
+  `rule` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `starts_at` datetime NOT NULL,
+  `occurrences` int(10) unsigned NOT NULL DEFAULT '0',
+  `latest_id` int(10) unsigned NOT NULL,
+  `stopped_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `member_id` (`member_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `todos`
+  ADD COLUMN `series_id` int(10) unsigned DEFAULT NULL,
+  ADD KEY `series_id` (`series_id`);
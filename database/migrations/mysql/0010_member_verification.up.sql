@@ -0,0 +1,3 @@
+ALTER TABLE `members` ADD COLUMN `verified_at` datetime DEFAULT NULL;
+
+UPDATE `members` SET `verified_at` = UTC_TIMESTAMP();
@@ -0,0 +1,6 @@
+DROP TABLE `recovery_codes`;
+
+ALTER TABLE `members`
+  DROP COLUMN `totp_counter`,
+  DROP COLUMN `totp_enabled_at`,
+  DROP COLUMN `totp_secret`;
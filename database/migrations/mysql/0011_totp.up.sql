@@ -0,0 +1,14 @@
+ALTER TABLE `members`
+  ADD COLUMN `totp_secret` varchar(32) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  ADD COLUMN `totp_enabled_at` datetime DEFAULT NULL,
+  ADD COLUMN `totp_counter` bigint(20) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS `recovery_codes` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `member_id` int(10) unsigned NOT NULL,
+  `hash` char(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created` datetime NOT NULL,
+  `used_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `member_id_hash` (`member_id`, `hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,5 @@
+ALTER TABLE `members`
+  DROP KEY `delete_at`,
+  DROP COLUMN `delete_at`,
+  DROP COLUMN `timezone`,
+  DROP COLUMN `display_name`;
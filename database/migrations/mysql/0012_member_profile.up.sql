@@ -0,0 +1,5 @@
+ALTER TABLE `members`
+  ADD COLUMN `display_name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  ADD COLUMN `timezone` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'UTC',
+  ADD COLUMN `delete_at` datetime DEFAULT NULL,
+  ADD KEY `delete_at` (`delete_at`);
@@ -0,0 +1,4 @@
+ALTER TABLE `members`
+  DROP KEY `role`,
+  DROP COLUMN `suspended_at`,
+  DROP COLUMN `role`;
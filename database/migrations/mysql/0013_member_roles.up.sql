@@ -0,0 +1,4 @@
+ALTER TABLE `members`
+  ADD COLUMN `role` varchar(16) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'member',
+  ADD COLUMN `suspended_at` datetime DEFAULT NULL,
+  ADD KEY `role` (`role`);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS `shares` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `owner_id` int(10) unsigned NOT NULL,
+  `member_id` int(10) unsigned NOT NULL,
+  `list_id` int(10) unsigned DEFAULT NULL,
+  `todo_id` int(10) unsigned DEFAULT NULL,
+  `permission` varchar(16) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created` datetime NOT NULL,
+  `accepted_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `owner_id` (`owner_id`),
+  KEY `member_id` (`member_id`),
+  KEY `list_id` (`list_id`),
+  KEY `todo_id` (`todo_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,13 @@
+ALTER TABLE `todos`
+  DROP KEY `workspace_id`,
+  DROP COLUMN `workspace_id`;
+
+ALTER TABLE `lists`
+  DROP KEY `workspace_id`,
+  DROP COLUMN `workspace_id`;
+
+DROP TABLE `invitations`;
+
+DROP TABLE `workspace_members`;
+
+DROP TABLE `workspaces`;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS `workspaces` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created` datetime NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `workspace_members` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `workspace_id` int(10) unsigned NOT NULL,
+  `member_id` int(10) unsigned NOT NULL,
+  `role` varchar(16) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `workspace_id_member_id` (`workspace_id`, `member_id`),
+  KEY `member_id` (`member_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `invitations` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `workspace_id` int(10) unsigned NOT NULL,
+  `inviter_id` int(10) unsigned NOT NULL,
+  `email` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `role` varchar(16) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `workspace_id` (`workspace_id`),
+  KEY `email` (`email`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `lists`
+  ADD COLUMN `workspace_id` int(10) unsigned DEFAULT NULL,
+  ADD KEY `workspace_id` (`workspace_id`);
+
+ALTER TABLE `todos`
+  ADD COLUMN `workspace_id` int(10) unsigned DEFAULT NULL,
+  ADD KEY `workspace_id` (`workspace_id`);
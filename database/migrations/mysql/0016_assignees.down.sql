@@ -0,0 +1,3 @@
+ALTER TABLE `todos`
+  DROP KEY `assignee_id`,
+  DROP COLUMN `assignee_id`;
@@ -0,0 +1,3 @@
+ALTER TABLE `todos`
+  ADD COLUMN `assignee_id` int(10) unsigned DEFAULT NULL,
+  ADD KEY `assignee_id` (`assignee_id`);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS `todo_events` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `todo_id` int(10) unsigned NOT NULL,
+  `member_id` int(10) unsigned NOT NULL,
+  `action` varchar(16) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `changes` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `todo_id` (`todo_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,15 @@
+CREATE EXTENSION IF NOT EXISTS citext;
+
+CREATE TABLE IF NOT EXISTS members (
+  id serial PRIMARY KEY,
+  email citext NOT NULL,
+  password char(60) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS todos (
+  id serial PRIMARY KEY,
+  member_id integer NOT NULL,
+  created timestamp with time zone NOT NULL,
+  detail varchar(255) NOT NULL,
+  completed boolean NOT NULL
+);
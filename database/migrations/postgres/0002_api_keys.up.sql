@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+  id serial PRIMARY KEY,
+  member_id integer NOT NULL,
+  name varchar(255) NOT NULL,
+  prefix char(12) NOT NULL,
+  hash char(64) NOT NULL UNIQUE,
+  created timestamp with time zone NOT NULL,
+  last_used timestamp with time zone DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_member_id ON api_keys (member_id);
@@ -0,0 +1,5 @@
+DROP INDEX todos_due_at;
+
+ALTER TABLE todos
+  DROP COLUMN remind_at,
+  DROP COLUMN due_at;
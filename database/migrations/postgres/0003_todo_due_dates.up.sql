@@ -0,0 +1,5 @@
+ALTER TABLE todos
+  ADD COLUMN due_at timestamp with time zone DEFAULT NULL,
+  ADD COLUMN remind_at timestamp with time zone DEFAULT NULL;
+
+CREATE INDEX todos_due_at ON todos (due_at);
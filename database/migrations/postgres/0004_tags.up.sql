@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS tags (
+  id serial PRIMARY KEY,
+  member_id integer NOT NULL,
+  name varchar(64) NOT NULL,
+  UNIQUE (member_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS todo_tags (
+  todo_id integer NOT NULL,
+  tag_id integer NOT NULL,
+  PRIMARY KEY (todo_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS todo_tags_tag_id ON todo_tags (tag_id);
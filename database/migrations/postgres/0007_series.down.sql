@@ -0,0 +1,5 @@
+DROP INDEX todos_series_id;
+
+ALTER TABLE todos DROP COLUMN series_id;
+
+DROP TABLE series;
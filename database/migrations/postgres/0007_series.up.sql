@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS series (
+  id serial PRIMARY KEY,
+  member_id integer NOT NULL,
+  created timestamp with time zone NOT NULL,
+  rule varchar(255) NOT NULL,
+  starts_at timestamp with time zone NOT NULL,
+  occurrences integer NOT NULL DEFAULT 0,
+  latest_id integer NOT NULL,
+  stopped_at timestamp with time zone DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS series_member_id ON series (member_id);
+
+ALTER TABLE todos ADD COLUMN series_id integer DEFAULT NULL;
+
+CREATE INDEX todos_series_id ON todos (series_id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+  id serial PRIMARY KEY,
+  member_id integer NOT NULL,
+  family char(32) NOT NULL,
+  hash char(64) NOT NULL UNIQUE,
+  created timestamp with time zone NOT NULL,
+  expires_at timestamp with time zone NOT NULL,
+  used_at timestamp with time zone DEFAULT NULL,
+  revoked_at timestamp with time zone DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_member_id ON refresh_tokens (member_id);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_family ON refresh_tokens (family);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS one_time_tokens (
+  id serial PRIMARY KEY,
+  member_id integer NOT NULL,
+  purpose varchar(32) NOT NULL,
+  hash char(64) NOT NULL UNIQUE,
+  created timestamp with time zone NOT NULL,
+  expires_at timestamp with time zone NOT NULL,
+  used_at timestamp with time zone DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS one_time_tokens_member_id ON one_time_tokens (member_id);
@@ -0,0 +1,3 @@
+ALTER TABLE members ADD COLUMN verified_at timestamp with time zone DEFAULT NULL;
+
+UPDATE members SET verified_at = now();
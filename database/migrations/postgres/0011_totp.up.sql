@@ -0,0 +1,14 @@
+ALTER TABLE members
+  ADD COLUMN totp_secret varchar(32) DEFAULT NULL,
+  ADD COLUMN totp_enabled_at timestamp with time zone DEFAULT NULL,
+  ADD COLUMN totp_counter bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+  id serial PRIMARY KEY,
+  member_id integer NOT NULL,
+  hash char(64) NOT NULL,
+  created timestamp with time zone NOT NULL,
+  used_at timestamp with time zone DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS recovery_codes_member_id_hash ON recovery_codes (member_id, hash);
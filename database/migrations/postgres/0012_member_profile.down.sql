@@ -0,0 +1,6 @@
+DROP INDEX members_delete_at;
+
+ALTER TABLE members
+  DROP COLUMN delete_at,
+  DROP COLUMN timezone,
+  DROP COLUMN display_name;
@@ -0,0 +1,6 @@
+ALTER TABLE members
+  ADD COLUMN display_name varchar(255) NOT NULL DEFAULT '',
+  ADD COLUMN timezone varchar(64) NOT NULL DEFAULT 'UTC',
+  ADD COLUMN delete_at timestamp with time zone DEFAULT NULL;
+
+CREATE INDEX members_delete_at ON members (delete_at);
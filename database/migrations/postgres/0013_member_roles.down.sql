@@ -0,0 +1,5 @@
+DROP INDEX members_role;
+
+ALTER TABLE members
+  DROP COLUMN suspended_at,
+  DROP COLUMN role;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS shares (
+  id serial PRIMARY KEY,
+  owner_id integer NOT NULL,
+  member_id integer NOT NULL,
+  list_id integer DEFAULT NULL,
+  todo_id integer DEFAULT NULL,
+  permission varchar(16) NOT NULL,
+  created timestamp with time zone NOT NULL,
+  accepted_at timestamp with time zone DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS shares_owner_id ON shares (owner_id);
+
+CREATE INDEX IF NOT EXISTS shares_member_id ON shares (member_id);
+
+CREATE INDEX IF NOT EXISTS shares_list_id ON shares (list_id);
+
+CREATE INDEX IF NOT EXISTS shares_todo_id ON shares (todo_id);
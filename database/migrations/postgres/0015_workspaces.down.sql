@@ -0,0 +1,13 @@
+DROP INDEX todos_workspace_id;
+
+ALTER TABLE todos DROP COLUMN workspace_id;
+
+DROP INDEX lists_workspace_id;
+
+ALTER TABLE lists DROP COLUMN workspace_id;
+
+DROP TABLE invitations;
+
+DROP TABLE workspace_members;
+
+DROP TABLE workspaces;
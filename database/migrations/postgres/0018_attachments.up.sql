@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS attachments (
+  id serial PRIMARY KEY,
+  todo_id integer NOT NULL,
+  member_id integer NOT NULL,
+  name varchar(255) NOT NULL,
+  content_type varchar(255) NOT NULL,
+  size bigint NOT NULL,
+  blob_key varchar(128) NOT NULL,
+  created timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS attachments_todo_id ON attachments (todo_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS `members` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `email` varchar(255) NOT NULL COLLATE NOCASE,
+  `password` char(60) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS `todos` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `member_id` integer NOT NULL,
+  `created` datetime NOT NULL,
+  `detail` varchar(255) NOT NULL,
+  `completed` boolean NOT NULL
+);
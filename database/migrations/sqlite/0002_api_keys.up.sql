@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `api_keys` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `member_id` integer NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `prefix` char(12) NOT NULL,
+  `hash` char(64) NOT NULL UNIQUE,
+  `created` datetime NOT NULL,
+  `last_used` datetime DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS `api_keys_member_id` ON `api_keys` (`member_id`);
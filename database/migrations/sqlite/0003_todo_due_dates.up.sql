@@ -0,0 +1,5 @@
+ALTER TABLE `todos` ADD COLUMN `due_at` datetime DEFAULT NULL;
+
+ALTER TABLE `todos` ADD COLUMN `remind_at` datetime DEFAULT NULL;
+
+CREATE INDEX `todos_due_at` ON `todos` (`due_at`);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `lists` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `member_id` integer NOT NULL,
+  `created` datetime NOT NULL,
+  `name` varchar(255) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS `lists_member_id` ON `lists` (`member_id`);
+
+ALTER TABLE `todos` ADD COLUMN `list_id` integer DEFAULT NULL;
+
+CREATE INDEX `todos_list_id` ON `todos` (`list_id`);
@@ -0,0 +1,3 @@
+ALTER TABLE `todos` ADD COLUMN `parent_id` integer DEFAULT NULL;
+
+CREATE INDEX `todos_parent_id` ON `todos` (`parent_id`);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `refresh_tokens` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `member_id` integer NOT NULL,
+  `family` char(32) NOT NULL,
+  `hash` char(64) NOT NULL UNIQUE,
+  `created` datetime NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `used_at` datetime DEFAULT NULL,
+  `revoked_at` datetime DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS `refresh_tokens_member_id` ON `refresh_tokens` (`member_id`);
+
+CREATE INDEX IF NOT EXISTS `refresh_tokens_family` ON `refresh_tokens` (`family`);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `one_time_tokens` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `member_id` integer NOT NULL,
+  `purpose` varchar(32) NOT NULL,
+  `hash` char(64) NOT NULL UNIQUE,
+  `created` datetime NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `used_at` datetime DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS `one_time_tokens_member_id` ON `one_time_tokens` (`member_id`);
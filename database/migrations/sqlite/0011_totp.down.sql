@@ -0,0 +1,7 @@
+DROP TABLE `recovery_codes`;
+
+ALTER TABLE `members` DROP COLUMN `totp_counter`;
+
+ALTER TABLE `members` DROP COLUMN `totp_enabled_at`;
+
+ALTER TABLE `members` DROP COLUMN `totp_secret`;
@@ -0,0 +1,15 @@
+ALTER TABLE `members` ADD COLUMN `totp_secret` varchar(32) DEFAULT NULL;
+
+ALTER TABLE `members` ADD COLUMN `totp_enabled_at` datetime DEFAULT NULL;
+
+ALTER TABLE `members` ADD COLUMN `totp_counter` bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS `recovery_codes` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `member_id` integer NOT NULL,
+  `hash` char(64) NOT NULL,
+  `created` datetime NOT NULL,
+  `used_at` datetime DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS `recovery_codes_member_id_hash` ON `recovery_codes` (`member_id`, `hash`);
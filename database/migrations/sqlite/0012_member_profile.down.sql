@@ -0,0 +1,7 @@
+DROP INDEX `members_delete_at`;
+
+ALTER TABLE `members` DROP COLUMN `delete_at`;
+
+ALTER TABLE `members` DROP COLUMN `timezone`;
+
+ALTER TABLE `members` DROP COLUMN `display_name`;
@@ -0,0 +1,7 @@
+ALTER TABLE `members` ADD COLUMN `display_name` varchar(255) NOT NULL DEFAULT '';
+
+ALTER TABLE `members` ADD COLUMN `timezone` varchar(64) NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE `members` ADD COLUMN `delete_at` datetime DEFAULT NULL;
+
+CREATE INDEX `members_delete_at` ON `members` (`delete_at`);
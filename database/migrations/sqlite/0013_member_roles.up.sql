@@ -0,0 +1,5 @@
+ALTER TABLE `members` ADD COLUMN `role` varchar(16) NOT NULL DEFAULT 'member';
+
+ALTER TABLE `members` ADD COLUMN `suspended_at` datetime DEFAULT NULL;
+
+CREATE INDEX `members_role` ON `members` (`role`);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS `shares` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `owner_id` integer NOT NULL,
+  `member_id` integer NOT NULL,
+  `list_id` integer DEFAULT NULL,
+  `todo_id` integer DEFAULT NULL,
+  `permission` varchar(16) NOT NULL,
+  `created` datetime NOT NULL,
+  `accepted_at` datetime DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS `shares_owner_id` ON `shares` (`owner_id`);
+
+CREATE INDEX IF NOT EXISTS `shares_member_id` ON `shares` (`member_id`);
+
+CREATE INDEX IF NOT EXISTS `shares_list_id` ON `shares` (`list_id`);
+
+CREATE INDEX IF NOT EXISTS `shares_todo_id` ON `shares` (`todo_id`);
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS `workspaces` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `name` varchar(255) NOT NULL,
+  `created` datetime NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS `workspace_members` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `workspace_id` integer NOT NULL,
+  `member_id` integer NOT NULL,
+  `role` varchar(16) NOT NULL,
+  `created` datetime NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS `workspace_members_workspace_id_member_id` ON `workspace_members` (`workspace_id`, `member_id`);
+
+CREATE INDEX IF NOT EXISTS `workspace_members_member_id` ON `workspace_members` (`member_id`);
+
+CREATE TABLE IF NOT EXISTS `invitations` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `workspace_id` integer NOT NULL,
+  `inviter_id` integer NOT NULL,
+  `email` varchar(255) NOT NULL,
+  `role` varchar(16) NOT NULL,
+  `created` datetime NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS `invitations_workspace_id` ON `invitations` (`workspace_id`);
+
+CREATE INDEX IF NOT EXISTS `invitations_email` ON `invitations` (`email`);
+
+ALTER TABLE `lists` ADD COLUMN `workspace_id` integer DEFAULT NULL;
+
+CREATE INDEX `lists_workspace_id` ON `lists` (`workspace_id`);
+
+ALTER TABLE `todos` ADD COLUMN `workspace_id` integer DEFAULT NULL;
+
+CREATE INDEX `todos_workspace_id` ON `todos` (`workspace_id`);
@@ -0,0 +1,3 @@
+DROP INDEX `todos_assignee_id`;
+
+ALTER TABLE `todos` DROP COLUMN `assignee_id`;
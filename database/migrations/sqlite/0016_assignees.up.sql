@@ -0,0 +1,3 @@
+ALTER TABLE `todos` ADD COLUMN `assignee_id` integer DEFAULT NULL;
+
+CREATE INDEX `todos_assignee_id` ON `todos` (`assignee_id`);
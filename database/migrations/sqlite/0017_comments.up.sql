@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `comments` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `todo_id` integer NOT NULL,
+  `member_id` integer NOT NULL,
+  `body` text NOT NULL,
+  `created` datetime NOT NULL,
+  `updated_at` datetime DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS `comments_todo_id` ON `comments` (`todo_id`);
+
+CREATE INDEX IF NOT EXISTS `comments_member_id` ON `comments` (`member_id`);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `attachments` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `todo_id` integer NOT NULL,
+  `member_id` integer NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `content_type` varchar(255) NOT NULL,
+  `size` integer NOT NULL,
+  `blob_key` varchar(128) NOT NULL,
+  `created` datetime NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS `attachments_todo_id` ON `attachments` (`todo_id`);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS `todo_events` (
+  `id` integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  `todo_id` integer NOT NULL,
+  `member_id` integer NOT NULL,
+  `action` varchar(16) NOT NULL,
+  `changes` text NOT NULL,
+  `created` datetime NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS `todo_events_todo_id` ON `todo_events` (`todo_id`);
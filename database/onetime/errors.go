@@ -0,0 +1,13 @@
+package onetime
+
+import "errors"
+
+var (
+	// ErrTokenNotFound is returned when a one-time token could not be
+	// found.
+	ErrTokenNotFound = errors.New("Token could not be found")
+
+	// ErrTokenUsed is returned when a one-time token has already been
+	// used.
+	ErrTokenUsed = errors.New("Token has already been used")
+)
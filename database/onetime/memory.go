@@ -0,0 +1,137 @@
+package onetime
+
+import (
+	"sync"
+	"time"
+)
+
+// Memory defines the one-time tokens database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits.
+type Memory struct {
+	mu     sync.RWMutex
+	lastID int
+	tokens map[int]*Token
+}
+
+// NewMemory creates a new in-memory one-time tokens database.
+func NewMemory() *Memory {
+	return &Memory{
+		tokens: make(map[int]*Token),
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	tokens := make(map[int]*Token, len(m.tokens))
+	for id, v := range m.tokens {
+		c := *v
+		tokens[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.tokens = tokens
+	}
+}
+
+// New creates a new one-time token.
+func (m *Memory) New(mid int, params *NewParams) (*Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Token.
+	m.lastID++
+	token := &Token{
+		ID:        m.lastID,
+		MemberID:  mid,
+		Purpose:   params.Purpose,
+		Hash:      params.Hash,
+		Created:   time.Now(),
+		ExpiresAt: params.ExpiresAt,
+	}
+
+	// Store a copy of the token.
+	stored := *token
+	m.tokens[token.ID] = &stored
+
+	return token, nil
+}
+
+// GetByPurposeAndHash retrieves a one-time token by its purpose and hash.
+func (m *Memory) GetByPurposeAndHash(purpose, hash string) (*Token, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, token := range m.tokens {
+		if token.Purpose == purpose && token.Hash == hash {
+			// Return a copy of the token.
+			found := *token
+			return &found, nil
+		}
+	}
+
+	return nil, ErrTokenNotFound
+}
+
+// MarkUsed marks a one-time token as used, returning ErrTokenUsed if it
+// already was.
+func (m *Memory) MarkUsed(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.tokens[id]
+	if !ok {
+		return ErrTokenNotFound
+	}
+	if token.UsedAt != nil {
+		return ErrTokenUsed
+	}
+
+	now := time.Now()
+	token.UsedAt = &now
+
+	return nil
+}
+
+// MarkUsedByMemberID marks every one-time token of a given member with the
+// given purpose as used.
+func (m *Memory) MarkUsedByMemberID(mid int, purpose string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range m.tokens {
+		if token.MemberID == mid && token.Purpose == purpose && token.UsedAt == nil {
+			usedAt := now
+			token.UsedAt = &usedAt
+		}
+	}
+
+	return nil
+}
+
+// DeleteByMemberID deletes every one-time token of a given member.
+func (m *Memory) DeleteByMemberID(mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, token := range m.tokens {
+		if token.MemberID == mid {
+			delete(m.tokens, id)
+		}
+	}
+
+	return nil
+}
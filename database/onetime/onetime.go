@@ -0,0 +1,48 @@
+package onetime
+
+import "time"
+
+// Database defines the one-time tokens database.
+//
+// One-time tokens are sent to members to confirm an action, such as
+// resetting their password. Each one is meant for a single purpose, can only
+// be used once and expires.
+type Database interface {
+	// New creates a new one-time token.
+	New(mid int, params *NewParams) (*Token, error)
+
+	// GetByPurposeAndHash retrieves a one-time token by its purpose and
+	// hash.
+	GetByPurposeAndHash(purpose, hash string) (*Token, error)
+
+	// MarkUsed marks a one-time token as used, returning ErrTokenUsed if
+	// it already was.
+	MarkUsed(id int) error
+
+	// MarkUsedByMemberID marks every one-time token of a given member
+	// with the given purpose as used.
+	MarkUsedByMemberID(mid int, purpose string) error
+
+	// DeleteByMemberID deletes every one-time token of a given member.
+	DeleteByMemberID(mid int) error
+}
+
+// Token defines a one-time token.
+//
+// Only the hash of the token itself is stored.
+type Token struct {
+	ID        int        `json:"id"`
+	MemberID  int        `json:"member_id"`
+	Purpose   string     `json:"purpose"`
+	Hash      string     `json:"-"`
+	Created   time.Time  `json:"created"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams struct {
+	Purpose   string    `json:"purpose"`
+	Hash      string    `json:"hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,138 @@
+package onetime
+
+import (
+	"database/sql"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the one-time tokens database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL one-time tokens database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// stmtInsert defines the SQL statement to
+	// insert a new one-time token into the database.
+	stmtInsert = `
+INSERT INTO one_time_tokens (member_id, purpose, hash, created, expires_at)
+VALUES (?, ?, ?, ?, ?)
+`
+
+	// stmtSelectByPurposeAndHash defines the SQL statement
+	// to select a one-time token by its purpose and hash.
+	stmtSelectByPurposeAndHash = `
+SELECT id, member_id, purpose, hash, created, expires_at, used_at
+FROM one_time_tokens
+WHERE purpose=? AND hash=?
+`
+
+	// stmtMarkUsed defines the SQL statement to mark
+	// a one-time token as used, unless it already is.
+	stmtMarkUsed = `
+UPDATE one_time_tokens
+SET used_at=?
+WHERE id=? AND used_at IS NULL
+`
+
+	// stmtMarkUsedByMemberID defines the SQL statement
+	// to mark every one-time token of a given member
+	// with a given purpose as used.
+	stmtMarkUsedByMemberID = `
+UPDATE one_time_tokens
+SET used_at=?
+WHERE member_id=? AND purpose=? AND used_at IS NULL
+`
+
+	// stmtDeleteByMemberID defines the SQL statement
+	// to delete every one-time token of a given member.
+	stmtDeleteByMemberID = `
+DELETE FROM one_time_tokens
+WHERE member_id=?
+`
+)
+
+// New creates a new one-time token.
+func (db *SQL) New(mid int, params *NewParams) (*Token, error) {
+	// Create a new Token.
+	token := &Token{
+		MemberID:  mid,
+		Purpose:   params.Purpose,
+		Hash:      params.Hash,
+		Created:   time.Now(),
+		ExpiresAt: params.ExpiresAt.UTC(),
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, token.MemberID, token.Purpose, token.Hash, token.Created, token.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	token.ID = id
+
+	return token, nil
+}
+
+// GetByPurposeAndHash retrieves a one-time token by its purpose and hash.
+func (db *SQL) GetByPurposeAndHash(purpose, hash string) (*Token, error) {
+	// Create a new Token.
+	token := &Token{}
+
+	// Execute the query.
+	err := db.db.QueryRow(stmtSelectByPurposeAndHash, purpose, hash).Scan(&token.ID, &token.MemberID, &token.Purpose, &token.Hash, &token.Created, &token.ExpiresAt, &token.UsedAt)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrTokenNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// MarkUsed marks a one-time token as used, returning ErrTokenUsed if it
+// already was.
+//
+// The check and the update happen in a single statement, so when the same
+// token is used twice at once only one of them succeeds.
+func (db *SQL) MarkUsed(id int) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtMarkUsed, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+
+	// Check if the token was marked.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrTokenUsed
+	}
+
+	return nil
+}
+
+// MarkUsedByMemberID marks every one-time token of a given member with the
+// given purpose as used.
+func (db *SQL) MarkUsedByMemberID(mid int, purpose string) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtMarkUsedByMemberID, time.Now().UTC(), mid, purpose)
+	return err
+}
+
+// DeleteByMemberID deletes every one-time token of a given member.
+func (db *SQL) DeleteByMemberID(mid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByMemberID, mid)
+	return err
+}
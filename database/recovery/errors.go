@@ -0,0 +1,11 @@
+package recovery
+
+import "errors"
+
+var (
+	// ErrCodeNotFound is returned when a recovery code could not be found.
+	ErrCodeNotFound = errors.New("Recovery code could not be found")
+
+	// ErrCodeUsed is returned when a recovery code has already been used.
+	ErrCodeUsed = errors.New("Recovery code has already been used")
+)
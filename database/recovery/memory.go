@@ -0,0 +1,135 @@
+package recovery
+
+import (
+	"sync"
+	"time"
+)
+
+// Memory defines the recovery codes database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits.
+type Memory struct {
+	mu     sync.RWMutex
+	lastID int
+	codes  map[int]*Code
+}
+
+// NewMemory creates a new in-memory recovery codes database.
+func NewMemory() *Memory {
+	return &Memory{
+		codes: make(map[int]*Code),
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	codes := make(map[int]*Code, len(m.codes))
+	for id, v := range m.codes {
+		c := *v
+		codes[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.codes = codes
+	}
+}
+
+// New creates a new recovery code.
+func (m *Memory) New(mid int, params *NewParams) (*Code, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Code.
+	m.lastID++
+	code := &Code{
+		ID:       m.lastID,
+		MemberID: mid,
+		Hash:     params.Hash,
+		Created:  time.Now(),
+	}
+
+	// Store a copy of the code.
+	stored := *code
+	m.codes[code.ID] = &stored
+
+	return code, nil
+}
+
+// GetByMemberIDAndHash retrieves a recovery code of a given member by its
+// hash.
+func (m *Memory) GetByMemberIDAndHash(mid int, hash string) (*Code, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, code := range m.codes {
+		if code.MemberID == mid && code.Hash == hash {
+			// Return a copy of the code.
+			found := *code
+			return &found, nil
+		}
+	}
+
+	return nil, ErrCodeNotFound
+}
+
+// CountUnusedByMemberID returns the number of recovery codes of a given
+// member that have not been used.
+func (m *Memory) CountUnusedByMemberID(mid int) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var count int
+	for _, code := range m.codes {
+		if code.MemberID == mid && code.UsedAt == nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// MarkUsed marks a recovery code as used, returning ErrCodeUsed if it
+// already was.
+func (m *Memory) MarkUsed(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	code, ok := m.codes[id]
+	if !ok {
+		return ErrCodeNotFound
+	}
+	if code.UsedAt != nil {
+		return ErrCodeUsed
+	}
+
+	now := time.Now()
+	code.UsedAt = &now
+
+	return nil
+}
+
+// DeleteByMemberID deletes every recovery code of a given member.
+func (m *Memory) DeleteByMemberID(mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, code := range m.codes {
+		if code.MemberID == mid {
+			delete(m.codes, id)
+		}
+	}
+
+	return nil
+}
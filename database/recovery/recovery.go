@@ -0,0 +1,43 @@
+package recovery
+
+import "time"
+
+// Database defines the recovery codes database.
+//
+// Recovery codes let members log in with two-factor authentication when
+// they do not have their authenticator app. Each one can only be used once.
+type Database interface {
+	// New creates a new recovery code.
+	New(mid int, params *NewParams) (*Code, error)
+
+	// GetByMemberIDAndHash retrieves a recovery code of a given member by
+	// its hash.
+	GetByMemberIDAndHash(mid int, hash string) (*Code, error)
+
+	// CountUnusedByMemberID returns the number of recovery codes of a given
+	// member that have not been used.
+	CountUnusedByMemberID(mid int) (int, error)
+
+	// MarkUsed marks a recovery code as used, returning ErrCodeUsed if it
+	// already was.
+	MarkUsed(id int) error
+
+	// DeleteByMemberID deletes every recovery code of a given member.
+	DeleteByMemberID(mid int) error
+}
+
+// Code defines a recovery code.
+//
+// Only the hash of the code itself is stored.
+type Code struct {
+	ID       int        `json:"id"`
+	MemberID int        `json:"member_id"`
+	Hash     string     `json:"-"`
+	Created  time.Time  `json:"created"`
+	UsedAt   *time.Time `json:"used_at"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams struct {
+	Hash string `json:"hash"`
+}
@@ -0,0 +1,142 @@
+package recovery
+
+import (
+	"database/sql"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the recovery codes database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL recovery codes database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// stmtInsert defines the SQL statement to
+	// insert a new recovery code into the database.
+	stmtInsert = `
+INSERT INTO recovery_codes (member_id, hash, created)
+VALUES (?, ?, ?)
+`
+
+	// stmtSelectByMemberIDAndHash defines the SQL
+	// statement to select a recovery code of a
+	// given member by its hash.
+	stmtSelectByMemberIDAndHash = `
+SELECT id, member_id, hash, created, used_at
+FROM recovery_codes
+WHERE member_id=? AND hash=?
+`
+
+	// stmtCountUnusedByMemberID defines the SQL
+	// statement to count the unused recovery codes
+	// of a given member.
+	stmtCountUnusedByMemberID = `
+SELECT COUNT(*)
+FROM recovery_codes
+WHERE member_id=? AND used_at IS NULL
+`
+
+	// stmtMarkUsed defines the SQL statement to mark
+	// a recovery code as used, unless it already is.
+	stmtMarkUsed = `
+UPDATE recovery_codes
+SET used_at=?
+WHERE id=? AND used_at IS NULL
+`
+
+	// stmtDeleteByMemberID defines the SQL statement
+	// to delete every recovery code of a given member.
+	stmtDeleteByMemberID = `
+DELETE FROM recovery_codes
+WHERE member_id=?
+`
+)
+
+// New creates a new recovery code.
+func (db *SQL) New(mid int, params *NewParams) (*Code, error) {
+	// Create a new Code.
+	code := &Code{
+		MemberID: mid,
+		Hash:     params.Hash,
+		Created:  time.Now(),
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, code.MemberID, code.Hash, code.Created)
+	if err != nil {
+		return nil, err
+	}
+	code.ID = id
+
+	return code, nil
+}
+
+// GetByMemberIDAndHash retrieves a recovery code of a given member by its
+// hash.
+func (db *SQL) GetByMemberIDAndHash(mid int, hash string) (*Code, error) {
+	// Create a new Code.
+	code := &Code{}
+
+	// Execute the query.
+	err := db.db.QueryRow(stmtSelectByMemberIDAndHash, mid, hash).Scan(&code.ID, &code.MemberID, &code.Hash, &code.Created, &code.UsedAt)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrCodeNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return code, nil
+}
+
+// CountUnusedByMemberID returns the number of recovery codes of a given
+// member that have not been used.
+func (db *SQL) CountUnusedByMemberID(mid int) (int, error) {
+	// Execute the query.
+	var count int
+	if err := db.db.QueryRow(stmtCountUnusedByMemberID, mid).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MarkUsed marks a recovery code as used, returning ErrCodeUsed if it
+// already was.
+//
+// The check and the update happen in a single statement, so when the same
+// code is used twice at once only one of them succeeds.
+func (db *SQL) MarkUsed(id int) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtMarkUsed, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+
+	// Check if the code was marked.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrCodeUsed
+	}
+
+	return nil
+}
+
+// DeleteByMemberID deletes every recovery code of a given member.
+func (db *SQL) DeleteByMemberID(mid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByMemberID, mid)
+	return err
+}
@@ -0,0 +1,8 @@
+package series
+
+import "errors"
+
+var (
+	// ErrSeriesNotFound is returned when a series could not be found.
+	ErrSeriesNotFound = errors.New("Series could not be found")
+)
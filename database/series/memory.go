@@ -0,0 +1,173 @@
+package series
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory defines the series database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits.
+type Memory struct {
+	mu     sync.RWMutex
+	lastID int
+	series map[int]*Series
+}
+
+// NewMemory creates a new in-memory series database.
+func NewMemory() *Memory {
+	return &Memory{
+		series: make(map[int]*Series),
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	series := make(map[int]*Series, len(m.series))
+	for id, v := range m.series {
+		c := *v
+		series[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.series = series
+	}
+}
+
+// New creates a new series.
+func (m *Memory) New(mid int, params *NewParams) (*Series, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Series.
+	m.lastID++
+	series := &Series{
+		ID:          m.lastID,
+		MemberID:    mid,
+		Created:     time.Now(),
+		Rule:        params.Rule,
+		StartsAt:    params.StartsAt,
+		Occurrences: 1,
+		LatestID:    params.LatestID,
+	}
+
+	// Store a copy of the series.
+	stored := *series
+	m.series[series.ID] = &stored
+
+	return series, nil
+}
+
+// GetByMemberID retrieves the series of a given member.
+func (m *Memory) GetByMemberID(mid int) (*SeriesSet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Create a new SeriesSet.
+	set := &SeriesSet{
+		Series: []*Series{},
+	}
+
+	// Add copies of the series belonging to this member.
+	for _, series := range m.series {
+		if series.MemberID == mid {
+			found := *series
+			set.Series = append(set.Series, &found)
+		}
+	}
+
+	// Sort the series by ID.
+	sort.Slice(set.Series, func(i, j int) bool {
+		return set.Series[i].ID < set.Series[j].ID
+	})
+	set.Total = len(set.Series)
+
+	return set, nil
+}
+
+// GetByID retrieves a series by its ID.
+func (m *Memory) GetByID(id int) (*Series, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	series, ok := m.series[id]
+	if !ok {
+		return nil, ErrSeriesNotFound
+	}
+
+	// Return a copy of the series.
+	found := *series
+	return &found, nil
+}
+
+// GetByIDAndMemberID retrieves a series by its ID and member ID.
+func (m *Memory) GetByIDAndMemberID(id, mid int) (*Series, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	series, ok := m.series[id]
+	if !ok || series.MemberID != mid {
+		return nil, ErrSeriesNotFound
+	}
+
+	// Return a copy of the series.
+	found := *series
+	return &found, nil
+}
+
+// Update updates a series.
+func (m *Memory) Update(id int, params *UpdateParams) (*Series, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	series, ok := m.series[id]
+	if !ok {
+		return nil, ErrSeriesNotFound
+	}
+
+	// Handle the fields being updated.
+	if params.Rule != nil {
+		series.Rule = *params.Rule
+	}
+	if params.Occurrences != nil {
+		series.Occurrences = *params.Occurrences
+	}
+	if params.LatestID != nil {
+		series.LatestID = *params.LatestID
+	}
+	if params.StoppedAt != nil {
+		stoppedAt := *params.StoppedAt
+		series.StoppedAt = &stoppedAt
+	}
+
+	// Return a copy of the series.
+	updated := *series
+	return &updated, nil
+}
+
+// DeleteByMemberID deletes every series of a given member.
+func (m *Memory) DeleteByMemberID(mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, series := range m.series {
+		if series.MemberID == mid {
+			delete(m.series, id)
+		}
+	}
+
+	return nil
+}
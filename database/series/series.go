@@ -0,0 +1,64 @@
+package series
+
+import "time"
+
+// Database defines the recurring todo series database.
+type Database interface {
+	// New creates a new series.
+	New(mid int, params *NewParams) (*Series, error)
+
+	// GetByMemberID retrieves the series of a given member.
+	GetByMemberID(mid int) (*SeriesSet, error)
+
+	// GetByID retrieves a series by its ID.
+	GetByID(id int) (*Series, error)
+
+	// GetByIDAndMemberID retrieves a series by its ID and member ID.
+	GetByIDAndMemberID(id, mid int) (*Series, error)
+
+	// Update updates a series.
+	Update(id int, params *UpdateParams) (*Series, error)
+
+	// DeleteByMemberID deletes every series of a given member.
+	DeleteByMemberID(mid int) error
+}
+
+// Series defines a recurring todo series.
+//
+// The Rule is a recurrence rule which, starting from StartsAt, gives the
+// due dates of the occurrences of the series. Occurrences is the number of
+// todos created for the series so far, the latest of which is LatestID.
+// Series with a StoppedAt do not create any more occurrences.
+type Series struct {
+	ID          int        `json:"id"`
+	MemberID    int        `json:"member_id"`
+	Created     time.Time  `json:"created"`
+	Rule        string     `json:"rule"`
+	StartsAt    time.Time  `json:"starts_at"`
+	Occurrences int        `json:"occurrences"`
+	LatestID    int        `json:"latest_id"`
+	StoppedAt   *time.Time `json:"stopped_at"`
+}
+
+// SeriesSet defines a set of recurring todo series.
+type SeriesSet struct {
+	Series []*Series `json:"series"`
+	Total  int       `json:"total"`
+}
+
+// NewParams defines the parameters for the New method.
+//
+// New series start with a single occurrence, the todo with LatestID.
+type NewParams struct {
+	Rule     string    `json:"rule"`
+	StartsAt time.Time `json:"starts_at"`
+	LatestID int       `json:"latest_id"`
+}
+
+// UpdateParams defines the parameters for the Update method.
+type UpdateParams struct {
+	Rule        *string    `json:"rule"`
+	Occurrences *int       `json:"occurrences"`
+	LatestID    *int       `json:"latest_id"`
+	StoppedAt   *time.Time `json:"stopped_at"`
+}
@@ -0,0 +1,244 @@
+package series
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the series database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL series database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// columns defines the columns selected for
+	// a series, in the order they are scanned.
+	columns = `id, member_id, created, rule, starts_at, occurrences, latest_id, stopped_at`
+
+	// stmtInsert defines the SQL statement to
+	// insert a new series into the database.
+	stmtInsert = `
+INSERT INTO series (member_id, created, rule, starts_at, occurrences, latest_id)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+	// stmtSelectByMemberID defines the SQL statement
+	// to select the series of a given member.
+	stmtSelectByMemberID = `
+SELECT ` + columns + `
+FROM series
+WHERE member_id=?
+ORDER BY id
+`
+
+	// stmtSelectByID defines the SQL statement to
+	// select a series by its ID.
+	stmtSelectByID = `
+SELECT ` + columns + `
+FROM series
+WHERE id=?
+`
+
+	// stmtSelectByIDAndMemberID defines the SQL statement
+	// to select a series by its ID and member ID.
+	stmtSelectByIDAndMemberID = `
+SELECT ` + columns + `
+FROM series
+WHERE id=? AND member_id=?
+`
+
+	// stmtUpdate defines the SQL statement to
+	// update a series.
+	stmtUpdate = `
+UPDATE series
+SET %s
+WHERE id=?
+`
+
+	// stmtDeleteByMemberID defines the SQL statement
+	// to delete every series of a given member.
+	stmtDeleteByMemberID = `
+DELETE FROM series
+WHERE member_id=?
+`
+)
+
+// New creates a new series.
+func (db *SQL) New(mid int, params *NewParams) (*Series, error) {
+	// Create a new Series.
+	series := &Series{
+		MemberID:    mid,
+		Created:     time.Now(),
+		Rule:        params.Rule,
+		StartsAt:    params.StartsAt.UTC(),
+		Occurrences: 1,
+		LatestID:    params.LatestID,
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, series.MemberID, series.Created, series.Rule, series.StartsAt, series.Occurrences, series.LatestID)
+	if err != nil {
+		return nil, err
+	}
+	series.ID = id
+
+	return series, nil
+}
+
+// GetByMemberID retrieves the series of a given member.
+func (db *SQL) GetByMemberID(mid int) (*SeriesSet, error) {
+	// Create a new SeriesSet.
+	set := &SeriesSet{
+		Series: []*Series{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(stmtSelectByMemberID, mid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the series rows.
+	for rows.Next() {
+		// Create a new Series.
+		series := &Series{}
+
+		// Scan row values into series struct.
+		if err := scan(rows, series); err != nil {
+			return nil, err
+		}
+
+		// Add to series set.
+		set.Series = append(set.Series, series)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	set.Total = len(set.Series)
+
+	return set, nil
+}
+
+// GetByID retrieves a series by its ID.
+func (db *SQL) GetByID(id int) (*Series, error) {
+	return db.getOne(stmtSelectByID, id)
+}
+
+// GetByIDAndMemberID retrieves a series by its ID and member ID.
+func (db *SQL) GetByIDAndMemberID(id, mid int) (*Series, error) {
+	return db.getOne(stmtSelectByIDAndMemberID, id, mid)
+}
+
+// getOne retrieves a single series using the given statement.
+func (db *SQL) getOne(stmt string, args ...interface{}) (*Series, error) {
+	// Create a new Series.
+	series := &Series{}
+
+	// Execute the query.
+	err := scan(db.db.QueryRow(stmt, args...), series)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrSeriesNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// Update updates a series.
+func (db *SQL) Update(id int, params *UpdateParams) (*Series, error) {
+	// Create variables to hold the query fields
+	// being updated and their new values.
+	var queryFields string
+	var queryValues []interface{}
+
+	// Handle rule field.
+	if params.Rule != nil {
+		if queryFields == "" {
+			queryFields = "rule=?"
+		} else {
+			queryFields += ", rule=?"
+		}
+
+		queryValues = append(queryValues, *params.Rule)
+	}
+
+	// Handle occurrences field.
+	if params.Occurrences != nil {
+		if queryFields == "" {
+			queryFields = "occurrences=?"
+		} else {
+			queryFields += ", occurrences=?"
+		}
+
+		queryValues = append(queryValues, *params.Occurrences)
+	}
+
+	// Handle latest ID field.
+	if params.LatestID != nil {
+		if queryFields == "" {
+			queryFields = "latest_id=?"
+		} else {
+			queryFields += ", latest_id=?"
+		}
+
+		queryValues = append(queryValues, *params.LatestID)
+	}
+
+	// Handle stopped at field.
+	if params.StoppedAt != nil {
+		if queryFields == "" {
+			queryFields = "stopped_at=?"
+		} else {
+			queryFields += ", stopped_at=?"
+		}
+
+		queryValues = append(queryValues, params.StoppedAt.UTC())
+	}
+
+	// Check if the query is empty.
+	if queryFields == "" {
+		return db.GetByID(id)
+	}
+
+	// Build the full query.
+	query := fmt.Sprintf(stmtUpdate, queryFields)
+	queryValues = append(queryValues, id)
+
+	// Execute the query.
+	_, err := db.db.Exec(query, queryValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetByID(id)
+}
+
+// scanner defines the Scan method shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan scans a row selected using the columns constant into a series.
+func scan(row scanner, series *Series) error {
+	return row.Scan(&series.ID, &series.MemberID, &series.Created, &series.Rule, &series.StartsAt, &series.Occurrences, &series.LatestID, &series.StoppedAt)
+}
+
+// DeleteByMemberID deletes every series of a given member.
+func (db *SQL) DeleteByMemberID(mid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByMemberID, mid)
+	return err
+}
@@ -0,0 +1,8 @@
+package shares
+
+import "errors"
+
+var (
+	// ErrShareNotFound is returned when a share could not be found.
+	ErrShareNotFound = errors.New("Share could not be found")
+)
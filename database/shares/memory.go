@@ -0,0 +1,255 @@
+package shares
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory defines the shares database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits.
+type Memory struct {
+	mu     sync.RWMutex
+	lastID int
+	shares map[int]*Share
+}
+
+// NewMemory creates a new in-memory shares database.
+func NewMemory() *Memory {
+	return &Memory{
+		shares: make(map[int]*Share),
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	shares := make(map[int]*Share, len(m.shares))
+	for id, v := range m.shares {
+		c := *v
+		shares[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.shares = shares
+	}
+}
+
+// New creates a new share.
+func (m *Memory) New(oid int, params *NewParams) (*Share, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Share.
+	m.lastID++
+	share := &Share{
+		ID:         m.lastID,
+		OwnerID:    oid,
+		MemberID:   params.MemberID,
+		ListID:     copyInt(params.ListID),
+		TodoID:     copyInt(params.TodoID),
+		Permission: params.Permission,
+		Created:    time.Now(),
+	}
+
+	// Store a copy of the share.
+	m.shares[share.ID] = copyShare(share)
+
+	return share, nil
+}
+
+// Get gets a set of shares.
+func (m *Memory) Get(params *GetParams) (*Shares, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Create a new Shares.
+	shares := &Shares{
+		Shares: []*Share{},
+	}
+
+	// Add copies of the shares matching the filters.
+	for _, share := range m.shares {
+		if params.OwnerID != nil && share.OwnerID != *params.OwnerID {
+			continue
+		}
+		if params.MemberID != nil && share.MemberID != *params.MemberID {
+			continue
+		}
+		if params.ListID != nil && (share.ListID == nil || *share.ListID != *params.ListID) {
+			continue
+		}
+		if params.TodoID != nil && (share.TodoID == nil || *share.TodoID != *params.TodoID) {
+			continue
+		}
+		if params.Accepted != nil && (share.AcceptedAt != nil) != *params.Accepted {
+			continue
+		}
+
+		shares.Shares = append(shares.Shares, copyShare(share))
+	}
+	shares.Total = len(shares.Shares)
+
+	// Sort the shares by ID.
+	sort.Slice(shares.Shares, func(i, j int) bool {
+		return shares.Shares[i].ID < shares.Shares[j].ID
+	})
+
+	return shares, nil
+}
+
+// GetByID retrieves a share by its ID.
+func (m *Memory) GetByID(id int) (*Share, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	share, ok := m.shares[id]
+	if !ok {
+		return nil, ErrShareNotFound
+	}
+
+	// Return a copy of the share.
+	return copyShare(share), nil
+}
+
+// Update updates a share.
+func (m *Memory) Update(id int, params *UpdateParams) (*Share, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	share, ok := m.shares[id]
+	if !ok {
+		return nil, ErrShareNotFound
+	}
+
+	// Handle permission field.
+	if params.Permission != nil {
+		share.Permission = *params.Permission
+	}
+
+	// Handle accepted at field.
+	if params.AcceptedAt != nil {
+		share.AcceptedAt = copyTime(params.AcceptedAt)
+	}
+
+	// Return a copy of the share.
+	return copyShare(share), nil
+}
+
+// Delete deletes a share.
+func (m *Memory) Delete(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.shares[id]; !ok {
+		return ErrShareNotFound
+	}
+	delete(m.shares, id)
+
+	return nil
+}
+
+// DeleteByListID deletes every share of the list with the given ID.
+func (m *Memory) DeleteByListID(lid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, share := range m.shares {
+		if share.ListID != nil && *share.ListID == lid {
+			delete(m.shares, id)
+		}
+	}
+
+	return nil
+}
+
+// DeleteByTodoID deletes every share of the todo with the given ID.
+func (m *Memory) DeleteByTodoID(tid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, share := range m.shares {
+		if share.TodoID != nil && *share.TodoID == tid {
+			delete(m.shares, id)
+		}
+	}
+
+	return nil
+}
+
+// DeleteByTodoIDs deletes every share of the todos with the given IDs.
+func (m *Memory) DeleteByTodoIDs(tids []int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a set of the todo IDs.
+	deleted := make(map[int]bool)
+	for _, tid := range tids {
+		deleted[tid] = true
+	}
+
+	for id, share := range m.shares {
+		if share.TodoID != nil && deleted[*share.TodoID] {
+			delete(m.shares, id)
+		}
+	}
+
+	return nil
+}
+
+// DeleteByMemberID deletes every share a given member either owns or was
+// given.
+func (m *Memory) DeleteByMemberID(mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, share := range m.shares {
+		if share.OwnerID == mid || share.MemberID == mid {
+			delete(m.shares, id)
+		}
+	}
+
+	return nil
+}
+
+// copyShare returns a copy of the given share, so stored shares never share
+// memory with the caller.
+func copyShare(share *Share) *Share {
+	c := *share
+	c.ListID = copyInt(share.ListID)
+	c.TodoID = copyInt(share.TodoID)
+	c.AcceptedAt = copyTime(share.AcceptedAt)
+	return &c
+}
+
+// copyInt returns a copy of the given int.
+func copyInt(i *int) *int {
+	if i == nil {
+		return nil
+	}
+
+	c := *i
+	return &c
+}
+
+// copyTime returns a copy of the given time.
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	c := *t
+	return &c
+}
@@ -0,0 +1,94 @@
+package shares
+
+import "time"
+
+const (
+	// PermissionViewer lets a member see a shared list or todo.
+	PermissionViewer = "viewer"
+
+	// PermissionEditor lets a member see and change a shared list or
+	// todo.
+	PermissionEditor = "editor"
+)
+
+// Database defines the shares database.
+//
+// A share gives a member access to either a list or a todo of another
+// member, its owner. Shares are invitations until the member accepts them.
+type Database interface {
+	// New creates a new share.
+	New(oid int, params *NewParams) (*Share, error)
+
+	// Get gets a set of shares.
+	Get(params *GetParams) (*Shares, error)
+
+	// GetByID retrieves a share by its ID.
+	GetByID(id int) (*Share, error)
+
+	// Update updates a share.
+	Update(id int, params *UpdateParams) (*Share, error)
+
+	// Delete deletes a share.
+	Delete(id int) error
+
+	// DeleteByListID deletes every share of the list with the given ID.
+	DeleteByListID(lid int) error
+
+	// DeleteByTodoID deletes every share of the todo with the given ID.
+	DeleteByTodoID(tid int) error
+
+	// DeleteByTodoIDs deletes every share of the todos with the given
+	// IDs.
+	DeleteByTodoIDs(tids []int) error
+
+	// DeleteByMemberID deletes every share a given member either owns or
+	// was given.
+	DeleteByMemberID(mid int) error
+}
+
+// Share defines a share.
+//
+// Exactly one of ListID and TodoID is set. Shares without an AcceptedAt
+// are pending invitations, which do not give any access yet.
+type Share struct {
+	ID         int        `json:"id"`
+	OwnerID    int        `json:"owner_id"`
+	MemberID   int        `json:"member_id"`
+	ListID     *int       `json:"list_id"`
+	TodoID     *int       `json:"todo_id"`
+	Permission string     `json:"permission"`
+	Created    time.Time  `json:"created"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+}
+
+// Shares defines a set of shares.
+type Shares struct {
+	Shares []*Share `json:"shares"`
+	Total  int      `json:"total"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams struct {
+	MemberID   int    `json:"member_id"`
+	ListID     *int   `json:"list_id"`
+	TodoID     *int   `json:"todo_id"`
+	Permission string `json:"permission"`
+}
+
+// GetParams defines the parameters for the Get method.
+//
+// Accepted matches the shares which were accepted, or the pending
+// invitations when false.
+type GetParams struct {
+	OwnerID  *int  `json:"owner_id"`
+	MemberID *int  `json:"member_id"`
+	ListID   *int  `json:"list_id"`
+	TodoID   *int  `json:"todo_id"`
+	Accepted *bool `json:"accepted"`
+}
+
+// UpdateParams defines the parameters for the Update method.
+type UpdateParams struct {
+	Permission *string    `json:"permission"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+}
@@ -0,0 +1,349 @@
+package shares
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the shares database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL shares database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// columns defines the columns selected for
+	// a share, in the order they are scanned.
+	columns = `id, owner_id, member_id, list_id, todo_id, permission, created, accepted_at`
+
+	// stmtInsert defines the SQL statement to
+	// insert a new share into the database.
+	stmtInsert = `
+INSERT INTO shares (owner_id, member_id, list_id, todo_id, permission, created)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+	// stmtSelect defines the SQL statement to
+	// select a set of shares.
+	stmtSelect = `
+SELECT ` + columns + `
+FROM shares
+%s
+ORDER BY id
+`
+
+	// stmtSelectByID defines the SQL statement to
+	// select a share by its ID.
+	stmtSelectByID = `
+SELECT ` + columns + `
+FROM shares
+WHERE id=?
+`
+
+	// stmtUpdate defines the SQL statement to
+	// update a share.
+	stmtUpdate = `
+UPDATE shares
+SET %s
+WHERE id=?
+`
+
+	// stmtDelete defines the SQL statement to
+	// delete a share.
+	stmtDelete = `
+DELETE FROM shares
+WHERE id=?
+`
+
+	// stmtDeleteByListID defines the SQL statement
+	// to delete every share of a given list.
+	stmtDeleteByListID = `
+DELETE FROM shares
+WHERE list_id=?
+`
+
+	// stmtDeleteByTodoID defines the SQL statement
+	// to delete every share of a given todo.
+	stmtDeleteByTodoID = `
+DELETE FROM shares
+WHERE todo_id=?
+`
+
+	// stmtDeleteByTodoIDs defines the SQL statement
+	// to delete every share of a set of todos.
+	stmtDeleteByTodoIDs = `
+DELETE FROM shares
+WHERE todo_id IN (%s)
+`
+
+	// stmtDeleteByMemberID defines the SQL statement
+	// to delete every share a given member either
+	// owns or was given.
+	stmtDeleteByMemberID = `
+DELETE FROM shares
+WHERE owner_id=? OR member_id=?
+`
+)
+
+// New creates a new share.
+func (db *SQL) New(oid int, params *NewParams) (*Share, error) {
+	// Create a new Share.
+	share := &Share{
+		OwnerID:    oid,
+		MemberID:   params.MemberID,
+		ListID:     params.ListID,
+		TodoID:     params.TodoID,
+		Permission: params.Permission,
+		Created:    time.Now(),
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, share.OwnerID, share.MemberID, share.ListID, share.TodoID, share.Permission, share.Created)
+	if err != nil {
+		return nil, err
+	}
+	share.ID = id
+
+	return share, nil
+}
+
+// Get gets a set of shares.
+func (db *SQL) Get(params *GetParams) (*Shares, error) {
+	// Create variables to hold the query fields
+	// being filtered on and their values.
+	var queryFields string
+	var queryValues []interface{}
+
+	// Handle owner ID field.
+	if params.OwnerID != nil {
+		if queryFields == "" {
+			queryFields = "WHERE owner_id=?"
+		} else {
+			queryFields += " AND owner_id=?"
+		}
+
+		queryValues = append(queryValues, *params.OwnerID)
+	}
+
+	// Handle member ID field.
+	if params.MemberID != nil {
+		if queryFields == "" {
+			queryFields = "WHERE member_id=?"
+		} else {
+			queryFields += " AND member_id=?"
+		}
+
+		queryValues = append(queryValues, *params.MemberID)
+	}
+
+	// Handle list ID field.
+	if params.ListID != nil {
+		if queryFields == "" {
+			queryFields = "WHERE list_id=?"
+		} else {
+			queryFields += " AND list_id=?"
+		}
+
+		queryValues = append(queryValues, *params.ListID)
+	}
+
+	// Handle todo ID field.
+	if params.TodoID != nil {
+		if queryFields == "" {
+			queryFields = "WHERE todo_id=?"
+		} else {
+			queryFields += " AND todo_id=?"
+		}
+
+		queryValues = append(queryValues, *params.TodoID)
+	}
+
+	// Handle accepted field.
+	if params.Accepted != nil {
+		filter := "accepted_at IS NOT NULL"
+		if !*params.Accepted {
+			filter = "accepted_at IS NULL"
+		}
+
+		if queryFields == "" {
+			queryFields = "WHERE " + filter
+		} else {
+			queryFields += " AND " + filter
+		}
+	}
+
+	// Create a new Shares.
+	shares := &Shares{
+		Shares: []*Share{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(fmt.Sprintf(stmtSelect, queryFields), queryValues...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the share rows.
+	for rows.Next() {
+		// Create a new Share.
+		share := &Share{}
+
+		// Scan row values into share struct.
+		if err := scan(rows, share); err != nil {
+			return nil, err
+		}
+
+		// Add to shares set.
+		shares.Shares = append(shares.Shares, share)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	shares.Total = len(shares.Shares)
+
+	return shares, nil
+}
+
+// GetByID retrieves a share by its ID.
+func (db *SQL) GetByID(id int) (*Share, error) {
+	// Create a new Share.
+	share := &Share{}
+
+	// Execute the query.
+	err := scan(db.db.QueryRow(stmtSelectByID, id), share)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrShareNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return share, nil
+}
+
+// Update updates a share.
+func (db *SQL) Update(id int, params *UpdateParams) (*Share, error) {
+	// Create variables to hold the query fields
+	// being updated and their new values.
+	var queryFields string
+	var queryValues []interface{}
+
+	// Handle permission field.
+	if params.Permission != nil {
+		if queryFields == "" {
+			queryFields = "permission=?"
+		} else {
+			queryFields += ", permission=?"
+		}
+
+		queryValues = append(queryValues, *params.Permission)
+	}
+
+	// Handle accepted at field.
+	if params.AcceptedAt != nil {
+		if queryFields == "" {
+			queryFields = "accepted_at=?"
+		} else {
+			queryFields += ", accepted_at=?"
+		}
+
+		queryValues = append(queryValues, params.AcceptedAt.UTC())
+	}
+
+	// Check if the query is empty.
+	if queryFields == "" {
+		return db.GetByID(id)
+	}
+
+	// Build the full query.
+	query := fmt.Sprintf(stmtUpdate, queryFields)
+	queryValues = append(queryValues, id)
+
+	// Execute the query.
+	_, err := db.db.Exec(query, queryValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetByID(id)
+}
+
+// Delete deletes a share.
+func (db *SQL) Delete(id int) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtDelete, id)
+	if err != nil {
+		return err
+	}
+
+	// Check if a share was deleted.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrShareNotFound
+	}
+
+	return nil
+}
+
+// DeleteByListID deletes every share of the list with the given ID.
+func (db *SQL) DeleteByListID(lid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByListID, lid)
+	return err
+}
+
+// DeleteByTodoID deletes every share of the todo with the given ID.
+func (db *SQL) DeleteByTodoID(tid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByTodoID, tid)
+	return err
+}
+
+// DeleteByTodoIDs deletes every share of the todos with the given IDs.
+func (db *SQL) DeleteByTodoIDs(tids []int) error {
+	// Check there is anything to delete.
+	if len(tids) == 0 {
+		return nil
+	}
+
+	// Get the IDs as query values.
+	queryValues := make([]interface{}, len(tids))
+	for i, tid := range tids {
+		queryValues[i] = tid
+	}
+
+	// Execute the query.
+	_, err := db.db.Exec(fmt.Sprintf(stmtDeleteByTodoIDs, dialect.Placeholders(len(tids))), queryValues...)
+	return err
+}
+
+// DeleteByMemberID deletes every share a given member either owns or was
+// given.
+func (db *SQL) DeleteByMemberID(mid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByMemberID, mid, mid)
+	return err
+}
+
+// scanner defines the Scan method shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan scans a share row into the given share.
+func scan(row scanner, share *Share) error {
+	return row.Scan(&share.ID, &share.OwnerID, &share.MemberID, &share.ListID, &share.TodoID, &share.Permission, &share.Created, &share.AcceptedAt)
+}
@@ -5,4 +5,7 @@ import "errors"
 var (
 	// ErrTodoNotFound is returned when a todo could not be found.
 	ErrTodoNotFound = errors.New("Todo could not be found")
+
+	// ErrTagNotFound is returned when a tag could not be found.
+	ErrTagNotFound = errors.New("Tag could not be found")
 )
@@ -0,0 +1,823 @@
+package todos
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory defines the todos database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits.
+type Memory struct {
+	mu        sync.RWMutex
+	lastID    int
+	todos     map[int]*Todo
+	lastTagID int
+	tags      map[int]*Tag
+}
+
+// NewMemory creates a new in-memory todos database.
+func NewMemory() *Memory {
+	return &Memory{
+		todos: make(map[int]*Todo),
+		tags:  make(map[int]*Tag),
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	lastTagID := m.lastTagID
+	todos := make(map[int]*Todo, len(m.todos))
+	for id, v := range m.todos {
+		c := *v
+		todos[id] = &c
+	}
+	tags := make(map[int]*Tag, len(m.tags))
+	for id, v := range m.tags {
+		c := *v
+		tags[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.lastTagID = lastTagID
+		m.todos = todos
+		m.tags = tags
+	}
+}
+
+// New creates a new todo.
+func (m *Memory) New(mid int, params *NewParams) (*Todo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Todo.
+	m.lastID++
+	todo := &Todo{
+		ID:          m.lastID,
+		MemberID:    mid,
+		WorkspaceID: copyInt(params.WorkspaceID),
+		AssigneeID:  copyInt(params.AssigneeID),
+		ListID:      copyInt(params.ListID),
+		ParentID:    copyInt(params.ParentID),
+		SeriesID:    copyInt(params.SeriesID),
+		Created:     time.Now(),
+		Detail:      params.Detail,
+		DueAt:       copyTime(params.DueAt),
+		RemindAt:    copyTime(params.RemindAt),
+		Tags:        m.setTags(mid, params.Tags),
+	}
+
+	// Store a copy of the todo.
+	stored := *todo
+	stored.Tags = copyTags(todo.Tags)
+	m.todos[todo.ID] = &stored
+
+	return todo, nil
+}
+
+// Get gets a set of todos.
+func (m *Memory) Get(params *GetParams) (*Todos, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Find all of the todos matching the filters.
+	now := time.Now()
+	var matches []*Todo
+	for _, todo := range m.todos {
+		if params.ID != nil && todo.ID != *params.ID {
+			continue
+		}
+		if params.MemberID != nil && todo.MemberID != *params.MemberID && !isShared(todo, params) && !isAssigned(todo, params) {
+			continue
+		}
+		if params.WorkspaceID != nil && (todo.WorkspaceID == nil || *todo.WorkspaceID != *params.WorkspaceID) {
+			continue
+		}
+		if params.Personal && todo.WorkspaceID != nil {
+			continue
+		}
+		if params.AssigneeID != nil && (todo.AssigneeID == nil || *todo.AssigneeID != *params.AssigneeID) {
+			continue
+		}
+		if params.Unassigned && todo.AssigneeID != nil {
+			continue
+		}
+		if params.ListID != nil && (todo.ListID == nil || *todo.ListID != *params.ListID) {
+			continue
+		}
+		if params.Inbox && todo.ListID != nil {
+			continue
+		}
+		if params.Created != nil && !todo.Created.Equal(*params.Created) {
+			continue
+		}
+		if params.Completed != nil && todo.Completed != *params.Completed {
+			continue
+		}
+		if params.DueBefore != nil && (todo.DueAt == nil || !todo.DueAt.Before(*params.DueBefore)) {
+			continue
+		}
+		if params.DueAfter != nil && (todo.DueAt == nil || !todo.DueAt.After(*params.DueAfter)) {
+			continue
+		}
+		if params.Overdue != nil && isOverdue(todo, now) != *params.Overdue {
+			continue
+		}
+		if len(params.Tags) > 0 && !hasTags(todo, params.Tags, params.TagsAll) {
+			continue
+		}
+
+		matches = append(matches, todo)
+	}
+
+	// Sort the todos by ID so pagination is stable.
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].ID < matches[j].ID
+	})
+
+	// Create a new Todos.
+	todos := &Todos{
+		Todos: []*Todo{},
+		Total: len(matches),
+	}
+
+	// Add copies of the requested page of todos.
+	for i := params.Offset; i < len(matches) && i < params.Offset+params.Limit; i++ {
+		todo := *matches[i]
+		todo.Tags = copyTags(todo.Tags)
+		todos.Todos = append(todos.Todos, &todo)
+	}
+
+	return todos, nil
+}
+
+// GetByID retrieves a todo by its ID.
+func (m *Memory) GetByID(id int) (*Todo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	todo, ok := m.todos[id]
+	if !ok {
+		return nil, ErrTodoNotFound
+	}
+
+	// Return a copy of the todo.
+	found := *todo
+	found.Tags = copyTags(todo.Tags)
+	return &found, nil
+}
+
+// GetByIDAndMemberID retrieves a todo by its ID and member ID.
+func (m *Memory) GetByIDAndMemberID(id, mid int) (*Todo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	todo, ok := m.todos[id]
+	if !ok || todo.MemberID != mid {
+		return nil, ErrTodoNotFound
+	}
+
+	// Return a copy of the todo.
+	found := *todo
+	found.Tags = copyTags(todo.Tags)
+	return &found, nil
+}
+
+// GetByParentID retrieves the subtasks of the todo with the given ID.
+func (m *Memory) GetByParentID(pid int) (*Todos, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Create a new Todos.
+	todos := &Todos{
+		Todos: []*Todo{},
+	}
+
+	// Add copies of the subtasks of the todo.
+	for _, todo := range m.todos {
+		if todo.ParentID != nil && *todo.ParentID == pid {
+			found := *todo
+			found.Tags = copyTags(todo.Tags)
+			todos.Todos = append(todos.Todos, &found)
+		}
+	}
+
+	// Sort the todos by ID.
+	sort.Slice(todos.Todos, func(i, j int) bool {
+		return todos.Todos[i].ID < todos.Todos[j].ID
+	})
+	todos.Total = len(todos.Todos)
+
+	return todos, nil
+}
+
+// Update updates a todo.
+func (m *Memory) Update(id int, params *UpdateParams) (*Todo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	todo, ok := m.todos[id]
+	if !ok {
+		return nil, ErrTodoNotFound
+	}
+
+	// Handle the fields being updated.
+	if params.ListID != nil {
+		todo.ListID = copyInt(params.ListID)
+	}
+	if params.ClearListID {
+		todo.ListID = nil
+	}
+	if params.ParentID != nil {
+		todo.ParentID = copyInt(params.ParentID)
+	}
+	if params.ClearParentID {
+		todo.ParentID = nil
+	}
+	if params.AssigneeID != nil {
+		todo.AssigneeID = copyInt(params.AssigneeID)
+	}
+	if params.ClearAssigneeID {
+		todo.AssigneeID = nil
+	}
+	if params.SeriesID != nil {
+		todo.SeriesID = copyInt(params.SeriesID)
+	}
+	if params.Created != nil {
+		todo.Created = *params.Created
+	}
+	if params.Detail != nil {
+		todo.Detail = *params.Detail
+	}
+	if params.Completed != nil {
+		todo.Completed = *params.Completed
+	}
+	if params.DueAt != nil {
+		todo.DueAt = copyTime(params.DueAt)
+	}
+	if params.ClearDueAt {
+		todo.DueAt = nil
+	}
+	if params.RemindAt != nil {
+		todo.RemindAt = copyTime(params.RemindAt)
+	}
+	if params.ClearRemindAt {
+		todo.RemindAt = nil
+	}
+	if params.Tags != nil {
+		todo.Tags = m.setTags(todo.MemberID, params.Tags)
+	}
+
+	// Return a copy of the todo.
+	updated := *todo
+	updated.Tags = copyTags(todo.Tags)
+	return &updated, nil
+}
+
+// DeleteByIDAndMemberID deletes a todo by its ID and member ID, returning
+// the number of todos deleted.
+func (m *Memory) DeleteByIDAndMemberID(id, mid int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	todo, ok := m.todos[id]
+	if !ok || todo.MemberID != mid {
+		return 0, nil
+	}
+	delete(m.todos, id)
+	m.detachSubtasks(map[int]bool{id: true})
+
+	return 1, nil
+}
+
+// DeleteByMemberID deletes the set of todos belonging to the given member
+// that match the given filters, returning the number of todos deleted.
+func (m *Memory) DeleteByMemberID(mid int, params *DeleteParams) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Delete all of the todos matching the filters.
+	deleted := make(map[int]bool)
+	for _, id := range m.matchDelete(mid, params) {
+		delete(m.todos, id)
+		deleted[id] = true
+	}
+	m.detachSubtasks(deleted)
+
+	return len(deleted), nil
+}
+
+// GetIDsByMemberID gets the IDs of the set of todos belonging to the given
+// member that match the given filters, which are the todos DeleteByMemberID
+// would delete.
+func (m *Memory) GetIDsByMemberID(mid int, params *DeleteParams) ([]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.matchDelete(mid, params), nil
+}
+
+// matchDelete returns the IDs of the todos of the given member matching the
+// given filters, sorted.
+func (m *Memory) matchDelete(mid int, params *DeleteParams) []int {
+	// Create a set of the IDs being filtered on.
+	ids := make(map[int]bool)
+	for _, id := range params.IDs {
+		ids[id] = true
+	}
+
+	// Find the todos matching the filters.
+	matches := []int{}
+	for id, todo := range m.todos {
+		if todo.MemberID != mid {
+			continue
+		}
+		if params.WorkspaceID != nil && (todo.WorkspaceID == nil || *todo.WorkspaceID != *params.WorkspaceID) {
+			continue
+		}
+		if params.Personal && todo.WorkspaceID != nil {
+			continue
+		}
+		if len(ids) > 0 && !ids[id] {
+			continue
+		}
+		if params.ListID != nil && (todo.ListID == nil || *todo.ListID != *params.ListID) {
+			continue
+		}
+		if params.Created != nil && !todo.Created.Equal(*params.Created) {
+			continue
+		}
+		if params.Completed != nil && todo.Completed != *params.Completed {
+			continue
+		}
+
+		matches = append(matches, id)
+	}
+	sort.Ints(matches)
+
+	return matches
+}
+
+// DeleteByListID deletes every todo of the list with the given ID,
+// returning the number of todos deleted.
+func (m *Memory) DeleteByListID(lid int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deleted := make(map[int]bool)
+	for id, todo := range m.todos {
+		if todo.ListID != nil && *todo.ListID == lid {
+			delete(m.todos, id)
+			deleted[id] = true
+		}
+	}
+	m.detachSubtasks(deleted)
+
+	return len(deleted), nil
+}
+
+// DeleteByWorkspaceID deletes every todo of the workspace with the given
+// ID, returning the number of todos deleted.
+func (m *Memory) DeleteByWorkspaceID(wid int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deleted := make(map[int]bool)
+	for id, todo := range m.todos {
+		if todo.WorkspaceID != nil && *todo.WorkspaceID == wid {
+			delete(m.todos, id)
+			deleted[id] = true
+		}
+	}
+	m.detachSubtasks(deleted)
+
+	return len(deleted), nil
+}
+
+// GetIDsByListID gets the IDs of every todo of the list with the given ID,
+// which are the todos DeleteByListID would delete.
+func (m *Memory) GetIDsByListID(lid int) ([]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := []int{}
+	for id, todo := range m.todos {
+		if todo.ListID != nil && *todo.ListID == lid {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+// GetIDsByWorkspaceID gets the IDs of every todo of the workspace with the
+// given ID, which are the todos DeleteByWorkspaceID would delete.
+func (m *Memory) GetIDsByWorkspaceID(wid int) ([]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := []int{}
+	for id, todo := range m.todos {
+		if todo.WorkspaceID != nil && *todo.WorkspaceID == wid {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+// UnassignByMemberID removes the given member as the assignee of every
+// todo, or only of the todos of the given workspace when it is not nil.
+func (m *Memory) UnassignByMemberID(mid int, wid *int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, todo := range m.todos {
+		if todo.AssigneeID == nil || *todo.AssigneeID != mid {
+			continue
+		}
+		if wid != nil && (todo.WorkspaceID == nil || *todo.WorkspaceID != *wid) {
+			continue
+		}
+
+		todo.AssigneeID = nil
+	}
+
+	return nil
+}
+
+// GetIDsByAssigneeID gets the IDs of every todo assigned to the given
+// member, or only of the todos of the given workspace when it is not nil,
+// which are the todos UnassignByMemberID would unassign.
+func (m *Memory) GetIDsByAssigneeID(mid int, wid *int) ([]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := []int{}
+	for id, todo := range m.todos {
+		if todo.AssigneeID == nil || *todo.AssigneeID != mid {
+			continue
+		}
+		if wid != nil && (todo.WorkspaceID == nil || *todo.WorkspaceID != *wid) {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+// MoveToInbox removes the todos of the list with the given ID from the list,
+// returning the number of todos moved.
+func (m *Memory) MoveToInbox(lid int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var moved int
+	for _, todo := range m.todos {
+		if todo.ListID != nil && *todo.ListID == lid {
+			todo.ListID = nil
+			moved++
+		}
+	}
+
+	return moved, nil
+}
+
+// detachSubtasks turns the subtasks of the deleted todos with the given IDs
+// into top level todos.
+//
+// The caller must hold the write lock.
+func (m *Memory) detachSubtasks(deleted map[int]bool) {
+	for _, todo := range m.todos {
+		if todo.ParentID != nil && deleted[*todo.ParentID] {
+			todo.ParentID = nil
+		}
+	}
+}
+
+// GetTagsByMemberID retrieves the tags of a given member.
+func (m *Memory) GetTagsByMemberID(mid int) (*Tags, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Create a new Tags.
+	tags := &Tags{
+		Tags: []*Tag{},
+	}
+
+	// Add copies of the tags belonging to this member.
+	for _, tag := range m.tags {
+		if tag.MemberID == mid {
+			tags.Tags = append(tags.Tags, m.copyTag(tag))
+		}
+	}
+
+	// Sort the tags by name.
+	sort.Slice(tags.Tags, func(i, j int) bool {
+		return tags.Tags[i].Name < tags.Tags[j].Name
+	})
+	tags.Total = len(tags.Tags)
+
+	return tags, nil
+}
+
+// GetTagByIDAndMemberID retrieves a tag by its ID and member ID.
+func (m *Memory) GetTagByIDAndMemberID(id, mid int) (*Tag, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tag, ok := m.tags[id]
+	if !ok || tag.MemberID != mid {
+		return nil, ErrTagNotFound
+	}
+
+	return m.copyTag(tag), nil
+}
+
+// UpdateTag updates a tag.
+func (m *Memory) UpdateTag(id int, params *UpdateTagParams) (*Tag, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tag, ok := m.tags[id]
+	if !ok {
+		return nil, ErrTagNotFound
+	}
+
+	// Handle name field, renaming the tag on every
+	// todo it is set on.
+	if params.Name != nil {
+		m.replaceTag(tag.MemberID, []string{tag.Name}, *params.Name)
+		tag.Name = *params.Name
+	}
+
+	return m.copyTag(tag), nil
+}
+
+// MergeTags moves the todos of the tags with the given IDs over to the tag
+// with the given ID, then deletes the merged tags.
+func (m *Memory) MergeTags(id int, ids []int) (*Tag, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tag, ok := m.tags[id]
+	if !ok {
+		return nil, ErrTagNotFound
+	}
+
+	// Delete the merged tags.
+	var names []string
+	for _, mergeID := range ids {
+		merged, ok := m.tags[mergeID]
+		if !ok || mergeID == id {
+			continue
+		}
+
+		names = append(names, merged.Name)
+		delete(m.tags, mergeID)
+	}
+
+	// Replace the merged tags on every todo.
+	m.replaceTag(tag.MemberID, names, tag.Name)
+
+	return m.copyTag(tag), nil
+}
+
+// DeleteTagByIDAndMemberID deletes a tag by its ID and member ID, removing
+// it from every todo.
+func (m *Memory) DeleteTagByIDAndMemberID(id, mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tag, ok := m.tags[id]
+	if !ok || tag.MemberID != mid {
+		return ErrTagNotFound
+	}
+	delete(m.tags, id)
+
+	// Remove the tag from every todo.
+	m.replaceTag(mid, []string{tag.Name}, "")
+
+	return nil
+}
+
+// DeleteTagsByMemberID deletes every tag of a given member, removing them
+// from every todo.
+func (m *Memory) DeleteTagsByMemberID(mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var names []string
+	for id, tag := range m.tags {
+		if tag.MemberID == mid {
+			names = append(names, tag.Name)
+			delete(m.tags, id)
+		}
+	}
+
+	// Remove the tags from every todo.
+	if len(names) > 0 {
+		m.replaceTag(mid, names, "")
+	}
+
+	return nil
+}
+
+// GetIDsByTagIDs gets the IDs of every todo any of the tags with the given
+// IDs is set on.
+func (m *Memory) GetIDsByTagIDs(ids []int) ([]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	found := []int{}
+	for id, todo := range m.todos {
+		for _, tid := range ids {
+			tag, ok := m.tags[tid]
+			if ok && todo.MemberID == tag.MemberID && contains(todo.Tags, tag.Name) {
+				found = append(found, id)
+				break
+			}
+		}
+	}
+	sort.Ints(found)
+
+	return found, nil
+}
+
+// setTags returns the sorted tag names to store on a todo of the given
+// member, creating any of the member's tags that do not exist yet.
+//
+// The caller must hold the write lock.
+func (m *Memory) setTags(mid int, names []string) []string {
+	tags := []string{}
+	for _, name := range names {
+		// Create the tag if it does not exist yet.
+		var found bool
+		for _, tag := range m.tags {
+			if tag.MemberID == mid && tag.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			m.lastTagID++
+			m.tags[m.lastTagID] = &Tag{
+				ID:       m.lastTagID,
+				MemberID: mid,
+				Name:     name,
+			}
+		}
+
+		tags = append(tags, name)
+	}
+	sort.Strings(tags)
+
+	return tags
+}
+
+// replaceTag replaces the given tag names on every todo of the given member
+// with the new tag name, or removes them if the new name is empty.
+//
+// The caller must hold the write lock.
+func (m *Memory) replaceTag(mid int, names []string, name string) {
+	for _, todo := range m.todos {
+		if todo.MemberID != mid || !hasTags(todo, names, false) {
+			continue
+		}
+
+		// Keep the tags that are not being replaced.
+		tags := []string{}
+		for _, t := range todo.Tags {
+			if !contains(names, t) && t != name {
+				tags = append(tags, t)
+			}
+		}
+		if name != "" {
+			tags = append(tags, name)
+		}
+		sort.Strings(tags)
+
+		todo.Tags = tags
+	}
+}
+
+// copyTag returns a copy of the given tag along with the number of todos
+// it is set on.
+//
+// The caller must hold the read lock.
+func (m *Memory) copyTag(tag *Tag) *Tag {
+	found := *tag
+	found.Todos = 0
+	for _, todo := range m.todos {
+		if todo.MemberID == tag.MemberID && contains(todo.Tags, tag.Name) {
+			found.Todos++
+		}
+	}
+
+	return &found
+}
+
+// hasTags returns whether the given todo has any of the given tags, or all
+// of them if all is set.
+func hasTags(todo *Todo, tags []string, all bool) bool {
+	for _, tag := range tags {
+		if contains(todo.Tags, tag) != all {
+			return !all
+		}
+	}
+
+	return all
+}
+
+// contains returns whether the given names contain the given name.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// copyTags returns a copy of the given tag names, so stored todos never
+// share a slice with the caller.
+func copyTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+
+	return append([]string{}, tags...)
+}
+
+// isShared returns whether the given todo matches the todos shared with the
+// member being filtered on.
+func isShared(todo *Todo, params *GetParams) bool {
+	for _, id := range params.SharedIDs {
+		if todo.ID == id {
+			return true
+		}
+	}
+
+	if todo.ListID != nil {
+		for _, lid := range params.SharedListIDs {
+			if *todo.ListID == lid {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isAssigned returns whether the given todo is assigned to the member being
+// filtered on, when the todos assigned to them are matched.
+func isAssigned(todo *Todo, params *GetParams) bool {
+	return params.Assigned && todo.AssigneeID != nil && *todo.AssigneeID == *params.MemberID
+}
+
+// isOverdue returns whether the given todo is not completed and its due date
+// has passed.
+func isOverdue(todo *Todo, now time.Time) bool {
+	return !todo.Completed && todo.DueAt != nil && todo.DueAt.Before(now)
+}
+
+// copyInt returns a copy of the given int, so stored todos never share an
+// int with the caller.
+func copyInt(i *int) *int {
+	if i == nil {
+		return nil
+	}
+
+	c := *i
+	return &c
+}
+
+// copyTime returns a copy of the given time, so stored todos never share a
+// time with the caller.
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	c := *t
+	return &c
+}
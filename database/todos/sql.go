@@ -0,0 +1,1299 @@
+package todos
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the todos database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL todos database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// columns defines the columns selected for
+	// a todo, in the order they are scanned.
+	columns = `id, member_id, workspace_id, assignee_id, list_id, parent_id, series_id, created, detail, completed, due_at, remind_at`
+
+	// stmtInsert defines the SQL statement to
+	// insert a new todo into the database.
+	stmtInsert = `
+INSERT INTO todos (member_id, workspace_id, assignee_id, list_id, parent_id, series_id, created, detail, completed, due_at, remind_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+	// stmtSelect defines the SQL statement to
+	// select a set of todos for a given member.
+	stmtSelect = `
+SELECT ` + columns + `
+FROM todos
+%s
+ORDER BY id
+%s
+`
+
+	// stmtSelectCount defines the SQL statement to
+	// select the total number of todos found for a
+	// a given member, according to the filters.
+	stmtSelectCount = `
+SELECT COUNT(*)
+FROM todos
+%s
+`
+
+	// stmtSelectByID defines the SQL statement to
+	// select a todo by its ID.
+	stmtSelectByID = `
+SELECT ` + columns + `
+FROM todos
+WHERE id=?
+`
+
+	// stmtSelectByIDAndMemberID defines the SQL statement
+	// to select a todo by its ID and member ID.
+	stmtSelectByIDAndMemberID = `
+SELECT ` + columns + `
+FROM todos
+WHERE id=? AND member_id=?
+`
+
+	// stmtSelectByParentID defines the SQL statement
+	// to select the subtasks of a todo.
+	stmtSelectByParentID = `
+SELECT ` + columns + `
+FROM todos
+WHERE parent_id=?
+ORDER BY id
+`
+
+	// stmtUpdate defines the SQL statement to
+	// update a todo.
+	stmtUpdate = `
+UPDATE todos
+SET %s
+WHERE id=?
+`
+
+	// stmtDeleteByIDAndMemberID defines the SQL statement
+	// to delete a todo by its ID and member ID.
+	stmtDeleteByIDAndMemberID = `
+DELETE FROM todos
+WHERE id=? AND member_id=?
+`
+
+	// stmtDeleteByIDs defines the SQL statement to
+	// delete a set of todos by their IDs.
+	stmtDeleteByIDs = `
+DELETE FROM todos
+WHERE id IN (%s)
+`
+
+	// stmtSelectIDsByMemberID defines the SQL
+	// statement to select the IDs of a set of todos
+	// for a given member.
+	stmtSelectIDsByMemberID = `
+SELECT id
+FROM todos
+WHERE member_id=?%s
+ORDER BY id
+`
+
+	// stmtSelectIDsByListID defines the SQL statement
+	// to select the IDs of every todo of a given list.
+	stmtSelectIDsByListID = `
+SELECT id
+FROM todos
+WHERE list_id=?
+ORDER BY id
+`
+
+	// stmtSelectIDsByWorkspaceID defines the SQL
+	// statement to select the IDs of every todo of a
+	// given workspace.
+	stmtSelectIDsByWorkspaceID = `
+SELECT id
+FROM todos
+WHERE workspace_id=?
+ORDER BY id
+`
+
+	// stmtSelectIDsByAssigneeID defines the SQL
+	// statement to select the IDs of every todo
+	// assigned to a given member.
+	stmtSelectIDsByAssigneeID = `
+SELECT id
+FROM todos
+WHERE assignee_id=?%s
+ORDER BY id
+`
+
+	// stmtDetachSubtasks defines the SQL statement to
+	// turn the subtasks of a set of todos into top
+	// level todos.
+	stmtDetachSubtasks = `
+UPDATE todos
+SET parent_id=NULL
+WHERE parent_id IN (%s)
+`
+
+	// stmtUnassignByMemberID defines the SQL statement
+	// to remove a member as the assignee of every todo.
+	stmtUnassignByMemberID = `
+UPDATE todos
+SET assignee_id=NULL
+WHERE assignee_id=?%s
+`
+
+	// stmtMoveToInbox defines the SQL statement to
+	// remove the todos of a list from the list.
+	stmtMoveToInbox = `
+UPDATE todos
+SET list_id=NULL
+WHERE list_id=?
+`
+
+	// stmtSelectTodoTags defines the SQL statement to
+	// select the tag names of a set of todos.
+	stmtSelectTodoTags = `
+SELECT tt.todo_id, t.name
+FROM todo_tags tt
+INNER JOIN tags t ON t.id=tt.tag_id
+WHERE tt.todo_id IN (%s)
+ORDER BY t.name
+`
+
+	// stmtSelectTodoIDsByTags defines the SQL statement
+	// to select the IDs of the todos with any of a set
+	// of tag names.
+	stmtSelectTodoIDsByTags = `id IN (
+SELECT tt.todo_id
+FROM todo_tags tt
+INNER JOIN tags t ON t.id=tt.tag_id
+WHERE t.name IN (%s)%s
+)`
+
+	// stmtInsertTodoTag defines the SQL statement to
+	// set a tag on a todo.
+	stmtInsertTodoTag = `
+INSERT INTO todo_tags (todo_id, tag_id)
+VALUES (?, ?)
+`
+
+	// stmtDeleteTodoTags defines the SQL statement to
+	// remove every tag of a todo.
+	stmtDeleteTodoTags = `
+DELETE FROM todo_tags
+WHERE todo_id=?
+`
+
+	// stmtDeleteTodoTagsByIDs defines the SQL statement
+	// to remove every tag of a set of todos.
+	stmtDeleteTodoTagsByIDs = `
+DELETE FROM todo_tags
+WHERE todo_id IN (%s)
+`
+
+	// stmtInsertTag defines the SQL statement to
+	// insert a new tag into the database.
+	stmtInsertTag = `
+INSERT INTO tags (member_id, name)
+VALUES (?, ?)
+`
+
+	// stmtSelectTagIDByName defines the SQL statement
+	// to select the ID of a tag by its member ID and
+	// name.
+	stmtSelectTagIDByName = `
+SELECT id
+FROM tags
+WHERE member_id=? AND name=?
+`
+
+	// stmtSelectTags defines the SQL statement to
+	// select a set of tags along with the number of
+	// todos they are set on.
+	stmtSelectTags = `
+SELECT t.id, t.member_id, t.name, COUNT(tt.todo_id)
+FROM tags t
+LEFT JOIN todo_tags tt ON tt.tag_id=t.id
+WHERE %s
+GROUP BY t.id, t.member_id, t.name
+ORDER BY t.name
+`
+
+	// stmtUpdateTag defines the SQL statement to
+	// update a tag.
+	stmtUpdateTag = `
+UPDATE tags
+SET name=?
+WHERE id=?
+`
+
+	// stmtMergeTodoTags defines the SQL statement to
+	// move the todos of a tag over to another tag,
+	// skipping todos that already have the other tag.
+	//
+	// The nested select is needed since MySQL cannot
+	// select from the table being updated otherwise.
+	stmtMergeTodoTags = `
+UPDATE todo_tags
+SET tag_id=?
+WHERE tag_id=? AND todo_id NOT IN (
+  SELECT todo_id FROM (SELECT todo_id FROM todo_tags WHERE tag_id=?) existing
+)
+`
+
+	// stmtDeleteTagTodoTags defines the SQL statement
+	// to remove a tag from every todo.
+	stmtDeleteTagTodoTags = `
+DELETE FROM todo_tags
+WHERE tag_id=?
+`
+
+	// stmtDeleteTag defines the SQL statement to
+	// delete a tag by its ID.
+	stmtDeleteTag = `
+DELETE FROM tags
+WHERE id=?
+`
+
+	// stmtSelectIDsByTagIDs defines the SQL statement
+	// to select the IDs of every todo any of a set of
+	// tags is set on.
+	stmtSelectIDsByTagIDs = `
+SELECT DISTINCT todo_id
+FROM todo_tags
+WHERE tag_id IN (%s)
+ORDER BY todo_id
+`
+
+	// stmtDeleteMemberTodoTags defines the SQL statement
+	// to remove every tag of a given member from every
+	// todo.
+	stmtDeleteMemberTodoTags = `
+DELETE FROM todo_tags
+WHERE tag_id IN (SELECT id FROM tags WHERE member_id=?)
+`
+
+	// stmtDeleteTagsByMemberID defines the SQL statement
+	// to delete every tag of a given member.
+	stmtDeleteTagsByMemberID = `
+DELETE FROM tags
+WHERE member_id=?
+`
+)
+
+// New creates a new todo.
+func (db *SQL) New(mid int, params *NewParams) (*Todo, error) {
+	// Create a new Todo.
+	todo := &Todo{
+		MemberID:    mid,
+		WorkspaceID: params.WorkspaceID,
+		AssigneeID:  params.AssigneeID,
+		ListID:      params.ListID,
+		ParentID:    params.ParentID,
+		SeriesID:    params.SeriesID,
+		Created:     time.Now(),
+		Detail:      params.Detail,
+		DueAt:       utc(params.DueAt),
+		RemindAt:    utc(params.RemindAt),
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, todo.MemberID, todo.WorkspaceID, todo.AssigneeID, todo.ListID, todo.ParentID, todo.SeriesID, todo.Created, todo.Detail, todo.Completed, todo.DueAt, todo.RemindAt)
+	if err != nil {
+		return nil, err
+	}
+	todo.ID = id
+
+	// Set the tags of the todo.
+	if err := db.setTags(todo.ID, todo.MemberID, params.Tags); err != nil {
+		return nil, err
+	}
+	if err := db.loadTags([]*Todo{todo}); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// Get gets a set of todos.
+func (db *SQL) Get(params *GetParams) (*Todos, error) {
+	// Create variables to hold the query fields
+	// being filtered on and their values.
+	var queryFields string
+	var queryValues []interface{}
+
+	// Handle ID field.
+	if params.ID != nil {
+		if queryFields == "" {
+			queryFields = "WHERE id=?"
+		} else {
+			queryFields += " AND id=?"
+		}
+
+		queryValues = append(queryValues, *params.ID)
+	}
+
+	// Handle member ID field, along with the todos
+	// shared with or assigned to the member.
+	if params.MemberID != nil {
+		filter := "member_id=?"
+		queryValues = append(queryValues, *params.MemberID)
+		if params.Assigned {
+			filter += " OR assignee_id=?"
+			queryValues = append(queryValues, *params.MemberID)
+		}
+		if len(params.SharedIDs) > 0 {
+			filter += " OR id IN (" + dialect.Placeholders(len(params.SharedIDs)) + ")"
+			for _, id := range params.SharedIDs {
+				queryValues = append(queryValues, id)
+			}
+		}
+		if len(params.SharedListIDs) > 0 {
+			filter += " OR list_id IN (" + dialect.Placeholders(len(params.SharedListIDs)) + ")"
+			for _, lid := range params.SharedListIDs {
+				queryValues = append(queryValues, lid)
+			}
+		}
+
+		if queryFields == "" {
+			queryFields = "WHERE (" + filter + ")"
+		} else {
+			queryFields += " AND (" + filter + ")"
+		}
+	}
+
+	// Handle workspace ID field.
+	if params.WorkspaceID != nil {
+		if queryFields == "" {
+			queryFields = "WHERE workspace_id=?"
+		} else {
+			queryFields += " AND workspace_id=?"
+		}
+
+		queryValues = append(queryValues, *params.WorkspaceID)
+	}
+
+	// Handle personal field.
+	if params.Personal {
+		if queryFields == "" {
+			queryFields = "WHERE workspace_id IS NULL"
+		} else {
+			queryFields += " AND workspace_id IS NULL"
+		}
+	}
+
+	// Handle assignee ID field.
+	if params.AssigneeID != nil {
+		if queryFields == "" {
+			queryFields = "WHERE assignee_id=?"
+		} else {
+			queryFields += " AND assignee_id=?"
+		}
+
+		queryValues = append(queryValues, *params.AssigneeID)
+	}
+
+	// Handle unassigned field.
+	if params.Unassigned {
+		if queryFields == "" {
+			queryFields = "WHERE assignee_id IS NULL"
+		} else {
+			queryFields += " AND assignee_id IS NULL"
+		}
+	}
+
+	// Handle list ID field.
+	if params.ListID != nil {
+		if queryFields == "" {
+			queryFields = "WHERE list_id=?"
+		} else {
+			queryFields += " AND list_id=?"
+		}
+
+		queryValues = append(queryValues, *params.ListID)
+	}
+
+	// Handle inbox field.
+	if params.Inbox {
+		if queryFields == "" {
+			queryFields = "WHERE list_id IS NULL"
+		} else {
+			queryFields += " AND list_id IS NULL"
+		}
+	}
+
+	// Handle created field.
+	if params.Created != nil {
+		if queryFields == "" {
+			queryFields = "WHERE created=?"
+		} else {
+			queryFields += " AND created=?"
+		}
+
+		queryValues = append(queryValues, *params.Created)
+	}
+
+	// Handle completed field.
+	if params.Completed != nil {
+		if queryFields == "" {
+			queryFields = "WHERE completed=?"
+		} else {
+			queryFields += " AND completed=?"
+		}
+
+		queryValues = append(queryValues, *params.Completed)
+	}
+
+	// Handle due before field.
+	if params.DueBefore != nil {
+		if queryFields == "" {
+			queryFields = "WHERE due_at<?"
+		} else {
+			queryFields += " AND due_at<?"
+		}
+
+		queryValues = append(queryValues, params.DueBefore.UTC())
+	}
+
+	// Handle due after field.
+	if params.DueAfter != nil {
+		if queryFields == "" {
+			queryFields = "WHERE due_at>?"
+		} else {
+			queryFields += " AND due_at>?"
+		}
+
+		queryValues = append(queryValues, params.DueAfter.UTC())
+	}
+
+	// Handle overdue field. A todo is overdue when
+	// it is not completed and its due date passed.
+	if params.Overdue != nil {
+		filter := "due_at<? AND completed=?"
+		if !*params.Overdue {
+			filter = "(due_at IS NULL OR due_at>=? OR completed<>?)"
+		}
+
+		if queryFields == "" {
+			queryFields = "WHERE " + filter
+		} else {
+			queryFields += " AND " + filter
+		}
+
+		queryValues = append(queryValues, time.Now().UTC(), false)
+	}
+
+	// Handle tags field. When all tags must match,
+	// only todos found once for every tag are kept.
+	if len(params.Tags) > 0 {
+		var having string
+		if params.TagsAll {
+			having = "\nGROUP BY tt.todo_id\nHAVING COUNT(*)=?"
+		}
+		filter := fmt.Sprintf(stmtSelectTodoIDsByTags, dialect.Placeholders(len(params.Tags)), having)
+
+		if queryFields == "" {
+			queryFields = "WHERE " + filter
+		} else {
+			queryFields += " AND " + filter
+		}
+
+		for _, tag := range params.Tags {
+			queryValues = append(queryValues, tag)
+		}
+		if params.TagsAll {
+			queryValues = append(queryValues, len(params.Tags))
+		}
+	}
+
+	// Build the full query.
+	query := fmt.Sprintf(stmtSelect, queryFields, db.db.Dialect().Limit(params.Offset, params.Limit))
+
+	// Create a new Todos.
+	todos := &Todos{
+		Todos: []*Todo{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(query, queryValues...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the todo rows.
+	for rows.Next() {
+		// Create a new Todo.
+		todo := &Todo{}
+
+		// Scan row values into todo struct.
+		if err := scan(rows, todo); err != nil {
+			return nil, err
+		}
+
+		// Add to todos set.
+		todos.Todos = append(todos.Todos, todo)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Load the tags of the todos.
+	if err := db.loadTags(todos.Todos); err != nil {
+		return nil, err
+	}
+
+	// Build the total count query.
+	queryCount := fmt.Sprintf(stmtSelectCount, queryFields)
+
+	// Get total count.
+	var total int
+	if err = db.db.QueryRow(queryCount, queryValues...).Scan(&total); err != nil {
+		return nil, err
+	}
+	todos.Total = total
+
+	return todos, nil
+}
+
+// GetByID retrieves a todo by its ID.
+func (db *SQL) GetByID(id int) (*Todo, error) {
+	// Create a new Todo.
+	todo := &Todo{}
+
+	// Execute the query.
+	err := scan(db.db.QueryRow(stmtSelectByID, id), todo)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrTodoNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	// Load the tags of the todo.
+	if err := db.loadTags([]*Todo{todo}); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// GetByIDAndMemberID retrieves a todo by its ID and member ID.
+func (db *SQL) GetByIDAndMemberID(id, mid int) (*Todo, error) {
+	// Create a new Todo.
+	todo := &Todo{}
+
+	// Execute the query.
+	err := scan(db.db.QueryRow(stmtSelectByIDAndMemberID, id, mid), todo)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrTodoNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	// Load the tags of the todo.
+	if err := db.loadTags([]*Todo{todo}); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// GetByParentID retrieves the subtasks of the todo with the given ID.
+func (db *SQL) GetByParentID(pid int) (*Todos, error) {
+	// Create a new Todos.
+	todos := &Todos{
+		Todos: []*Todo{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(stmtSelectByParentID, pid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the todo rows.
+	for rows.Next() {
+		// Create a new Todo.
+		todo := &Todo{}
+
+		// Scan row values into todo struct.
+		if err := scan(rows, todo); err != nil {
+			return nil, err
+		}
+
+		// Add to todos set.
+		todos.Todos = append(todos.Todos, todo)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	todos.Total = len(todos.Todos)
+
+	// Load the tags of the todos.
+	if err := db.loadTags(todos.Todos); err != nil {
+		return nil, err
+	}
+
+	return todos, nil
+}
+
+// Update updates a todo.
+func (db *SQL) Update(id int, params *UpdateParams) (*Todo, error) {
+	// Create variables to hold the query fields
+	// being updated and their new values.
+	var queryFields string
+	var queryValues []interface{}
+
+	// Handle list ID field.
+	if params.ListID != nil || params.ClearListID {
+		if queryFields == "" {
+			queryFields = "list_id=?"
+		} else {
+			queryFields += ", list_id=?"
+		}
+
+		if params.ClearListID {
+			queryValues = append(queryValues, nil)
+		} else {
+			queryValues = append(queryValues, *params.ListID)
+		}
+	}
+
+	// Handle parent ID field.
+	if params.ParentID != nil || params.ClearParentID {
+		if queryFields == "" {
+			queryFields = "parent_id=?"
+		} else {
+			queryFields += ", parent_id=?"
+		}
+
+		if params.ClearParentID {
+			queryValues = append(queryValues, nil)
+		} else {
+			queryValues = append(queryValues, *params.ParentID)
+		}
+	}
+
+	// Handle assignee ID field.
+	if params.AssigneeID != nil || params.ClearAssigneeID {
+		if queryFields == "" {
+			queryFields = "assignee_id=?"
+		} else {
+			queryFields += ", assignee_id=?"
+		}
+
+		if params.ClearAssigneeID {
+			queryValues = append(queryValues, nil)
+		} else {
+			queryValues = append(queryValues, *params.AssigneeID)
+		}
+	}
+
+	// Handle series ID field.
+	if params.SeriesID != nil {
+		if queryFields == "" {
+			queryFields = "series_id=?"
+		} else {
+			queryFields += ", series_id=?"
+		}
+
+		queryValues = append(queryValues, *params.SeriesID)
+	}
+
+	// Handle created field.
+	if params.Created != nil {
+		if queryFields == "" {
+			queryFields = "created=?"
+		} else {
+			queryFields += ", created=?"
+		}
+
+		queryValues = append(queryValues, *params.Created)
+	}
+
+	// Handle detail field.
+	if params.Detail != nil {
+		if queryFields == "" {
+			queryFields = "detail=?"
+		} else {
+			queryFields += ", detail=?"
+		}
+
+		queryValues = append(queryValues, *params.Detail)
+	}
+
+	// Handle completed field.
+	if params.Completed != nil {
+		if queryFields == "" {
+			queryFields = "completed=?"
+		} else {
+			queryFields += ", completed=?"
+		}
+
+		queryValues = append(queryValues, *params.Completed)
+	}
+
+	// Handle due at field.
+	if params.DueAt != nil || params.ClearDueAt {
+		if queryFields == "" {
+			queryFields = "due_at=?"
+		} else {
+			queryFields += ", due_at=?"
+		}
+
+		if params.ClearDueAt {
+			queryValues = append(queryValues, nil)
+		} else {
+			queryValues = append(queryValues, params.DueAt.UTC())
+		}
+	}
+
+	// Handle remind at field.
+	if params.RemindAt != nil || params.ClearRemindAt {
+		if queryFields == "" {
+			queryFields = "remind_at=?"
+		} else {
+			queryFields += ", remind_at=?"
+		}
+
+		if params.ClearRemindAt {
+			queryValues = append(queryValues, nil)
+		} else {
+			queryValues = append(queryValues, params.RemindAt.UTC())
+		}
+	}
+
+	// Update the todo if any of its fields changed.
+	if queryFields != "" {
+		// Build the full query.
+		query := fmt.Sprintf(stmtUpdate, queryFields)
+		queryValues = append(queryValues, id)
+
+		// Execute the query.
+		_, err := db.db.Exec(query, queryValues...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Handle tags field.
+	if params.Tags != nil {
+		todo, err := db.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := db.setTags(id, todo.MemberID, params.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	// Since the GetByID method is straight forward,
+	// we can use this method to retrieve the updated
+	// todo. Anything more complicated should use the
+	// original statement constants.
+	return db.GetByID(id)
+}
+
+// DeleteByIDAndMemberID deletes a todo by its ID and member ID, returning
+// the number of todos deleted.
+func (db *SQL) DeleteByIDAndMemberID(id, mid int) (int, error) {
+	// Execute the query.
+	res, err := db.db.Exec(stmtDeleteByIDAndMemberID, id, mid)
+	if err != nil {
+		return 0, err
+	}
+
+	// Get the number of rows affected.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	// Remove the tags of the deleted todo and
+	// detach its subtasks.
+	if affected > 0 {
+		if _, err := db.db.Exec(stmtDeleteTodoTags, id); err != nil {
+			return 0, err
+		}
+		if _, err := db.db.Exec(fmt.Sprintf(stmtDetachSubtasks, "?"), id); err != nil {
+			return 0, err
+		}
+	}
+
+	return int(affected), nil
+}
+
+// DeleteByMemberID deletes the set of todos belonging to the given member
+// that match the given filters, returning the number of todos deleted.
+func (db *SQL) DeleteByMemberID(mid int, params *DeleteParams) (int, error) {
+	// Get the todos matching the filters.
+	ids, err := db.GetIDsByMemberID(mid, params)
+	if err != nil {
+		return 0, err
+	}
+
+	return db.delete(ids)
+}
+
+// GetIDsByMemberID gets the IDs of the set of todos belonging to the given
+// member that match the given filters, which are the todos DeleteByMemberID
+// would delete.
+func (db *SQL) GetIDsByMemberID(mid int, params *DeleteParams) ([]int, error) {
+	// Get the query fields being filtered
+	// on and their values.
+	queryFields, queryValues := deleteFilters(mid, params)
+
+	// Build the full query.
+	query := fmt.Sprintf(stmtSelectIDsByMemberID, queryFields)
+
+	return db.ids(query, queryValues...)
+}
+
+// ids selects the IDs of a set of todos using the given statement.
+func (db *SQL) ids(stmt string, args ...interface{}) ([]int, error) {
+	// Execute the query.
+	rows, err := db.db.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the todo rows.
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// deleteFilters returns the query fields filtering the todos of the given
+// member on the given filters, along with their values.
+func deleteFilters(mid int, params *DeleteParams) (string, []interface{}) {
+	// Create variables to hold the query fields
+	// being filtered on and their values.
+	var queryFields string
+	queryValues := []interface{}{mid}
+
+	// Handle workspace ID field.
+	if params.WorkspaceID != nil {
+		queryFields += " AND workspace_id=?"
+		queryValues = append(queryValues, *params.WorkspaceID)
+	}
+
+	// Handle personal field.
+	if params.Personal {
+		queryFields += " AND workspace_id IS NULL"
+	}
+
+	// Handle IDs field.
+	if len(params.IDs) > 0 {
+		queryFields += " AND id IN (" + dialect.Placeholders(len(params.IDs)) + ")"
+
+		for _, id := range params.IDs {
+			queryValues = append(queryValues, id)
+		}
+	}
+
+	// Handle list ID field.
+	if params.ListID != nil {
+		queryFields += " AND list_id=?"
+		queryValues = append(queryValues, *params.ListID)
+	}
+
+	// Handle created field.
+	if params.Created != nil {
+		queryFields += " AND created=?"
+		queryValues = append(queryValues, *params.Created)
+	}
+
+	// Handle completed field.
+	if params.Completed != nil {
+		queryFields += " AND completed=?"
+		queryValues = append(queryValues, *params.Completed)
+	}
+
+	return queryFields, queryValues
+}
+
+// DeleteByListID deletes every todo of the list with the given ID,
+// returning the number of todos deleted.
+func (db *SQL) DeleteByListID(lid int) (int, error) {
+	// Get the todos of the list.
+	ids, err := db.GetIDsByListID(lid)
+	if err != nil {
+		return 0, err
+	}
+
+	return db.delete(ids)
+}
+
+// GetIDsByListID gets the IDs of every todo of the list with the given ID,
+// which are the todos DeleteByListID would delete.
+func (db *SQL) GetIDsByListID(lid int) ([]int, error) {
+	return db.ids(stmtSelectIDsByListID, lid)
+}
+
+// DeleteByWorkspaceID deletes every todo of the workspace with the given
+// ID, returning the number of todos deleted.
+func (db *SQL) DeleteByWorkspaceID(wid int) (int, error) {
+	// Get the todos of the workspace.
+	ids, err := db.GetIDsByWorkspaceID(wid)
+	if err != nil {
+		return 0, err
+	}
+
+	return db.delete(ids)
+}
+
+// GetIDsByWorkspaceID gets the IDs of every todo of the workspace with the
+// given ID, which are the todos DeleteByWorkspaceID would delete.
+func (db *SQL) GetIDsByWorkspaceID(wid int) ([]int, error) {
+	return db.ids(stmtSelectIDsByWorkspaceID, wid)
+}
+
+// delete deletes the todos with the given IDs, returning the number of
+// todos deleted.
+func (db *SQL) delete(ids []int) (int, error) {
+	// Check there is anything to delete.
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	// Get the IDs as query values.
+	in := dialect.Placeholders(len(ids))
+	queryValues := make([]interface{}, len(ids))
+	for i, id := range ids {
+		queryValues[i] = id
+	}
+
+	// Execute the query.
+	res, err := db.db.Exec(fmt.Sprintf(stmtDeleteByIDs, in), queryValues...)
+	if err != nil {
+		return 0, err
+	}
+
+	// Get the number of rows affected.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	// Remove the tags of the deleted todos and
+	// detach their subtasks.
+	if affected > 0 {
+		if _, err := db.db.Exec(fmt.Sprintf(stmtDeleteTodoTagsByIDs, in), queryValues...); err != nil {
+			return 0, err
+		}
+		if _, err := db.db.Exec(fmt.Sprintf(stmtDetachSubtasks, in), queryValues...); err != nil {
+			return 0, err
+		}
+	}
+
+	return int(affected), nil
+}
+
+// UnassignByMemberID removes the given member as the assignee of every
+// todo, or only of the todos of the given workspace when it is not nil.
+func (db *SQL) UnassignByMemberID(mid int, wid *int) error {
+	// Handle workspace ID field.
+	var queryFields string
+	queryValues := []interface{}{mid}
+	if wid != nil {
+		queryFields = " AND workspace_id=?"
+		queryValues = append(queryValues, *wid)
+	}
+
+	// Execute the query.
+	_, err := db.db.Exec(fmt.Sprintf(stmtUnassignByMemberID, queryFields), queryValues...)
+	return err
+}
+
+// GetIDsByAssigneeID gets the IDs of every todo assigned to the given
+// member, or only of the todos of the given workspace when it is not nil,
+// which are the todos UnassignByMemberID would unassign.
+func (db *SQL) GetIDsByAssigneeID(mid int, wid *int) ([]int, error) {
+	// Handle workspace ID field.
+	var queryFields string
+	queryValues := []interface{}{mid}
+	if wid != nil {
+		queryFields = " AND workspace_id=?"
+		queryValues = append(queryValues, *wid)
+	}
+
+	return db.ids(fmt.Sprintf(stmtSelectIDsByAssigneeID, queryFields), queryValues...)
+}
+
+// MoveToInbox removes the todos of the list with the given ID from the list,
+// returning the number of todos moved.
+func (db *SQL) MoveToInbox(lid int) (int, error) {
+	// Execute the query.
+	res, err := db.db.Exec(stmtMoveToInbox, lid)
+	if err != nil {
+		return 0, err
+	}
+
+	// Get the number of rows affected.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(affected), nil
+}
+
+// GetTagsByMemberID retrieves the tags of a given member.
+func (db *SQL) GetTagsByMemberID(mid int) (*Tags, error) {
+	// Create a new Tags.
+	tags := &Tags{
+		Tags: []*Tag{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(fmt.Sprintf(stmtSelectTags, "t.member_id=?"), mid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the tag rows.
+	for rows.Next() {
+		// Create a new Tag.
+		tag := &Tag{}
+
+		// Scan row values into tag struct.
+		if err := rows.Scan(&tag.ID, &tag.MemberID, &tag.Name, &tag.Todos); err != nil {
+			return nil, err
+		}
+
+		// Add to tags set.
+		tags.Tags = append(tags.Tags, tag)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	tags.Total = len(tags.Tags)
+
+	return tags, nil
+}
+
+// GetTagByIDAndMemberID retrieves a tag by its ID and member ID.
+func (db *SQL) GetTagByIDAndMemberID(id, mid int) (*Tag, error) {
+	return db.getTag(fmt.Sprintf(stmtSelectTags, "t.id=? AND t.member_id=?"), id, mid)
+}
+
+// UpdateTag updates a tag.
+func (db *SQL) UpdateTag(id int, params *UpdateTagParams) (*Tag, error) {
+	// Handle name field.
+	if params.Name != nil {
+		// Execute the query.
+		if _, err := db.db.Exec(stmtUpdateTag, *params.Name, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return db.getTag(fmt.Sprintf(stmtSelectTags, "t.id=?"), id)
+}
+
+// MergeTags moves the todos of the tags with the given IDs over to the tag
+// with the given ID, then deletes the merged tags.
+func (db *SQL) MergeTags(id int, ids []int) (*Tag, error) {
+	// Loop through the tags being merged.
+	for _, mergeID := range ids {
+		if mergeID == id {
+			continue
+		}
+
+		// Move the todos over to the tag.
+		if _, err := db.db.Exec(stmtMergeTodoTags, id, mergeID, id); err != nil {
+			return nil, err
+		}
+
+		// Delete the merged tag, along with the todos
+		// which already had both tags.
+		if _, err := db.db.Exec(stmtDeleteTagTodoTags, mergeID); err != nil {
+			return nil, err
+		}
+		if _, err := db.db.Exec(stmtDeleteTag, mergeID); err != nil {
+			return nil, err
+		}
+	}
+
+	return db.getTag(fmt.Sprintf(stmtSelectTags, "t.id=?"), id)
+}
+
+// DeleteTagByIDAndMemberID deletes a tag by its ID and member ID, removing
+// it from every todo.
+func (db *SQL) DeleteTagByIDAndMemberID(id, mid int) error {
+	// Make sure the tag belongs to the member.
+	if _, err := db.GetTagByIDAndMemberID(id, mid); err != nil {
+		return err
+	}
+
+	// Remove the tag from every todo.
+	if _, err := db.db.Exec(stmtDeleteTagTodoTags, id); err != nil {
+		return err
+	}
+
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteTag, id)
+	return err
+}
+
+// DeleteTagsByMemberID deletes every tag of a given member, removing them
+// from every todo.
+func (db *SQL) DeleteTagsByMemberID(mid int) error {
+	// Remove the tags from every todo.
+	if _, err := db.db.Exec(stmtDeleteMemberTodoTags, mid); err != nil {
+		return err
+	}
+
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteTagsByMemberID, mid)
+	return err
+}
+
+// GetIDsByTagIDs gets the IDs of every todo any of the tags with the given
+// IDs is set on.
+func (db *SQL) GetIDsByTagIDs(ids []int) ([]int, error) {
+	// Check there are any tags.
+	if len(ids) == 0 {
+		return []int{}, nil
+	}
+
+	// Get the IDs as query values.
+	queryValues := make([]interface{}, len(ids))
+	for i, id := range ids {
+		queryValues[i] = id
+	}
+
+	return db.ids(fmt.Sprintf(stmtSelectIDsByTagIDs, dialect.Placeholders(len(ids))), queryValues...)
+}
+
+// getTag retrieves a single tag using the given query and values.
+func (db *SQL) getTag(query string, args ...interface{}) (*Tag, error) {
+	// Create a new Tag.
+	tag := &Tag{}
+
+	// Execute the query.
+	err := db.db.QueryRow(query, args...).Scan(&tag.ID, &tag.MemberID, &tag.Name, &tag.Todos)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrTagNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// setTags replaces the tags of a todo with the tags of the given names,
+// creating any of the member's tags that do not exist yet.
+func (db *SQL) setTags(id, mid int, names []string) error {
+	// Remove the current tags of the todo.
+	if _, err := db.db.Exec(stmtDeleteTodoTags, id); err != nil {
+		return err
+	}
+
+	// Loop through the tag names.
+	for _, name := range names {
+		// Get the ID of the tag, creating the tag
+		// if it does not exist yet.
+		var tid int
+		err := db.db.QueryRow(stmtSelectTagIDByName, mid, name).Scan(&tid)
+		if err == sql.ErrNoRows {
+			tid, err = db.db.Insert(stmtInsertTag, mid, name)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Set the tag on the todo.
+		if _, err := db.db.Exec(stmtInsertTodoTag, id, tid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadTags loads the tag names of the given todos.
+func (db *SQL) loadTags(todos []*Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+
+	// Index the todos by their ID.
+	byID := make(map[int]*Todo)
+	var ids []interface{}
+	for _, todo := range todos {
+		todo.Tags = []string{}
+		byID[todo.ID] = todo
+		ids = append(ids, todo.ID)
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(fmt.Sprintf(stmtSelectTodoTags, dialect.Placeholders(len(ids))), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// Loop through the tag rows.
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return err
+		}
+
+		byID[id].Tags = append(byID[id].Tags, name)
+	}
+
+	return rows.Err()
+}
+
+// scanner defines the Scan method shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan scans a row selected using the columns constant into a todo.
+func scan(row scanner, todo *Todo) error {
+	return row.Scan(&todo.ID, &todo.MemberID, &todo.WorkspaceID, &todo.AssigneeID, &todo.ListID, &todo.ParentID, &todo.SeriesID, &todo.Created, &todo.Detail, &todo.Completed, &todo.DueAt, &todo.RemindAt)
+}
+
+// utc returns the given time in UTC, so that times stored as text, as they
+// are with SQLite, still compare correctly.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	u := t.UTC()
+	return &u
+}
@@ -1,30 +1,126 @@
 package todos
 
-import (
-	"database/sql"
-	"fmt"
-	"time"
-)
+import "time"
 
 // Database defines the todos database.
-type Database struct {
-	db *sql.DB
-}
+type Database interface {
+	// New creates a new todo.
+	New(mid int, params *NewParams) (*Todo, error)
+
+	// Get gets a set of todos.
+	Get(params *GetParams) (*Todos, error)
+
+	// GetByID retrieves a todo by its ID.
+	GetByID(id int) (*Todo, error)
+
+	// GetByIDAndMemberID retrieves a todo by its ID and member ID.
+	GetByIDAndMemberID(id, mid int) (*Todo, error)
+
+	// GetByParentID retrieves the subtasks of the todo with the given ID.
+	GetByParentID(pid int) (*Todos, error)
+
+	// Update updates a todo.
+	Update(id int, params *UpdateParams) (*Todo, error)
+
+	// DeleteByIDAndMemberID deletes a todo by its ID and member ID,
+	// returning the number of todos deleted.
+	//
+	// The subtasks of deleted todos are kept as top level todos, as they
+	// are with DeleteByMemberID.
+	DeleteByIDAndMemberID(id, mid int) (int, error)
+
+	// DeleteByMemberID deletes the set of todos belonging to the given
+	// member that match the given filters, returning the number of todos
+	// deleted.
+	DeleteByMemberID(mid int, params *DeleteParams) (int, error)
+
+	// GetIDsByMemberID gets the IDs of the set of todos belonging to the
+	// given member that match the given filters, which are the todos
+	// DeleteByMemberID would delete.
+	GetIDsByMemberID(mid int, params *DeleteParams) ([]int, error)
+
+	// DeleteByListID deletes every todo of the list with the given ID,
+	// returning the number of todos deleted.
+	DeleteByListID(lid int) (int, error)
+
+	// GetIDsByListID gets the IDs of every todo of the list with the
+	// given ID, which are the todos DeleteByListID would delete.
+	GetIDsByListID(lid int) ([]int, error)
+
+	// DeleteByWorkspaceID deletes every todo of the workspace with the
+	// given ID, returning the number of todos deleted.
+	DeleteByWorkspaceID(wid int) (int, error)
+
+	// GetIDsByWorkspaceID gets the IDs of every todo of the workspace
+	// with the given ID, which are the todos DeleteByWorkspaceID would
+	// delete.
+	GetIDsByWorkspaceID(wid int) ([]int, error)
+
+	// UnassignByMemberID removes the given member as the assignee of
+	// every todo, or only of the todos of the given workspace when it is
+	// not nil.
+	UnassignByMemberID(mid int, wid *int) error
+
+	// GetIDsByAssigneeID gets the IDs of every todo assigned to the given
+	// member, or only of the todos of the given workspace when it is not
+	// nil, which are the todos UnassignByMemberID would unassign.
+	GetIDsByAssigneeID(mid int, wid *int) ([]int, error)
 
-// New creates a new todos database.
-func New(db *sql.DB) *Database {
-	return &Database{
-		db: db,
-	}
+	// MoveToInbox removes the todos of the list with the given ID from
+	// the list, returning the number of todos moved.
+	MoveToInbox(lid int) (int, error)
+
+	// GetTagsByMemberID retrieves the tags of a given member.
+	GetTagsByMemberID(mid int) (*Tags, error)
+
+	// GetTagByIDAndMemberID retrieves a tag by its ID and member ID.
+	GetTagByIDAndMemberID(id, mid int) (*Tag, error)
+
+	// UpdateTag updates a tag.
+	UpdateTag(id int, params *UpdateTagParams) (*Tag, error)
+
+	// MergeTags moves the todos of the tags with the given IDs over to
+	// the tag with the given ID, then deletes the merged tags.
+	MergeTags(id int, ids []int) (*Tag, error)
+
+	// DeleteTagByIDAndMemberID deletes a tag by its ID and member ID,
+	// removing it from every todo.
+	DeleteTagByIDAndMemberID(id, mid int) error
+
+	// DeleteTagsByMemberID deletes every tag of a given member, removing
+	// them from every todo.
+	DeleteTagsByMemberID(mid int) error
+
+	// GetIDsByTagIDs gets the IDs of every todo any of the tags with the
+	// given IDs is set on.
+	GetIDsByTagIDs(ids []int) ([]int, error)
 }
 
 // Todo defines a todo.
+//
+// Todos without a ListID are not in any list, which is shown to members as
+// their inbox. Todos with a ParentID are subtasks of the todo with that ID.
+// Todos with a SeriesID are occurrences of a recurring todo series.
+//
+// Todos with a WorkspaceID belong to that workspace, while the MemberID is
+// the member who created them. Todos without one are in the personal space
+// of the member.
+//
+// Todos with an AssigneeID are assigned to the member with that ID.
 type Todo struct {
-	ID        int       `json:"id"`
-	MemberID  int       `json:"member_id"`
-	Created   time.Time `json:"created"`
-	Detail    string    `json:"detail"`
-	Completed bool      `json:"completed"`
+	ID          int        `json:"id"`
+	MemberID    int        `json:"member_id"`
+	WorkspaceID *int       `json:"workspace_id"`
+	AssigneeID  *int       `json:"assignee_id"`
+	ListID      *int       `json:"list_id"`
+	ParentID    *int       `json:"parent_id"`
+	SeriesID    *int       `json:"series_id"`
+	Created     time.Time  `json:"created"`
+	Detail      string     `json:"detail"`
+	Completed   bool       `json:"completed"`
+	DueAt       *time.Time `json:"due_at"`
+	RemindAt    *time.Time `json:"remind_at"`
+	Tags        []string   `json:"tags"`
 }
 
 // Todos defines a set of todos.
@@ -33,300 +129,114 @@ type Todos struct {
 	Total int     `json:"total"`
 }
 
-const (
-	// stmtInsert defines the SQL statement to
-	// insert a new todo into the database.
-	stmtInsert = `
-INSERT INTO todos (member_id, created, detail, completed)
-VALUES (?, ?, ?, ?)
-`
-
-	// stmtSelect defines the SQL statement to
-	// select a set of todos for a given member.
-	stmtSelect = `
-SELECT id, member_id, created, detail, completed
-FROM todos
-%s
-LIMIT %v, %v
-`
-
-	// stmtSelectCount defines the SQL statement to
-	// select the total number of todos found for a
-	// a given member, according to the filters.
-	stmtSelectCount = `
-SELECT COUNT(*)
-FROM todos
-%s
-`
-
-	// stmtSelectByID defines the SQL statement to
-	// select a todo by its ID.
-	stmtSelectByID = `
-SELECT id, member_id, created, detail, completed
-FROM todos
-WHERE id=?
-`
-
-	// stmtSelectByIDAndMemberID defines the SQL statement
-	// to select a todo by its ID and member ID.
-	stmtSelectByIDAndMemberID = `
-SELECT id, member_id, created, detail, completed
-FROM todos
-WHERE id=? AND member_id=?
-`
-
-	// stmtUpdate defines the SQL statement to
-	// update a todo.
-	stmtUpdate = `
-UPDATE todos
-SET %s
-WHERE id=?
-`
-)
-
 // NewParams defines the parameters for the New method.
 type NewParams struct {
-	Detail string `json:"detail"`
-}
-
-// New creates a new todo.
-func (db *Database) New(mid int, params *NewParams) (*Todo, error) {
-	// Create a new Todo.
-	todo := &Todo{
-		MemberID: mid,
-		Created:  time.Now(),
-		Detail:   params.Detail,
-	}
-
-	// Create variable to hold the result.
-	var res sql.Result
-	var err error
-
-	// Execute the query.
-	if res, err = db.db.Exec(stmtInsert, todo.MemberID, todo.Created, todo.Detail, todo.Completed); err != nil {
-		return nil, err
-	}
-
-	// Get last insert ID.
-	id, err := res.LastInsertId()
-	if err != nil {
-		return nil, err
-	}
-	todo.ID = int(id)
-
-	return todo, nil
+	WorkspaceID *int       `json:"workspace_id"`
+	AssigneeID  *int       `json:"assignee_id"`
+	ListID      *int       `json:"list_id"`
+	ParentID    *int       `json:"parent_id"`
+	SeriesID    *int       `json:"series_id"`
+	Detail      string     `json:"detail"`
+	DueAt       *time.Time `json:"due_at"`
+	RemindAt    *time.Time `json:"remind_at"`
+	Tags        []string   `json:"tags"`
 }
 
 // GetParams defines the parameters for the Get method.
+//
+// Inbox matches the todos which are not in any list.
+//
+// Todos with any of the given Tags are matched, unless TagsAll is set, in
+// which case todos must have all of them.
+//
+// Along with the todos of the member given by MemberID, the todos of other
+// members whose ID is in SharedIDs or whose list ID is in SharedListIDs are
+// matched, as those were shared with the member. So are the todos assigned
+// to the member when Assigned is set.
+//
+// Personal matches the todos which are not in any workspace.
+//
+// AssigneeID matches the todos assigned to the member with that ID, while
+// Unassigned matches the todos which are not assigned to anyone.
 type GetParams struct {
-	ID        *int       `json:"id"`
-	MemberID  *int       `json:"member_id"`
-	Created   *time.Time `json:"created"`
-	Completed *bool      `json:"completed"`
-	Offset    int        `json:"offset"`
-	Limit     int        `json:"limit"`
+	ID            *int       `json:"id"`
+	MemberID      *int       `json:"member_id"`
+	WorkspaceID   *int       `json:"workspace_id"`
+	Personal      bool       `json:"personal"`
+	SharedIDs     []int      `json:"shared_ids"`
+	SharedListIDs []int      `json:"shared_list_ids"`
+	Assigned      bool       `json:"assigned"`
+	AssigneeID    *int       `json:"assignee_id"`
+	Unassigned    bool       `json:"unassigned"`
+	ListID        *int       `json:"list_id"`
+	Inbox         bool       `json:"inbox"`
+	Created       *time.Time `json:"created"`
+	Completed     *bool      `json:"completed"`
+	DueBefore     *time.Time `json:"due_before"`
+	DueAfter      *time.Time `json:"due_after"`
+	Overdue       *bool      `json:"overdue"`
+	Tags          []string   `json:"tags"`
+	TagsAll       bool       `json:"tags_all"`
+	Offset        int        `json:"offset"`
+	Limit         int        `json:"limit"`
 }
 
-// Get gets a set of todos.
-func (db *Database) Get(params *GetParams) (*Todos, error) {
-	// Create variables to hold the query fields
-	// being filtered on and their values.
-	var queryFields string
-	var queryValues []interface{}
-
-	// Handle ID field.
-	if params.ID != nil {
-		if queryFields == "" {
-			queryFields = "WHERE id=?"
-		} else {
-			queryFields += " AND id=?"
-		}
-
-		queryValues = append(queryValues, *params.ID)
-	}
-
-	// Handle member ID field.
-	if params.MemberID != nil {
-		if queryFields == "" {
-			queryFields = "WHERE member_id=?"
-		} else {
-			queryFields += " AND member_id=?"
-		}
-
-		queryValues = append(queryValues, *params.MemberID)
-	}
-
-	// Handle created field.
-	if params.Created != nil {
-		if queryFields == "" {
-			queryFields = "WHERE created=?"
-		} else {
-			queryFields += " AND created=?"
-		}
-
-		queryValues = append(queryValues, *params.Created)
-	}
-
-	// Handle completed field.
-	if params.Completed != nil {
-		if queryFields == "" {
-			queryFields = "WHERE completed=?"
-		} else {
-			queryFields += " AND completed=?"
-		}
-
-		queryValues = append(queryValues, *params.Created)
-	}
-
-	// Build the full query.
-	query := fmt.Sprintf(stmtSelect, queryFields, params.Offset, params.Limit)
-
-	// Create a new Todos.
-	todos := &Todos{
-		Todos: []*Todo{},
-	}
-
-	// Execute the query.
-	rows, err := db.db.Query(query, queryValues...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	// Loop through the todo rows.
-	for rows.Next() {
-		// Create a new Todo.
-		todo := &Todo{}
-
-		// Scan row values into todo struct.
-		if err := rows.Scan(&todo.ID, &todo.MemberID, &todo.Created, &todo.Detail, &todo.Completed); err != nil {
-			return nil, err
-		}
-
-		// Add to todos set.
-		todos.Todos = append(todos.Todos, todo)
-	}
-	if err = rows.Err(); err != nil {
-		return nil, err
-	}
-
-	// Build the total count query.
-	queryCount := fmt.Sprintf(stmtSelectCount, queryFields)
-
-	// Get total count.
-	var total int
-	if err = db.db.QueryRow(queryCount, queryValues...).Scan(&total); err != nil {
-		return nil, err
-	}
-	todos.Total = total
-
-	return todos, nil
+// UpdateParams defines the parameters for the Update method.
+//
+// Since a nil ListID, ParentID, AssigneeID, DueAt or RemindAt means the
+// field is left as is, the ClearListID, ClearParentID, ClearAssigneeID,
+// ClearDueAt and ClearRemindAt fields are used to remove them instead.
+//
+// A nil Tags leaves the tags of the todo as is, while an empty Tags removes
+// all of them.
+type UpdateParams struct {
+	ListID          *int       `json:"list_id"`
+	ClearListID     bool       `json:"clear_list_id"`
+	ParentID        *int       `json:"parent_id"`
+	ClearParentID   bool       `json:"clear_parent_id"`
+	AssigneeID      *int       `json:"assignee_id"`
+	ClearAssigneeID bool       `json:"clear_assignee_id"`
+	SeriesID        *int       `json:"series_id"`
+	Created         *time.Time `json:"created"`
+	Detail          *string    `json:"detail"`
+	Completed       *bool      `json:"completed"`
+	DueAt           *time.Time `json:"due_at"`
+	ClearDueAt      bool       `json:"clear_due_at"`
+	RemindAt        *time.Time `json:"remind_at"`
+	ClearRemindAt   bool       `json:"clear_remind_at"`
+	Tags            []string   `json:"tags"`
 }
 
-// GetByID retrieves a todo by its ID.
-func (db *Database) GetByID(id int) (*Todo, error) {
-	// Create a new Todo.
-	todo := &Todo{}
-
-	// Execute the query.
-	err := db.db.QueryRow(stmtSelectByID, id).Scan(&todo.ID, &todo.MemberID, &todo.Created, &todo.Detail, &todo.Completed)
-	switch {
-	case err == sql.ErrNoRows:
-		return nil, ErrTodoNotFound
-	case err != nil:
-		return nil, err
-	}
-
-	return todo, nil
+// DeleteParams defines the parameters for the DeleteByMemberID and
+// GetIDsByMemberID methods.
+//
+// Personal matches the todos which are not in any workspace.
+type DeleteParams struct {
+	WorkspaceID *int       `json:"workspace_id"`
+	Personal    bool       `json:"personal"`
+	IDs         []int      `json:"ids"`
+	ListID      *int       `json:"list_id"`
+	Created     *time.Time `json:"created"`
+	Completed   *bool      `json:"completed"`
 }
 
-// GetByIDAndMemberID retrieves a todo by its ID and member ID.
-func (db *Database) GetByIDAndMemberID(id, mid int) (*Todo, error) {
-	// Create a new Todo.
-	todo := &Todo{}
-
-	// Execute the query.
-	err := db.db.QueryRow(stmtSelectByIDAndMemberID, id, mid).Scan(&todo.ID, &todo.MemberID, &todo.Created, &todo.Detail, &todo.Completed)
-	switch {
-	case err == sql.ErrNoRows:
-		return nil, ErrTodoNotFound
-	case err != nil:
-		return nil, err
-	}
-
-	return todo, nil
+// Tag defines a tag.
+//
+// Todos holds the number of todos the tag is set on.
+type Tag struct {
+	ID       int    `json:"id"`
+	MemberID int    `json:"member_id"`
+	Name     string `json:"name"`
+	Todos    int    `json:"todos"`
 }
 
-// UpdateParams defines the parameters for the Update method.
-type UpdateParams struct {
-	Created   *time.Time `json:"created"`
-	Detail    *string    `json:"detail"`
-	Completed *bool      `json:"completed"`
+// Tags defines a set of tags.
+type Tags struct {
+	Tags  []*Tag `json:"tags"`
+	Total int    `json:"total"`
 }
 
-// Update updates a todo.
-func (db *Database) Update(id int, params *UpdateParams) (*Todo, error) {
-	// Create variables to hold the query fields
-	// being updated and their new values.
-	var queryFields string
-	var queryValues []interface{}
-
-	// Handle created field.
-	if params.Created != nil {
-		if queryFields == "" {
-			queryFields = "created=?"
-		} else {
-			queryFields += ", created=?"
-		}
-
-		queryValues = append(queryValues, *params.Created)
-	}
-
-	// Handle detail field.
-	if params.Detail != nil {
-		if queryFields == "" {
-			queryFields = "detail=?"
-		} else {
-			queryFields += ", detail=?"
-		}
-
-		queryValues = append(queryValues, *params.Detail)
-	}
-
-	// Handle completed field.
-	if params.Completed != nil {
-		if queryFields == "" {
-			queryFields = "completed=?"
-		} else {
-			queryFields += ", completed=?"
-		}
-
-		// Handle turning boolean into tinyint.
-		if *params.Completed {
-			queryValues = append(queryValues, 1)
-		} else {
-			queryValues = append(queryValues, 0)
-		}
-	}
-
-	// Check if the query is empty.
-	if queryFields == "" {
-		return db.GetByID(id)
-	}
-
-	// Build the full query.
-	query := fmt.Sprintf(stmtUpdate, queryFields)
-	queryValues = append(queryValues, id)
-
-	// Execute the query.
-	_, err := db.db.Exec(query, queryValues...)
-	if err != nil {
-		return nil, err
-	}
-
-	// Since the GetByID method is straight forward,
-	// we can use this method to retrieve the updated
-	// todo. Anything more complicated should use the
-	// original statement constants.
-	return db.GetByID(id)
+// UpdateTagParams defines the parameters for the UpdateTag method.
+type UpdateTagParams struct {
+	Name *string `json:"name"`
 }
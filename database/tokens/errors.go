@@ -0,0 +1,11 @@
+package tokens
+
+import "errors"
+
+var (
+	// ErrTokenNotFound is returned when a refresh token could not be found.
+	ErrTokenNotFound = errors.New("Refresh token could not be found")
+
+	// ErrTokenUsed is returned when a refresh token has already been used.
+	ErrTokenUsed = errors.New("Refresh token has already been used")
+)
@@ -0,0 +1,168 @@
+package tokens
+
+import (
+	"sync"
+	"time"
+)
+
+// Memory defines the refresh tokens database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits.
+type Memory struct {
+	mu     sync.RWMutex
+	lastID int
+	tokens map[int]*Token
+}
+
+// NewMemory creates a new in-memory refresh tokens database.
+func NewMemory() *Memory {
+	return &Memory{
+		tokens: make(map[int]*Token),
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	tokens := make(map[int]*Token, len(m.tokens))
+	for id, v := range m.tokens {
+		c := *v
+		tokens[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.tokens = tokens
+	}
+}
+
+// New creates a new refresh token.
+func (m *Memory) New(mid int, params *NewParams) (*Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Token.
+	m.lastID++
+	token := &Token{
+		ID:        m.lastID,
+		MemberID:  mid,
+		Family:    params.Family,
+		Hash:      params.Hash,
+		Created:   time.Now(),
+		ExpiresAt: params.ExpiresAt,
+	}
+
+	// Store a copy of the token.
+	stored := *token
+	m.tokens[token.ID] = &stored
+
+	return token, nil
+}
+
+// GetByHash retrieves a refresh token by its hash.
+func (m *Memory) GetByHash(hash string) (*Token, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, token := range m.tokens {
+		if token.Hash == hash {
+			// Return a copy of the token.
+			found := *token
+			return &found, nil
+		}
+	}
+
+	return nil, ErrTokenNotFound
+}
+
+// MarkUsed marks a refresh token as used, returning ErrTokenUsed if it
+// already was.
+func (m *Memory) MarkUsed(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.tokens[id]
+	if !ok {
+		return ErrTokenNotFound
+	}
+	if token.UsedAt != nil {
+		return ErrTokenUsed
+	}
+
+	now := time.Now()
+	token.UsedAt = &now
+
+	return nil
+}
+
+// IsFamilyActive returns whether a family has a refresh token which is
+// neither revoked nor expired.
+func (m *Memory) IsFamilyActive(family string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	for _, token := range m.tokens {
+		if token.Family == family && token.RevokedAt == nil && token.ExpiresAt.After(now) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// RevokeByFamily revokes every refresh token of a family.
+func (m *Memory) RevokeByFamily(family string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range m.tokens {
+		if token.Family == family && token.RevokedAt == nil {
+			revokedAt := now
+			token.RevokedAt = &revokedAt
+		}
+	}
+
+	return nil
+}
+
+// RevokeByMemberID revokes every refresh token of a given member.
+func (m *Memory) RevokeByMemberID(mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range m.tokens {
+		if token.MemberID == mid && token.RevokedAt == nil {
+			revokedAt := now
+			token.RevokedAt = &revokedAt
+		}
+	}
+
+	return nil
+}
+
+// DeleteByMemberID deletes every refresh token of a given member.
+func (m *Memory) DeleteByMemberID(mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, token := range m.tokens {
+		if token.MemberID == mid {
+			delete(m.tokens, id)
+		}
+	}
+
+	return nil
+}
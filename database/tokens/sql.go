@@ -0,0 +1,172 @@
+package tokens
+
+import (
+	"database/sql"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the refresh tokens database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL refresh tokens database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// stmtInsert defines the SQL statement to
+	// insert a new refresh token into the database.
+	stmtInsert = `
+INSERT INTO refresh_tokens (member_id, family, hash, created, expires_at)
+VALUES (?, ?, ?, ?, ?)
+`
+
+	// stmtSelectByHash defines the SQL statement to
+	// select a refresh token by its hash.
+	stmtSelectByHash = `
+SELECT id, member_id, family, hash, created, expires_at, used_at, revoked_at
+FROM refresh_tokens
+WHERE hash=?
+`
+
+	// stmtMarkUsed defines the SQL statement to mark
+	// a refresh token as used, unless it already is.
+	stmtMarkUsed = `
+UPDATE refresh_tokens
+SET used_at=?
+WHERE id=? AND used_at IS NULL
+`
+
+	// stmtSelectActiveCountByFamily defines the SQL
+	// statement to select the number of refresh tokens
+	// of a family which are neither revoked nor expired.
+	stmtSelectActiveCountByFamily = `
+SELECT COUNT(*)
+FROM refresh_tokens
+WHERE family=? AND revoked_at IS NULL AND expires_at>?
+`
+
+	// stmtRevokeByFamily defines the SQL statement to
+	// revoke every refresh token of a family.
+	stmtRevokeByFamily = `
+UPDATE refresh_tokens
+SET revoked_at=?
+WHERE family=? AND revoked_at IS NULL
+`
+
+	// stmtRevokeByMemberID defines the SQL statement to
+	// revoke every refresh token of a given member.
+	stmtRevokeByMemberID = `
+UPDATE refresh_tokens
+SET revoked_at=?
+WHERE member_id=? AND revoked_at IS NULL
+`
+
+	// stmtDeleteByMemberID defines the SQL statement
+	// to delete every refresh token of a given member.
+	stmtDeleteByMemberID = `
+DELETE FROM refresh_tokens
+WHERE member_id=?
+`
+)
+
+// New creates a new refresh token.
+func (db *SQL) New(mid int, params *NewParams) (*Token, error) {
+	// Create a new Token.
+	token := &Token{
+		MemberID:  mid,
+		Family:    params.Family,
+		Hash:      params.Hash,
+		Created:   time.Now(),
+		ExpiresAt: params.ExpiresAt.UTC(),
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, token.MemberID, token.Family, token.Hash, token.Created, token.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	token.ID = id
+
+	return token, nil
+}
+
+// GetByHash retrieves a refresh token by its hash.
+func (db *SQL) GetByHash(hash string) (*Token, error) {
+	// Create a new Token.
+	token := &Token{}
+
+	// Execute the query.
+	err := db.db.QueryRow(stmtSelectByHash, hash).Scan(&token.ID, &token.MemberID, &token.Family, &token.Hash, &token.Created, &token.ExpiresAt, &token.UsedAt, &token.RevokedAt)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrTokenNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// MarkUsed marks a refresh token as used, returning ErrTokenUsed if it
+// already was.
+//
+// The check and the update happen in a single statement, so when the same
+// token is used twice at once only one of them succeeds.
+func (db *SQL) MarkUsed(id int) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtMarkUsed, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+
+	// Check if the token was marked.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrTokenUsed
+	}
+
+	return nil
+}
+
+// IsFamilyActive returns whether a family has a refresh token which is
+// neither revoked nor expired.
+func (db *SQL) IsFamilyActive(family string) (bool, error) {
+	// Execute the query.
+	var count int
+	if err := db.db.QueryRow(stmtSelectActiveCountByFamily, family, time.Now().UTC()).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// RevokeByFamily revokes every refresh token of a family.
+func (db *SQL) RevokeByFamily(family string) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtRevokeByFamily, time.Now().UTC(), family)
+	return err
+}
+
+// RevokeByMemberID revokes every refresh token of a given member.
+func (db *SQL) RevokeByMemberID(mid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtRevokeByMemberID, time.Now().UTC(), mid)
+	return err
+}
+
+// DeleteByMemberID deletes every refresh token of a given member.
+func (db *SQL) DeleteByMemberID(mid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteByMemberID, mid)
+	return err
+}
@@ -0,0 +1,52 @@
+package tokens
+
+import "time"
+
+// Database defines the refresh tokens database.
+type Database interface {
+	// New creates a new refresh token.
+	New(mid int, params *NewParams) (*Token, error)
+
+	// GetByHash retrieves a refresh token by its hash.
+	GetByHash(hash string) (*Token, error)
+
+	// MarkUsed marks a refresh token as used, returning ErrTokenUsed if
+	// it already was.
+	MarkUsed(id int) error
+
+	// IsFamilyActive returns whether a family has a refresh token which is
+	// neither revoked nor expired.
+	IsFamilyActive(family string) (bool, error)
+
+	// RevokeByFamily revokes every refresh token of a family.
+	RevokeByFamily(family string) error
+
+	// RevokeByMemberID revokes every refresh token of a given member.
+	RevokeByMemberID(mid int) error
+
+	// DeleteByMemberID deletes every refresh token of a given member.
+	DeleteByMemberID(mid int) error
+}
+
+// Token defines a refresh token.
+//
+// Only the hash of the token itself is stored. Every refresh token belongs
+// to a family, which starts when a member logs in and is passed on to each
+// token the refresh token is rotated into.
+type Token struct {
+	ID        int        `json:"id"`
+	MemberID  int        `json:"member_id"`
+	Family    string     `json:"family"`
+	Hash      string     `json:"-"`
+	Created   time.Time  `json:"created"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams struct {
+	Family    string    `json:"family"`
+	Hash      string    `json:"hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,13 @@
+package workspaces
+
+import "errors"
+
+var (
+	// ErrWorkspaceNotFound is returned when a workspace could not be
+	// found.
+	ErrWorkspaceNotFound = errors.New("Workspace could not be found")
+
+	// ErrMemberNotFound is returned when a member of a workspace could
+	// not be found.
+	ErrMemberNotFound = errors.New("Workspace member could not be found")
+)
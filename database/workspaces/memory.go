@@ -0,0 +1,286 @@
+package workspaces
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory defines the workspaces database backed by memory.
+//
+// It is safe for concurrent use and is meant for tests and local demos,
+// all data is lost once the process exits.
+type Memory struct {
+	mu           sync.RWMutex
+	lastID       int
+	lastMemberID int
+	workspaces   map[int]*Workspace
+	members      map[int]*Member
+}
+
+// NewMemory creates a new in-memory workspaces database.
+func NewMemory() *Memory {
+	return &Memory{
+		workspaces: make(map[int]*Workspace),
+		members:    make(map[int]*Member),
+	}
+}
+
+// Snapshot returns a function restoring the database to the state it is in
+// now, which is used to roll back transactions.
+func (m *Memory) Snapshot() func() {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Copy the state of the database.
+	lastID := m.lastID
+	lastMemberID := m.lastMemberID
+	workspaces := make(map[int]*Workspace, len(m.workspaces))
+	for id, v := range m.workspaces {
+		c := *v
+		workspaces[id] = &c
+	}
+	members := make(map[int]*Member, len(m.members))
+	for id, v := range m.members {
+		c := *v
+		members[id] = &c
+	}
+
+	// Restore the copied state.
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.lastID = lastID
+		m.lastMemberID = lastMemberID
+		m.workspaces = workspaces
+		m.members = members
+	}
+}
+
+// New creates a new workspace.
+func (m *Memory) New(params *NewParams) (*Workspace, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Workspace.
+	m.lastID++
+	workspace := &Workspace{
+		ID:      m.lastID,
+		Name:    params.Name,
+		Created: time.Now(),
+	}
+
+	// Store a copy of the workspace.
+	stored := *workspace
+	m.workspaces[workspace.ID] = &stored
+
+	return workspace, nil
+}
+
+// GetByID retrieves a workspace by its ID.
+func (m *Memory) GetByID(id int) (*Workspace, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, ok := m.workspaces[id]
+	if !ok {
+		return nil, ErrWorkspaceNotFound
+	}
+
+	// Return a copy of the workspace.
+	found := *workspace
+	return &found, nil
+}
+
+// GetByMemberID retrieves the workspaces a given member belongs to.
+func (m *Memory) GetByMemberID(mid int) (*Workspaces, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Create a new Workspaces.
+	workspaces := &Workspaces{
+		Workspaces: []*Workspace{},
+	}
+
+	// Add copies of the workspaces of the member.
+	for _, member := range m.members {
+		if member.MemberID != mid {
+			continue
+		}
+
+		if workspace, ok := m.workspaces[member.WorkspaceID]; ok {
+			found := *workspace
+			workspaces.Workspaces = append(workspaces.Workspaces, &found)
+		}
+	}
+	workspaces.Total = len(workspaces.Workspaces)
+
+	// Sort the workspaces by ID.
+	sort.Slice(workspaces.Workspaces, func(i, j int) bool {
+		return workspaces.Workspaces[i].ID < workspaces.Workspaces[j].ID
+	})
+
+	return workspaces, nil
+}
+
+// Update updates a workspace.
+func (m *Memory) Update(id int, params *UpdateParams) (*Workspace, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace, ok := m.workspaces[id]
+	if !ok {
+		return nil, ErrWorkspaceNotFound
+	}
+
+	// Handle name field.
+	if params.Name != nil {
+		workspace.Name = *params.Name
+	}
+
+	// Return a copy of the workspace.
+	updated := *workspace
+	return &updated, nil
+}
+
+// Delete deletes a workspace along with its members.
+func (m *Memory) Delete(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.workspaces[id]; !ok {
+		return ErrWorkspaceNotFound
+	}
+	delete(m.workspaces, id)
+
+	// Remove the members of the workspace.
+	for mid, member := range m.members {
+		if member.WorkspaceID == id {
+			delete(m.members, mid)
+		}
+	}
+
+	return nil
+}
+
+// NewMember adds a member to a workspace with the given role.
+func (m *Memory) NewMember(wid, mid int, role string) (*Member, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Create a new Member.
+	m.lastMemberID++
+	member := &Member{
+		ID:          m.lastMemberID,
+		WorkspaceID: wid,
+		MemberID:    mid,
+		Role:        role,
+		Created:     time.Now(),
+	}
+
+	// Store a copy of the member.
+	stored := *member
+	m.members[member.ID] = &stored
+
+	return member, nil
+}
+
+// GetMembers retrieves the members of a given workspace.
+func (m *Memory) GetMembers(wid int) (*Members, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Create a new Members.
+	members := &Members{
+		Members: []*Member{},
+	}
+
+	// Add copies of the members of the workspace.
+	for _, member := range m.members {
+		if member.WorkspaceID == wid {
+			found := *member
+			members.Members = append(members.Members, &found)
+		}
+	}
+	members.Total = len(members.Members)
+
+	// Sort the members by ID.
+	sort.Slice(members.Members, func(i, j int) bool {
+		return members.Members[i].ID < members.Members[j].ID
+	})
+
+	return members, nil
+}
+
+// GetMember retrieves a member of a given workspace.
+func (m *Memory) GetMember(wid, mid int) (*Member, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	member := m.member(wid, mid)
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+
+	// Return a copy of the member.
+	found := *member
+	return &found, nil
+}
+
+// UpdateMember changes the role of a member of a given workspace.
+func (m *Memory) UpdateMember(wid, mid int, role string) (*Member, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	member := m.member(wid, mid)
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+	member.Role = role
+
+	// Return a copy of the member.
+	updated := *member
+	return &updated, nil
+}
+
+// DeleteMember removes a member from a given workspace.
+func (m *Memory) DeleteMember(wid, mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	member := m.member(wid, mid)
+	if member == nil {
+		return ErrMemberNotFound
+	}
+	delete(m.members, member.ID)
+
+	return nil
+}
+
+// DeleteMembersByMemberID removes a given member from every workspace they
+// belong to.
+func (m *Memory) DeleteMembersByMemberID(mid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, member := range m.members {
+		if member.MemberID == mid {
+			delete(m.members, id)
+		}
+	}
+
+	return nil
+}
+
+// member returns the stored member of the given workspace, or nil if the
+// member does not belong to it. The lock must be held by the caller.
+func (m *Memory) member(wid, mid int) *Member {
+	for _, member := range m.members {
+		if member.WorkspaceID == wid && member.MemberID == mid {
+			return member
+		}
+	}
+
+	return nil
+}
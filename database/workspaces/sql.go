@@ -0,0 +1,380 @@
+package workspaces
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gotodo/database/dialect"
+)
+
+// SQL defines the workspaces database backed by an SQL database.
+type SQL struct {
+	db *dialect.DB
+}
+
+// NewSQL creates a new SQL workspaces database.
+func NewSQL(db *dialect.DB) *SQL {
+	return &SQL{
+		db: db,
+	}
+}
+
+const (
+	// columns defines the columns selected for
+	// a workspace, in the order they are scanned.
+	columns = `id, name, created`
+
+	// memberColumns defines the columns selected for
+	// a workspace member, in the order they are scanned.
+	memberColumns = `id, workspace_id, member_id, role, created`
+
+	// stmtInsert defines the SQL statement to
+	// insert a new workspace into the database.
+	stmtInsert = `
+INSERT INTO workspaces (name, created)
+VALUES (?, ?)
+`
+
+	// stmtSelectByID defines the SQL statement to
+	// select a workspace by its ID.
+	stmtSelectByID = `
+SELECT ` + columns + `
+FROM workspaces
+WHERE id=?
+`
+
+	// stmtSelectByMemberID defines the SQL statement
+	// to select the workspaces of a given member.
+	stmtSelectByMemberID = `
+SELECT ` + columns + `
+FROM workspaces
+WHERE id IN (SELECT workspace_id FROM workspace_members WHERE member_id=?)
+ORDER BY id
+`
+
+	// stmtUpdate defines the SQL statement to
+	// update a workspace.
+	stmtUpdate = `
+UPDATE workspaces
+SET %s
+WHERE id=?
+`
+
+	// stmtDelete defines the SQL statement to
+	// delete a workspace.
+	stmtDelete = `
+DELETE FROM workspaces
+WHERE id=?
+`
+
+	// stmtInsertMember defines the SQL statement to
+	// add a member to a workspace.
+	stmtInsertMember = `
+INSERT INTO workspace_members (workspace_id, member_id, role, created)
+VALUES (?, ?, ?, ?)
+`
+
+	// stmtSelectMembers defines the SQL statement to
+	// select the members of a workspace.
+	stmtSelectMembers = `
+SELECT ` + memberColumns + `
+FROM workspace_members
+WHERE workspace_id=?
+ORDER BY id
+`
+
+	// stmtSelectMember defines the SQL statement to
+	// select a member of a workspace.
+	stmtSelectMember = `
+SELECT ` + memberColumns + `
+FROM workspace_members
+WHERE workspace_id=? AND member_id=?
+`
+
+	// stmtUpdateMember defines the SQL statement to
+	// change the role of a member of a workspace.
+	stmtUpdateMember = `
+UPDATE workspace_members
+SET role=?
+WHERE workspace_id=? AND member_id=?
+`
+
+	// stmtDeleteMember defines the SQL statement to
+	// remove a member from a workspace.
+	stmtDeleteMember = `
+DELETE FROM workspace_members
+WHERE workspace_id=? AND member_id=?
+`
+
+	// stmtDeleteMembers defines the SQL statement to
+	// remove every member from a workspace.
+	stmtDeleteMembers = `
+DELETE FROM workspace_members
+WHERE workspace_id=?
+`
+
+	// stmtDeleteMembersByMemberID defines the SQL
+	// statement to remove a member from every
+	// workspace.
+	stmtDeleteMembersByMemberID = `
+DELETE FROM workspace_members
+WHERE member_id=?
+`
+)
+
+// New creates a new workspace.
+func (db *SQL) New(params *NewParams) (*Workspace, error) {
+	// Create a new Workspace.
+	workspace := &Workspace{
+		Name:    params.Name,
+		Created: time.Now(),
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsert, workspace.Name, workspace.Created)
+	if err != nil {
+		return nil, err
+	}
+	workspace.ID = id
+
+	return workspace, nil
+}
+
+// GetByID retrieves a workspace by its ID.
+func (db *SQL) GetByID(id int) (*Workspace, error) {
+	// Create a new Workspace.
+	workspace := &Workspace{}
+
+	// Execute the query.
+	err := scan(db.db.QueryRow(stmtSelectByID, id), workspace)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrWorkspaceNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return workspace, nil
+}
+
+// GetByMemberID retrieves the workspaces a given member belongs to.
+func (db *SQL) GetByMemberID(mid int) (*Workspaces, error) {
+	// Create a new Workspaces.
+	workspaces := &Workspaces{
+		Workspaces: []*Workspace{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(stmtSelectByMemberID, mid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the workspace rows.
+	for rows.Next() {
+		// Create a new Workspace.
+		workspace := &Workspace{}
+
+		// Scan row values into workspace struct.
+		if err := scan(rows, workspace); err != nil {
+			return nil, err
+		}
+
+		// Add to workspaces set.
+		workspaces.Workspaces = append(workspaces.Workspaces, workspace)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	workspaces.Total = len(workspaces.Workspaces)
+
+	return workspaces, nil
+}
+
+// Update updates a workspace.
+func (db *SQL) Update(id int, params *UpdateParams) (*Workspace, error) {
+	// Create variables to hold the query fields
+	// being updated and their new values.
+	var queryFields string
+	var queryValues []interface{}
+
+	// Handle name field.
+	if params.Name != nil {
+		if queryFields == "" {
+			queryFields = "name=?"
+		} else {
+			queryFields += ", name=?"
+		}
+
+		queryValues = append(queryValues, *params.Name)
+	}
+
+	// Check if the query is empty.
+	if queryFields == "" {
+		return db.GetByID(id)
+	}
+
+	// Build the full query.
+	query := fmt.Sprintf(stmtUpdate, queryFields)
+	queryValues = append(queryValues, id)
+
+	// Execute the query.
+	_, err := db.db.Exec(query, queryValues...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetByID(id)
+}
+
+// Delete deletes a workspace along with its members.
+func (db *SQL) Delete(id int) error {
+	// Remove the members of the workspace.
+	if _, err := db.db.Exec(stmtDeleteMembers, id); err != nil {
+		return err
+	}
+
+	// Execute the query.
+	res, err := db.db.Exec(stmtDelete, id)
+	if err != nil {
+		return err
+	}
+
+	// Check if a workspace was deleted.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrWorkspaceNotFound
+	}
+
+	return nil
+}
+
+// NewMember adds a member to a workspace with the given role.
+func (db *SQL) NewMember(wid, mid int, role string) (*Member, error) {
+	// Create a new Member.
+	member := &Member{
+		WorkspaceID: wid,
+		MemberID:    mid,
+		Role:        role,
+		Created:     time.Now(),
+	}
+
+	// Execute the query.
+	id, err := db.db.Insert(stmtInsertMember, member.WorkspaceID, member.MemberID, member.Role, member.Created)
+	if err != nil {
+		return nil, err
+	}
+	member.ID = id
+
+	return member, nil
+}
+
+// GetMembers retrieves the members of a given workspace.
+func (db *SQL) GetMembers(wid int) (*Members, error) {
+	// Create a new Members.
+	members := &Members{
+		Members: []*Member{},
+	}
+
+	// Execute the query.
+	rows, err := db.db.Query(stmtSelectMembers, wid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Loop through the member rows.
+	for rows.Next() {
+		// Create a new Member.
+		member := &Member{}
+
+		// Scan row values into member struct.
+		if err := scanMember(rows, member); err != nil {
+			return nil, err
+		}
+
+		// Add to members set.
+		members.Members = append(members.Members, member)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	members.Total = len(members.Members)
+
+	return members, nil
+}
+
+// GetMember retrieves a member of a given workspace.
+func (db *SQL) GetMember(wid, mid int) (*Member, error) {
+	// Create a new Member.
+	member := &Member{}
+
+	// Execute the query.
+	err := scanMember(db.db.QueryRow(stmtSelectMember, wid, mid), member)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, ErrMemberNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// UpdateMember changes the role of a member of a given workspace.
+func (db *SQL) UpdateMember(wid, mid int, role string) (*Member, error) {
+	// Execute the query.
+	if _, err := db.db.Exec(stmtUpdateMember, role, wid, mid); err != nil {
+		return nil, err
+	}
+
+	return db.GetMember(wid, mid)
+}
+
+// DeleteMember removes a member from a given workspace.
+func (db *SQL) DeleteMember(wid, mid int) error {
+	// Execute the query.
+	res, err := db.db.Exec(stmtDeleteMember, wid, mid)
+	if err != nil {
+		return err
+	}
+
+	// Check if a member was removed.
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// DeleteMembersByMemberID removes a given member from every workspace they
+// belong to.
+func (db *SQL) DeleteMembersByMemberID(mid int) error {
+	// Execute the query.
+	_, err := db.db.Exec(stmtDeleteMembersByMemberID, mid)
+	return err
+}
+
+// scanner defines the Scan method shared by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scan scans a workspace row into the given workspace.
+func scan(row scanner, workspace *Workspace) error {
+	return row.Scan(&workspace.ID, &workspace.Name, &workspace.Created)
+}
+
+// scanMember scans a workspace member row into the given member.
+func scanMember(row scanner, member *Member) error {
+	return row.Scan(&member.ID, &member.WorkspaceID, &member.MemberID, &member.Role, &member.Created)
+}
@@ -0,0 +1,95 @@
+package workspaces
+
+import "time"
+
+const (
+	// RoleOwner is the role of the members who own a workspace. Owners
+	// can do anything within the workspace, including deleting it.
+	RoleOwner = "owner"
+
+	// RoleAdmin is the role of the members who manage the members and
+	// invitations of a workspace.
+	RoleAdmin = "admin"
+
+	// RoleMember is the role of the members who work on the lists and
+	// todos of a workspace.
+	RoleMember = "member"
+)
+
+// Database defines the workspaces database.
+//
+// Workspaces own lists and todos, which every member of the workspace can
+// work on. The members of each workspace are kept along with their role.
+type Database interface {
+	// New creates a new workspace.
+	New(params *NewParams) (*Workspace, error)
+
+	// GetByID retrieves a workspace by its ID.
+	GetByID(id int) (*Workspace, error)
+
+	// GetByMemberID retrieves the workspaces a given member belongs to.
+	GetByMemberID(mid int) (*Workspaces, error)
+
+	// Update updates a workspace.
+	Update(id int, params *UpdateParams) (*Workspace, error)
+
+	// Delete deletes a workspace along with its members.
+	Delete(id int) error
+
+	// NewMember adds a member to a workspace with the given role.
+	NewMember(wid, mid int, role string) (*Member, error)
+
+	// GetMembers retrieves the members of a given workspace.
+	GetMembers(wid int) (*Members, error)
+
+	// GetMember retrieves a member of a given workspace.
+	GetMember(wid, mid int) (*Member, error)
+
+	// UpdateMember changes the role of a member of a given workspace.
+	UpdateMember(wid, mid int, role string) (*Member, error)
+
+	// DeleteMember removes a member from a given workspace.
+	DeleteMember(wid, mid int) error
+
+	// DeleteMembersByMemberID removes a given member from every workspace
+	// they belong to.
+	DeleteMembersByMemberID(mid int) error
+}
+
+// Workspace defines a workspace.
+type Workspace struct {
+	ID      int       `json:"id"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+}
+
+// Workspaces defines a set of workspaces.
+type Workspaces struct {
+	Workspaces []*Workspace `json:"workspaces"`
+	Total      int          `json:"total"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams struct {
+	Name string `json:"name"`
+}
+
+// UpdateParams defines the parameters for the Update method.
+type UpdateParams struct {
+	Name *string `json:"name"`
+}
+
+// Member defines a member of a workspace.
+type Member struct {
+	ID          int       `json:"id"`
+	WorkspaceID int       `json:"workspace_id"`
+	MemberID    int       `json:"member_id"`
+	Role        string    `json:"role"`
+	Created     time.Time `json:"created"`
+}
+
+// Members defines a set of members of a workspace.
+type Members struct {
+	Members []*Member `json:"members"`
+	Total   int       `json:"total"`
+}
@@ -0,0 +1,9 @@
+package mailer
+
+import "errors"
+
+var (
+	// ErrHeaderInvalid is returned when the recipient or subject of a
+	// message contains line breaks.
+	ErrHeaderInvalid = errors.New("Email headers cannot contain line breaks")
+)
@@ -0,0 +1,61 @@
+// Package mailer sends the emails of the API, such as password resets.
+//
+// The Mailer interface is implemented by SMTP, which delivers emails through
+// an SMTP server, and Writer, which writes them out to a file or log, for
+// development and tests.
+package mailer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DriverSMTP is the name of the SMTP mailer driver.
+	DriverSMTP = "smtp"
+
+	// DriverFile is the name of the mailer driver writing emails to a
+	// file, or to standard output when no file is given.
+	DriverFile = "file"
+)
+
+// Mailer defines a mailer.
+type Mailer interface {
+	// Send sends an email.
+	Send(msg *Message) error
+}
+
+// Message defines an email message.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// format returns the given message as an RFC 5322 email with a plain text
+// body, sent from the given address.
+func format(from string, msg *Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.Replace(msg.Body, "\n", "\r\n", -1))
+	b.WriteString("\r\n")
+
+	return []byte(b.String())
+}
+
+// checkHeader returns an error if the given header value contains line
+// breaks, which would allow injecting headers into the email.
+func checkHeader(value string) error {
+	if strings.ContainsAny(value, "\r\n") {
+		return ErrHeaderInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package mailer
+
+import (
+	"net"
+	"net/mail"
+	"net/smtp"
+)
+
+// SMTP defines the mailer delivering emails through an SMTP server.
+type SMTP struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTP creates a new SMTP mailer sending emails from the given address.
+//
+// When a username is given, the mailer authenticates using PLAIN auth,
+// which the net/smtp package only allows over TLS or to localhost.
+func NewSMTP(host, port, username, password, from string) *SMTP {
+	m := &SMTP{
+		addr: net.JoinHostPort(host, port),
+		from: from,
+	}
+
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return m
+}
+
+// Send sends an email.
+func (m *SMTP) Send(msg *Message) error {
+	// Check the headers.
+	if err := checkHeader(msg.To); err != nil {
+		return err
+	}
+	if err := checkHeader(msg.Subject); err != nil {
+		return err
+	}
+
+	// Get the bare address of the sender, which
+	// may include a name, for the SMTP envelope.
+	from, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.addr, m.auth, from.Address, []string{msg.To}, format(m.from, msg))
+}
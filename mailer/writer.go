@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"io"
+	"sync"
+)
+
+// Writer defines the mailer writing emails to an io.Writer instead of
+// sending them, such as a file or a log.
+//
+// It is safe for concurrent use and is meant for tests and development.
+type Writer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+// NewWriter creates a new Writer mailer writing emails from the given
+// address to w.
+func NewWriter(w io.Writer, from string) *Writer {
+	return &Writer{
+		w:    w,
+		from: from,
+	}
+}
+
+// Send writes an email, followed by an empty line.
+func (m *Writer) Send(msg *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check the headers.
+	if err := checkHeader(msg.To); err != nil {
+		return err
+	}
+	if err := checkHeader(msg.Subject); err != nil {
+		return err
+	}
+
+	if _, err := m.w.Write(format(m.from, msg)); err != nil {
+		return err
+	}
+	_, err := m.w.Write([]byte("\r\n"))
+	return err
+}
@@ -0,0 +1,41 @@
+package attachments
+
+import (
+	"gotodo/blobstore"
+	"gotodo/database"
+	dbattachments "gotodo/database/attachments"
+)
+
+// DeleteByTodoIDs deletes the attachments on the todos with the given IDs,
+// along with their bytes.
+//
+// It is called once the todos were deleted, outside of the transaction
+// deleting them, as the bytes of the files cannot be rolled back.
+func DeleteByTodoIDs(db *database.Database, blobs blobstore.BlobStore, tids []int) error {
+	for _, tid := range tids {
+		// Try to pull the attachments from the database.
+		dbas, err := db.Attachments.GetByTodoID(tid)
+		if err != nil {
+			return err
+		}
+
+		// Delete each attachment.
+		for _, dba := range dbas.Attachments {
+			if err := Delete(db, blobs, dba); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes the bytes of an attachment, then the attachment itself, so
+// the bytes are never left behind without an attachment pointing to them.
+func Delete(db *database.Database, blobs blobstore.BlobStore, dba *dbattachments.Attachment) error {
+	if err := blobs.Delete(dba.Key); err != nil {
+		return err
+	}
+
+	return db.Attachments.Delete(dba.ID)
+}
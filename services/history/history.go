@@ -0,0 +1,197 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"gotodo/database"
+	dbevents "gotodo/database/events"
+	dbtodos "gotodo/database/todos"
+)
+
+// Record records an event in the history of a todo, which was changed by the
+// given member from before to after. Before is nil for created todos, and
+// after is nil for deleted ones.
+//
+// Updates which did not change any field are not recorded.
+func Record(db *database.Database, mid int, action string, before, after *dbtodos.Todo) error {
+	// Get the ID of the todo.
+	tid := 0
+	if before != nil {
+		tid = before.ID
+	} else if after != nil {
+		tid = after.ID
+	}
+
+	// Get the fields which changed.
+	changes, err := diff(before, after)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 && action == dbevents.ActionUpdated {
+		return nil
+	}
+
+	// Create this event in the database.
+	_, err = db.Events.New(tid, mid, &dbevents.NewParams{
+		Action:  action,
+		Changes: changes,
+	})
+	return err
+}
+
+// Get retrieves the todos with the given IDs before they are changed, so
+// their changes can be recorded with RecordUpdated or RecordDeleted.
+func Get(db *database.Database, ids []int) ([]*dbtodos.Todo, error) {
+	todos := []*dbtodos.Todo{}
+	for _, id := range ids {
+		dbt, err := db.Todos.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		todos = append(todos, dbt)
+	}
+
+	return todos, nil
+}
+
+// GetSubtasks retrieves the subtasks of the todos with the given IDs which
+// are not among them, which are the subtasks turned into top level todos
+// when those todos are deleted.
+func GetSubtasks(db *database.Database, ids []int) ([]*dbtodos.Todo, error) {
+	// Get the todos being deleted.
+	deleted := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+
+	// Get the subtasks of each todo.
+	todos := []*dbtodos.Todo{}
+	for _, id := range ids {
+		dbts, err := db.Todos.GetByParentID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range dbts.Todos {
+			if !deleted[t.ID] {
+				todos = append(todos, t)
+			}
+		}
+	}
+
+	return todos, nil
+}
+
+// RecordUpdated records the update of each of the given todos, as retrieved
+// before they were changed, by the given member.
+func RecordUpdated(db *database.Database, mid int, todos []*dbtodos.Todo) error {
+	for _, before := range todos {
+		// Try to pull the todo as it is now.
+		after, err := db.Todos.GetByID(before.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := Record(db, mid, dbevents.ActionUpdated, before, after); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RecordDeleted records the deletion of each of the given todos by the given
+// member.
+func RecordDeleted(db *database.Database, mid int, todos []*dbtodos.Todo) error {
+	for _, before := range todos {
+		if err := Record(db, mid, dbevents.ActionDeleted, before, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// diff returns the changes of the fields of a todo from before to after,
+// either of which can be nil.
+func diff(before, after *dbtodos.Todo) (map[string]*dbevents.Change, error) {
+	// Get the fields on both sides.
+	from, err := Fields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := Fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compare each field.
+	changes := make(map[string]*dbevents.Change)
+	for name := range from {
+		if !bytes.Equal(from[name], to[name]) {
+			changes[name] = &dbevents.Change{
+				From: from[name],
+				To:   to[name],
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// Fields returns the fields of the given todo which are kept in its history,
+// encoded as JSON, which are all null when the todo is nil.
+func Fields(t *dbtodos.Todo) (map[string]json.RawMessage, error) {
+	// Get the values of the fields.
+	values := map[string]interface{}{
+		"detail":      nil,
+		"completed":   nil,
+		"due_at":      nil,
+		"remind_at":   nil,
+		"list_id":     nil,
+		"parent_id":   nil,
+		"assignee_id": nil,
+		"tags":        nil,
+	}
+	if t != nil {
+		tags := t.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+
+		values["detail"] = t.Detail
+		values["completed"] = t.Completed
+		values["due_at"] = utc(t.DueAt)
+		values["remind_at"] = utc(t.RemindAt)
+		values["list_id"] = t.ListID
+		values["parent_id"] = t.ParentID
+		values["assignee_id"] = t.AssigneeID
+		values["tags"] = tags
+	}
+
+	// Encode each value.
+	encoded := make(map[string]json.RawMessage, len(values))
+	for name, value := range values {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		encoded[name] = b
+	}
+
+	return encoded, nil
+}
+
+// utc returns the given time in UTC, so the same time is always encoded the
+// same way.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	u := t.UTC()
+	return &u
+}
@@ -0,0 +1,19 @@
+package keys
+
+import (
+	"errors"
+
+	dbkeys "gotodo/database/keys"
+)
+
+var (
+	// ErrNameEmpty is returned when the name param is empty.
+	ErrNameEmpty = errors.New("Name parameter is empty")
+
+	// ErrInvalidKey is returned when the API key used for authentication
+	// is invalid.
+	ErrInvalidKey = errors.New("API key is invalid")
+
+	// ErrKeyNotFound is returned when an API key could not be found.
+	ErrKeyNotFound = dbkeys.ErrKeyNotFound
+)
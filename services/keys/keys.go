@@ -0,0 +1,220 @@
+package keys
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"gotodo/database"
+	dbkeys "gotodo/database/keys"
+	"gotodo/services/errors"
+	"gotodo/services/secrets"
+)
+
+const (
+	// keyPrefix is prepended to every generated API key so they are easy
+	// to recognize, e.g. when scanning for leaked secrets.
+	keyPrefix = "gtd_"
+
+	// keyBytes is the number of random bytes in an API key.
+	keyBytes = 24
+
+	// prefixLength is the number of leading characters of an API key that
+	// are stored in plain text to identify it.
+	prefixLength = 12
+)
+
+// Service defines the API keys service.
+type Service struct {
+	db *database.Database
+}
+
+// New returns a new API keys service.
+func New(db *database.Database) *Service {
+	return &Service{
+		db: db,
+	}
+}
+
+// Key defines an API key.
+type Key dbkeys.Key
+
+// Keys defines a set of API keys.
+type Keys struct {
+	Keys  []*Key `json:"keys"`
+	Total int    `json:"total"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams struct {
+	Name string `json:"name"`
+}
+
+// New creates a new API key for the given member.
+//
+// The plain text key is returned alongside the stored key. It is never
+// persisted and cannot be retrieved again.
+func (s *Service) New(mid int, params *NewParams) (*Key, string, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check name.
+	if params.Name == "" {
+		pes.Add(errors.NewParamError("name", ErrNameEmpty))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, "", pes
+	}
+
+	// Generate the key.
+	b := make([]byte, keyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+	secret := keyPrefix + hex.EncodeToString(b)
+
+	// Create this key in the database.
+	dbk, err := s.db.Keys.New(mid, &dbkeys.NewParams{
+		Name:   params.Name,
+		Prefix: secret[:prefixLength],
+		Hash:   secrets.Hash(secret),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Create a new Key.
+	key := &Key{
+		ID:       dbk.ID,
+		MemberID: dbk.MemberID,
+		Name:     dbk.Name,
+		Prefix:   dbk.Prefix,
+		Hash:     dbk.Hash,
+		Created:  dbk.Created,
+		LastUsed: dbk.LastUsed,
+	}
+
+	return key, secret, nil
+}
+
+// GetByMemberID retrieves the API keys of a given member.
+func (s *Service) GetByMemberID(mid int) (*Keys, error) {
+	// Try to pull the keys from the database.
+	dbks, err := s.db.Keys.GetByMemberID(mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Keys.
+	keys := &Keys{
+		Keys:  []*Key{},
+		Total: dbks.Total,
+	}
+
+	// Loop through the set of keys.
+	for _, k := range dbks.Keys {
+		// Create a new Key.
+		key := &Key{
+			ID:       k.ID,
+			MemberID: k.MemberID,
+			Name:     k.Name,
+			Prefix:   k.Prefix,
+			Hash:     k.Hash,
+			Created:  k.Created,
+			LastUsed: k.LastUsed,
+		}
+
+		// Add to keys set.
+		keys.Keys = append(keys.Keys, key)
+	}
+
+	return keys, nil
+}
+
+// UpdateParams defines the parameters for the update methods.
+type UpdateParams struct {
+	Name *string `json:"name"`
+}
+
+// UpdateByIDAndMemberID updates an API key.
+func (s *Service) UpdateByIDAndMemberID(id, mid int, params *UpdateParams) (*Key, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check name.
+	if params.Name != nil && *params.Name == "" {
+		pes.Add(errors.NewParamError("name", ErrNameEmpty))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Try to pull this key from the database.
+	if _, err := s.db.Keys.GetByIDAndMemberID(id, mid); err != nil {
+		return nil, err
+	}
+
+	// Update this key in the database.
+	dbk, err := s.db.Keys.Update(id, &dbkeys.UpdateParams{
+		Name: params.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Key.
+	key := &Key{
+		ID:       dbk.ID,
+		MemberID: dbk.MemberID,
+		Name:     dbk.Name,
+		Prefix:   dbk.Prefix,
+		Hash:     dbk.Hash,
+		Created:  dbk.Created,
+		LastUsed: dbk.LastUsed,
+	}
+
+	return key, nil
+}
+
+// DeleteByIDAndMemberID revokes an API key.
+func (s *Service) DeleteByIDAndMemberID(id, mid int) error {
+	return s.db.Keys.DeleteByIDAndMemberID(id, mid)
+}
+
+// Authenticate looks up the API key matching the given plain text key and
+// records that it was used.
+func (s *Service) Authenticate(secret string) (*Key, error) {
+	// Try to pull this key from the database.
+	dbk, err := s.db.Keys.GetByHash(secrets.Hash(secret))
+	if err == dbkeys.ErrKeyNotFound {
+		return nil, ErrInvalidKey
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Record the time this key was last used.
+	now := time.Now()
+	dbk, err = s.db.Keys.Update(dbk.ID, &dbkeys.UpdateParams{
+		LastUsed: &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Key.
+	key := &Key{
+		ID:       dbk.ID,
+		MemberID: dbk.MemberID,
+		Name:     dbk.Name,
+		Prefix:   dbk.Prefix,
+		Hash:     dbk.Hash,
+		Created:  dbk.Created,
+		LastUsed: dbk.LastUsed,
+	}
+
+	return key, nil
+}
@@ -0,0 +1,27 @@
+package lists
+
+import (
+	"errors"
+
+	dblists "gotodo/database/lists"
+	dbworkspaces "gotodo/database/workspaces"
+)
+
+var (
+	// ErrNameEmpty is returned when the name param is empty.
+	ErrNameEmpty = errors.New("Name parameter is empty")
+
+	// ErrTodosInvalid is returned when the todos param is invalid.
+	ErrTodosInvalid = errors.New("Todos parameter is invalid, must be either inbox or delete")
+
+	// ErrOwnerOnly is returned when a member changes a list they do not
+	// own.
+	ErrOwnerOnly = errors.New("Only the owner of the list can do this")
+
+	// ErrListNotFound is returned when a list could not be found.
+	ErrListNotFound = dblists.ErrListNotFound
+
+	// ErrWorkspaceNotFound is returned when a workspace could not be
+	// found, or the member does not belong to it.
+	ErrWorkspaceNotFound = dbworkspaces.ErrWorkspaceNotFound
+)
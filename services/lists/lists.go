@@ -0,0 +1,457 @@
+package lists
+
+import (
+	"time"
+
+	"gotodo/blobstore"
+	"gotodo/database"
+	dblists "gotodo/database/lists"
+	dbshares "gotodo/database/shares"
+	dbworkspaces "gotodo/database/workspaces"
+	"gotodo/services/attachments"
+	"gotodo/services/errors"
+	"gotodo/services/history"
+)
+
+const (
+	// PermissionOwner is the permission of a member on their own lists.
+	PermissionOwner = "owner"
+
+	// PermissionEditor is the permission of a member on the lists shared
+	// with them to edit their todos.
+	PermissionEditor = dbshares.PermissionEditor
+
+	// PermissionViewer is the permission of a member on the lists shared
+	// with them to view their todos.
+	PermissionViewer = dbshares.PermissionViewer
+
+	// TodosInbox moves the todos of a deleted list to the inbox.
+	TodosInbox = "inbox"
+
+	// TodosDelete deletes the todos of a deleted list along with it.
+	TodosDelete = "delete"
+)
+
+// Service defines the lists service.
+type Service struct {
+	db    *database.Database
+	blobs blobstore.BlobStore
+}
+
+// New returns a new lists service, deleting the bytes of the attachments on
+// the todos of deleted lists from the given blob store.
+func New(db *database.Database, blobs blobstore.BlobStore) *Service {
+	return &Service{
+		db:    db,
+		blobs: blobs,
+	}
+}
+
+// List defines a todo list.
+//
+// Permission is the permission of the member the list was retrieved for,
+// which is PermissionOwner for their own lists, or the permission they were
+// given for lists shared with them. Within a workspace, it is
+// PermissionOwner for the lists they created or when they are an owner or
+// admin of the workspace, and PermissionEditor otherwise.
+type List struct {
+	ID          int       `json:"id"`
+	MemberID    int       `json:"member_id"`
+	WorkspaceID *int      `json:"workspace_id"`
+	Created     time.Time `json:"created"`
+	Name        string    `json:"name"`
+	Permission  string    `json:"permission"`
+}
+
+// Lists defines a set of todo lists.
+type Lists struct {
+	Lists []*List `json:"lists"`
+	Total int     `json:"total"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams dblists.NewParams
+
+// New creates a new list within the given workspace, or within the personal
+// space of the member when nil.
+func (s *Service) New(mid int, wid *int, params *NewParams) (*List, error) {
+	// Check the member belongs to the workspace.
+	if wid != nil {
+		if _, err := s.workspaceRole(*wid, mid); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check name.
+	if params.Name == "" {
+		pes.Add(errors.NewParamError("name", ErrNameEmpty))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Create this list in the database.
+	dbl, err := s.db.Lists.New(mid, &dblists.NewParams{
+		WorkspaceID: wid,
+		Name:        params.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newList(dbl, PermissionOwner), nil
+}
+
+// GetByMemberID retrieves the lists of the given workspace, which the given
+// member must belong to. When the workspace is nil, the lists of the
+// personal space of the member are retrieved instead, followed by the lists
+// shared with them.
+func (s *Service) GetByMemberID(mid int, wid *int) (*Lists, error) {
+	// Handle the lists of the workspace.
+	if wid != nil {
+		return s.getByWorkspaceID(*wid, mid)
+	}
+
+	// Try to pull the lists from the database.
+	dbls, err := s.db.Lists.GetByMemberID(mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Lists.
+	lists := &Lists{
+		Lists: []*List{},
+	}
+
+	// Loop through the set of lists, skipping
+	// the ones the member created in workspaces.
+	for _, l := range dbls.Lists {
+		if l.WorkspaceID == nil {
+			lists.Lists = append(lists.Lists, newList(l, PermissionOwner))
+		}
+	}
+
+	// Try to pull the shared lists from the database.
+	accepted := true
+	dbss, err := s.db.Shares.Get(&dbshares.GetParams{
+		MemberID: &mid,
+		Accepted: &accepted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Loop through the set of shares.
+	for _, share := range dbss.Shares {
+		if share.ListID == nil {
+			continue
+		}
+
+		// Skip lists that no longer exist.
+		dbl, err := s.db.Lists.GetByID(*share.ListID)
+		if err == dblists.ErrListNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		lists.Lists = append(lists.Lists, newList(dbl, share.Permission))
+	}
+	lists.Total = len(lists.Lists)
+
+	return lists, nil
+}
+
+// getByWorkspaceID retrieves the lists of the given workspace, which the
+// given member must belong to.
+func (s *Service) getByWorkspaceID(wid, mid int) (*Lists, error) {
+	// Get the role of the member.
+	role, err := s.workspaceRole(wid, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Try to pull the lists from the database.
+	dbls, err := s.db.Lists.GetByWorkspaceID(wid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Lists.
+	lists := &Lists{
+		Lists: []*List{},
+		Total: dbls.Total,
+	}
+
+	// Loop through the set of lists.
+	for _, l := range dbls.Lists {
+		lists.Lists = append(lists.Lists, newList(l, workspacePermission(role, l.MemberID == mid)))
+	}
+
+	return lists, nil
+}
+
+// GetByIDAndMemberID retrieves a list by its ID, which must be in the given
+// workspace, or either belong to the given member or be shared with them
+// when nil.
+func (s *Service) GetByIDAndMemberID(id, mid int, wid *int) (*List, error) {
+	// Try to pull this list from the database.
+	dbl, permission, err := s.get(id, mid, wid)
+	if err != nil {
+		return nil, err
+	}
+
+	return newList(dbl, permission), nil
+}
+
+// get retrieves a list of the given workspace, or of the personal space of
+// the member when nil, along with the permission of the member on it.
+// ErrListNotFound is returned when the member has no access to the list.
+func (s *Service) get(id, mid int, wid *int) (*dblists.List, string, error) {
+	// Try to pull this list from the database.
+	dbl, err := s.db.Lists.GetByID(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Check the list is in the workspace.
+	if (dbl.WorkspaceID == nil) != (wid == nil) || (wid != nil && *dbl.WorkspaceID != *wid) {
+		return nil, "", ErrListNotFound
+	}
+
+	// Get the permission of the member
+	// within the workspace.
+	if wid != nil {
+		role, err := s.workspaceRole(*wid, mid)
+		if err == ErrWorkspaceNotFound {
+			return nil, "", ErrListNotFound
+		} else if err != nil {
+			return nil, "", err
+		}
+
+		return dbl, workspacePermission(role, dbl.MemberID == mid), nil
+	}
+
+	// Check if the member owns this list.
+	if dbl.MemberID == mid {
+		return dbl, PermissionOwner, nil
+	}
+
+	// Try to pull the shares of this list from the database.
+	accepted := true
+	dbss, err := s.db.Shares.Get(&dbshares.GetParams{
+		MemberID: &mid,
+		ListID:   &id,
+		Accepted: &accepted,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Keep the highest permission.
+	var permission string
+	for _, share := range dbss.Shares {
+		if permission != PermissionEditor {
+			permission = share.Permission
+		}
+	}
+
+	// Check if the member has access.
+	if permission == "" {
+		return nil, "", ErrListNotFound
+	}
+
+	return dbl, permission, nil
+}
+
+// UpdateParams defines the parameters for the update methods.
+type UpdateParams dblists.UpdateParams
+
+// UpdateByIDAndMemberID updates a list of the given workspace, or of the
+// personal space of the member when nil. Only the owner of a list can
+// update it.
+func (s *Service) UpdateByIDAndMemberID(id, mid int, wid *int, params *UpdateParams) (*List, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check name.
+	if params.Name != nil && *params.Name == "" {
+		pes.Add(errors.NewParamError("name", ErrNameEmpty))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Check the member owns this list.
+	if _, permission, err := s.get(id, mid, wid); err != nil {
+		return nil, err
+	} else if permission != PermissionOwner {
+		return nil, ErrOwnerOnly
+	}
+
+	// Update this list in the database.
+	dbl, err := s.db.Lists.Update(id, &dblists.UpdateParams{
+		Name: params.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newList(dbl, PermissionOwner), nil
+}
+
+// DeleteParams defines the parameters for the DeleteByIDAndMemberID method.
+//
+// Todos decides what happens to the todos of the list, either TodosInbox or
+// TodosDelete. The todos are moved to the inbox by default.
+type DeleteParams struct {
+	Todos string `json:"todos"`
+}
+
+// DeleteByIDAndMemberID deletes a list of the given workspace, or of the
+// personal space of the member when nil, either moving its todos to the
+// inbox or deleting them, their shares, comments and attachments along with
+// it.
+// Only the owner of a list can delete it.
+//
+// The change of its todos is recorded in their history.
+func (s *Service) DeleteByIDAndMemberID(id, mid int, wid *int, params *DeleteParams) error {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check todos.
+	if params.Todos != "" && params.Todos != TodosInbox && params.Todos != TodosDelete {
+		pes.Add(errors.NewParamError("todos", ErrTodosInvalid))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return pes
+	}
+
+	// Check the member owns this list.
+	dbl, permission, err := s.get(id, mid, wid)
+	if err != nil {
+		return err
+	} else if permission != PermissionOwner {
+		return ErrOwnerOnly
+	}
+
+	// Delete this list from the database, along
+	// with its shares, in a single transaction.
+	var ids []int
+	if err := s.db.Transaction(func(tx *database.Database) error {
+		// Get the todos of the list.
+		var err error
+		ids, err = tx.Todos.GetIDsByListID(id)
+		if err != nil {
+			return err
+		}
+		dbts, err := history.Get(tx, ids)
+		if err != nil {
+			return err
+		}
+
+		// Handle the todos of the list, deleting the
+		// shares of the deleted todos and their comments.
+		if params.Todos == TodosDelete {
+			// Get the subtasks of the todos, which
+			// are kept as top level todos.
+			subtasks, err := history.GetSubtasks(tx, ids)
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.Todos.DeleteByListID(id); err != nil {
+				return err
+			}
+			if err := tx.Shares.DeleteByTodoIDs(ids); err != nil {
+				return err
+			}
+			if err := tx.Comments.DeleteByTodoIDs(ids); err != nil {
+				return err
+			}
+
+			// Record the deletion of the todos,
+			// and the update of their subtasks.
+			if err := history.RecordDeleted(tx, mid, dbts); err != nil {
+				return err
+			}
+			if err := history.RecordUpdated(tx, mid, subtasks); err != nil {
+				return err
+			}
+		} else {
+			if _, err := tx.Todos.MoveToInbox(id); err != nil {
+				return err
+			}
+
+			// Record the move of the todos.
+			if err := history.RecordUpdated(tx, mid, dbts); err != nil {
+				return err
+			}
+		}
+
+		// Delete the shares of this list.
+		if err := tx.Shares.DeleteByListID(id); err != nil {
+			return err
+		}
+
+		return tx.Lists.DeleteByIDAndMemberID(id, dbl.MemberID)
+	}); err != nil {
+		return err
+	}
+
+	// Delete the attachments on the deleted todos,
+	// which are not part of the transaction as their
+	// files cannot be rolled back.
+	if params.Todos == TodosDelete {
+		return attachments.DeleteByTodoIDs(s.db, s.blobs, ids)
+	}
+
+	return nil
+}
+
+// workspaceRole returns the role of the given member within the given
+// workspace, returning ErrWorkspaceNotFound if they do not belong to it.
+func (s *Service) workspaceRole(wid, mid int) (string, error) {
+	// Try to pull this member from the database.
+	dbm, err := s.db.Workspaces.GetMember(wid, mid)
+	if err == dbworkspaces.ErrMemberNotFound {
+		return "", ErrWorkspaceNotFound
+	} else if err != nil {
+		return "", err
+	}
+
+	return dbm.Role, nil
+}
+
+// workspacePermission returns the permission of a member with the given
+// role on a list of their workspace, where created tells whether they
+// created the list.
+func workspacePermission(role string, created bool) string {
+	if created || role == dbworkspaces.RoleOwner || role == dbworkspaces.RoleAdmin {
+		return PermissionOwner
+	}
+
+	return PermissionEditor
+}
+
+// newList returns the service List of the given database list, with the
+// given permission of the member it was retrieved for.
+func newList(l *dblists.List, permission string) *List {
+	return &List{
+		ID:          l.ID,
+		MemberID:    l.MemberID,
+		WorkspaceID: l.WorkspaceID,
+		Created:     l.Created,
+		Name:        l.Name,
+		Permission:  permission,
+	}
+}
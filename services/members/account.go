@@ -0,0 +1,472 @@
+package members
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gotodo/database"
+	dbmembers "gotodo/database/members"
+	dbtodos "gotodo/database/todos"
+	dbworkspaces "gotodo/database/workspaces"
+	"gotodo/services/attachments"
+	"gotodo/services/errors"
+	"gotodo/services/history"
+	"gotodo/services/workspaces"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxDisplayName is the maximum number of characters of a display name.
+const maxDisplayName = 255
+
+// UpdateProfileParams defines the parameters for the UpdateProfile method.
+//
+// The password is only required to change the email.
+type UpdateProfileParams struct {
+	Email       *string `json:"email"`
+	DisplayName *string `json:"display_name"`
+	Timezone    *string `json:"timezone"`
+	Password    string  `json:"password"`
+}
+
+// UpdateProfile updates the profile of a member.
+//
+// Changing the email requires the password of the member, who is then
+// unverified until the new email is verified. The verification tokens sent
+// to the previous email are used up.
+func (s *Service) UpdateProfile(id int, params *UpdateProfileParams) (*Member, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check email.
+	emailChanged := params.Email != nil && *params.Email != dbm.Email
+	if params.Email != nil && *params.Email == "" {
+		pes.Add(errors.NewParamError("email", ErrEmailEmpty))
+	} else if emailChanged {
+		found, err := s.db.Members.GetByEmail(*params.Email)
+		if err == nil && found.ID != id {
+			pes.Add(errors.NewParamError("email", ErrEmailExists))
+		} else if err != nil && err != dbmembers.ErrMemberNotFound {
+			return nil, err
+		}
+
+		// Validate the password.
+		if err = bcrypt.CompareHashAndPassword([]byte(dbm.Password), []byte(params.Password)); err != nil {
+			pes.Add(errors.NewParamError("password", ErrCurrentPasswordInvalid))
+		}
+	}
+
+	// Check display name.
+	var displayName *string
+	if params.DisplayName != nil {
+		name := strings.TrimSpace(*params.DisplayName)
+		if len([]rune(name)) > maxDisplayName {
+			pes.Add(errors.NewParamError("display_name", ErrDisplayNameLength))
+		}
+		displayName = &name
+	}
+
+	// Check timezone.
+	if params.Timezone != nil {
+		if *params.Timezone == "" || *params.Timezone == "Local" {
+			pes.Add(errors.NewParamError("timezone", ErrTimezoneInvalid))
+		} else if _, err := time.LoadLocation(*params.Timezone); err != nil {
+			pes.Add(errors.NewParamError("timezone", ErrTimezoneInvalid))
+		}
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Create the update parameters.
+	update := &dbmembers.UpdateParams{
+		DisplayName: displayName,
+		Timezone:    params.Timezone,
+	}
+
+	// Handle email change.
+	if emailChanged {
+		// Use up the verification tokens sent
+		// to the previous email.
+		if err := s.db.OneTime.MarkUsedByMemberID(id, PurposeVerifyEmail); err != nil {
+			return nil, err
+		}
+
+		update.Email = params.Email
+		update.ClearVerifiedAt = true
+	}
+
+	// Update this member in the database.
+	if dbm, err = s.db.Members.Update(id, update); err != nil {
+		return nil, err
+	}
+
+	return newMember(dbm), nil
+}
+
+// CloseParams defines the parameters for the Close method.
+//
+// The code is only required when the member has two-factor authentication
+// enabled, and is either a TOTP code or a recovery code.
+type CloseParams struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// Close closes the account of a member, which requires their password.
+//
+// The account is deleted once the given grace period has passed, or right
+// away if it is zero, in which case nil is returned. Every session of the
+// member is revoked, and logging in during the grace period reopens the
+// account.
+func (s *Service) Close(id int, params *CloseParams, grace time.Duration) (*Member, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the password.
+	if err = bcrypt.CompareHashAndPassword([]byte(dbm.Password), []byte(params.Password)); err != nil {
+		pes.Add(errors.NewParamError("password", ErrCurrentPasswordInvalid))
+		return nil, pes
+	}
+
+	// Check code.
+	if dbm.TOTPEnabledAt != nil {
+		if ok, err := s.checkCode(dbm, params.Code); err != nil {
+			return nil, err
+		} else if !ok {
+			pes.Add(errors.NewParamError("code", ErrCodeInvalid))
+			return nil, pes
+		}
+	}
+
+	// Check no workspace is left without an owner.
+	if err := s.checkWorkspaces(id); err != nil {
+		return nil, err
+	}
+
+	// Delete the account right away
+	// if there is no grace period.
+	if grace <= 0 {
+		return nil, s.Purge(id)
+	}
+
+	// Update this member in the database.
+	deleteAt := time.Now().Add(grace)
+	if dbm, err = s.db.Members.Update(id, &dbmembers.UpdateParams{
+		DeleteAt: &deleteAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	// Revoke the sessions of this member.
+	if err := s.db.Tokens.RevokeByMemberID(id); err != nil {
+		return nil, err
+	}
+
+	return newMember(dbm), nil
+}
+
+// Purge deletes a member along with all of their data, such as their todos,
+// lists, tags, shares and API keys, in a single transaction.
+//
+// The lists and todos the member created in workspaces are kept for the
+// other members of those workspaces, while the member is removed from them
+// along with the invitations sent to their email. The todos assigned to the
+// member are unassigned, and the comments they wrote are deleted along with
+// every comment on their personal todos.
+//
+// The workspaces the member is the only member of are deleted along with
+// everything in them. In the workspaces they are the last owner of, which
+// Close prevents but the other members may have joined since, the member
+// who joined first is made an owner.
+//
+// The changes in the history of their personal todos, and of the todos they
+// deleted before, are cleared, while removing their tags from the todos which
+// are kept, and unassigning them, is recorded in the history of those todos.
+// The attachments on the deleted todos are deleted once the rest is.
+func (s *Service) Purge(id int) error {
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	var deleted []int
+	if err := s.db.Transaction(func(tx *database.Database) error {
+		// Delete the workspaces the member is the
+		// only member of, and keep an owner in the
+		// others.
+		wids, err := leaveWorkspaces(tx, id)
+		if err != nil {
+			return err
+		}
+
+		// Delete the personal todos, along with
+		// the comments on them.
+		ids, err := tx.Todos.GetIDsByMemberID(id, &dbtodos.DeleteParams{Personal: true})
+		if err != nil {
+			return err
+		}
+		deleted = append(wids, ids...)
+		if err := tx.Comments.DeleteByTodoIDs(ids); err != nil {
+			return err
+		}
+		if _, err := tx.Todos.DeleteByMemberID(id, &dbtodos.DeleteParams{Personal: true}); err != nil {
+			return err
+		}
+
+		// Clear the changes in the history of the
+		// personal todos, and of the todos the member
+		// deleted before.
+		dids, err := tx.Events.GetDeletedTodoIDsByMemberID(id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Events.ClearChangesByTodoIDs(append(ids, dids...)); err != nil {
+			return err
+		}
+
+		// Remove the tags of the member from the todos
+		// they created in workspaces, recording the
+		// update of those todos.
+		dbtags, err := tx.Todos.GetTagsByMemberID(id)
+		if err != nil {
+			return err
+		}
+		tagIDs := make([]int, len(dbtags.Tags))
+		for i, t := range dbtags.Tags {
+			tagIDs[i] = t.ID
+		}
+		tids, err := tx.Todos.GetIDsByTagIDs(tagIDs)
+		if err != nil {
+			return err
+		}
+		dbts, err := history.Get(tx, tids)
+		if err != nil {
+			return err
+		}
+		if err := tx.Todos.DeleteTagsByMemberID(id); err != nil {
+			return err
+		}
+		if err := history.RecordUpdated(tx, id, dbts); err != nil {
+			return err
+		}
+
+		// Unassign the member, recording the update
+		// of the todos assigned to them.
+		aids, err := tx.Todos.GetIDsByAssigneeID(id, nil)
+		if err != nil {
+			return err
+		}
+		if dbts, err = history.Get(tx, aids); err != nil {
+			return err
+		}
+		if err := tx.Todos.UnassignByMemberID(id, nil); err != nil {
+			return err
+		}
+		if err := history.RecordUpdated(tx, id, dbts); err != nil {
+			return err
+		}
+
+		// Delete the shares, both those the member
+		// owns and those given to them.
+		if err := tx.Shares.DeleteByMemberID(id); err != nil {
+			return err
+		}
+
+		// Delete the comments the member wrote.
+		if err := tx.Comments.DeleteByMemberID(id); err != nil {
+			return err
+		}
+
+		// Remove the member from their workspaces.
+		if err := tx.Workspaces.DeleteMembersByMemberID(id); err != nil {
+			return err
+		}
+		if err := tx.Invitations.DeleteByEmail(dbm.Email); err != nil {
+			return err
+		}
+
+		// Delete the lists and series.
+		if err := tx.Lists.DeleteByMemberID(id); err != nil {
+			return err
+		}
+		if err := tx.Series.DeleteByMemberID(id); err != nil {
+			return err
+		}
+
+		// Delete the credentials.
+		if err := tx.Keys.DeleteByMemberID(id); err != nil {
+			return err
+		}
+		if err := tx.Tokens.DeleteByMemberID(id); err != nil {
+			return err
+		}
+		if err := tx.OneTime.DeleteByMemberID(id); err != nil {
+			return err
+		}
+		if err := tx.Recovery.DeleteByMemberID(id); err != nil {
+			return err
+		}
+
+		// Delete the member.
+		return tx.Members.Delete(id)
+	}); err != nil {
+		return err
+	}
+
+	// Delete the attachments on the deleted todos,
+	// which are not part of the transaction as their
+	// files cannot be rolled back.
+	return attachments.DeleteByTodoIDs(s.db, s.blobs, deleted)
+}
+
+// checkWorkspaces returns ErrLastOwner when the given member is the last
+// owner of a workspace which has other members, which would be left without
+// an owner once the member is deleted.
+func (s *Service) checkWorkspaces(id int) error {
+	// Try to pull the workspaces from the database.
+	dbws, err := s.db.Workspaces.GetByMemberID(id)
+	if err != nil {
+		return err
+	}
+
+	// Check each workspace.
+	for _, dbw := range dbws.Workspaces {
+		dbms, err := s.db.Workspaces.GetMembers(dbw.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(dbms.Members) > 1 && lastOwner(dbms, id) {
+			return ErrLastOwner
+		}
+	}
+
+	return nil
+}
+
+// leaveWorkspaces deletes the workspaces the given member is the only member
+// of using the given database, such as a transaction, returning the IDs of
+// the todos deleted with them. In the workspaces they are the last owner of,
+// the member who joined first is made an owner.
+func leaveWorkspaces(db *database.Database, id int) ([]int, error) {
+	// Try to pull the workspaces from the database.
+	dbws, err := db.Workspaces.GetByMemberID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle each workspace.
+	deleted := []int{}
+	for _, dbw := range dbws.Workspaces {
+		dbms, err := db.Workspaces.GetMembers(dbw.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Delete the workspace if the member
+		// is its only member.
+		if len(dbms.Members) == 1 {
+			ids, err := workspaces.Delete(db, dbw.ID)
+			if err != nil {
+				return nil, err
+			}
+			deleted = append(deleted, ids...)
+			continue
+		}
+
+		// Make the member who joined first
+		// an owner if the member is the last.
+		if !lastOwner(dbms, id) {
+			continue
+		}
+		var first *dbworkspaces.Member
+		for _, dbm := range dbms.Members {
+			if dbm.MemberID != id && (first == nil || dbm.Created.Before(first.Created)) {
+				first = dbm
+			}
+		}
+		if _, err := db.Workspaces.UpdateMember(dbw.ID, first.MemberID, dbworkspaces.RoleOwner); err != nil {
+			return nil, err
+		}
+	}
+
+	return deleted, nil
+}
+
+// lastOwner returns whether the member with the given ID is the last owner
+// among the given members of a workspace.
+func lastOwner(dbms *dbworkspaces.Members, id int) bool {
+	var owner bool
+	for _, dbm := range dbms.Members {
+		if dbm.Role != dbworkspaces.RoleOwner {
+			continue
+		}
+		if dbm.MemberID != id {
+			return false
+		}
+		owner = true
+	}
+
+	return owner
+}
+
+// PurgeClosed deletes the members whose account was closed and whose grace
+// period has passed, returning the number of members deleted.
+//
+// A member who could not be deleted does not stop the others from being
+// deleted, the failures are returned together once every member was tried.
+func (s *Service) PurgeClosed() (int, error) {
+	// Try to pull the members from the database.
+	dbms, err := s.db.Members.GetByDeleteAtBefore(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	// Delete each member, keeping
+	// track of the failures.
+	var purged int
+	var failures []string
+	for _, dbm := range dbms {
+		if err := s.Purge(dbm.ID); err != nil {
+			failures = append(failures, fmt.Sprintf("member %d: %s", dbm.ID, err))
+			continue
+		}
+		purged++
+	}
+
+	// Return the failures, if any.
+	if len(failures) > 0 {
+		return purged, fmt.Errorf("could not purge %s", strings.Join(failures, ", "))
+	}
+
+	return purged, nil
+}
+
+// reopen cancels the deletion of the account of the given member, if it was
+// closed, returning the updated member.
+func (s *Service) reopen(dbm *dbmembers.Member) (*dbmembers.Member, error) {
+	if dbm.DeleteAt == nil {
+		return dbm, nil
+	}
+
+	return s.db.Members.Update(dbm.ID, &dbmembers.UpdateParams{
+		ClearDeleteAt: true,
+	})
+}
@@ -0,0 +1,137 @@
+package members
+
+import (
+	"strings"
+	"testing"
+
+	"gotodo/blobstore"
+	"gotodo/database"
+	dbattachments "gotodo/database/attachments"
+	dbmembers "gotodo/database/members"
+	dbtodos "gotodo/database/todos"
+	dbworkspaces "gotodo/database/workspaces"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newTestWorkspace creates a workspace for the tests with the given members
+// and their roles, in the order they joined.
+func newTestWorkspace(t *testing.T, db *database.Database, name string, mids []int, roles []string) *dbworkspaces.Workspace {
+	dbw, err := db.Workspaces.New(&dbworkspaces.NewParams{Name: name})
+	if err != nil {
+		t.Fatalf("Workspaces.New() error: %s", err)
+	}
+	for i, mid := range mids {
+		if _, err := db.Workspaces.NewMember(dbw.ID, mid, roles[i]); err != nil {
+			t.Fatalf("Workspaces.NewMember() error: %s", err)
+		}
+	}
+
+	return dbw
+}
+
+func TestPurgeWorkspaces(t *testing.T) {
+	db := database.NewMemory()
+	blobs := blobstore.NewLocal(t.TempDir())
+	s := New(db, nil, nil, blobs)
+
+	// Create the member being purged, and
+	// the members of their workspaces.
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt.GenerateFromPassword() error: %s", err)
+	}
+	mids := []int{}
+	for _, email := range []string{"purged@gotodo.io", "second@gotodo.io", "third@gotodo.io"} {
+		dbm, err := db.Members.New(&dbmembers.NewParams{Email: email, Password: string(hash)})
+		if err != nil {
+			t.Fatalf("Members.New() error: %s", err)
+		}
+		mids = append(mids, dbm.ID)
+	}
+	id := mids[0]
+
+	// The member is the only member of one workspace,
+	// and the last owner of another one.
+	alone := newTestWorkspace(t, db, "Alone", []int{id}, []string{dbworkspaces.RoleOwner})
+	shared := newTestWorkspace(t, db, "Shared", mids, []string{dbworkspaces.RoleOwner, dbworkspaces.RoleMember, dbworkspaces.RoleMember})
+
+	// Add a todo with an attachment to the workspace
+	// only the member belongs to, and a todo to the
+	// other one.
+	dbt, err := db.Todos.New(id, &dbtodos.NewParams{WorkspaceID: &alone.ID, Detail: "alone"})
+	if err != nil {
+		t.Fatalf("Todos.New() error: %s", err)
+	}
+	key := "0123456789abcdef"
+	if err := blobs.Put(key, strings.NewReader("bytes")); err != nil {
+		t.Fatalf("Put() error: %s", err)
+	}
+	if _, err := db.Attachments.New(dbt.ID, id, &dbattachments.NewParams{Name: "a.png", ContentType: "image/png", Size: 5, Key: key}); err != nil {
+		t.Fatalf("Attachments.New() error: %s", err)
+	}
+	kept, err := db.Todos.New(id, &dbtodos.NewParams{WorkspaceID: &shared.ID, Detail: "shared"})
+	if err != nil {
+		t.Fatalf("Todos.New() error: %s", err)
+	}
+
+	// Closing the account is refused while the
+	// shared workspace would be left without an owner.
+	if _, err := s.Close(id, &CloseParams{Password: "password"}, 0); err != ErrLastOwner {
+		t.Fatalf("Close() returned %v, want %v", err, ErrLastOwner)
+	}
+
+	if err := s.Purge(id); err != nil {
+		t.Fatalf("Purge() error: %s", err)
+	}
+
+	// The workspace only the member belonged to is
+	// deleted along with its todos and attachments.
+	if _, err := db.Workspaces.GetByID(alone.ID); err != dbworkspaces.ErrWorkspaceNotFound {
+		t.Errorf("Workspaces.GetByID() of the deleted workspace returned %v, want %v", err, dbworkspaces.ErrWorkspaceNotFound)
+	}
+	if _, err := db.Todos.GetByID(dbt.ID); err != dbtodos.ErrTodoNotFound {
+		t.Errorf("Todos.GetByID() of a todo of the deleted workspace returned %v, want %v", err, dbtodos.ErrTodoNotFound)
+	}
+	dbas, err := db.Attachments.GetByTodoID(dbt.ID)
+	if err != nil {
+		t.Fatalf("Attachments.GetByTodoID() error: %s", err)
+	}
+	if len(dbas.Attachments) != 0 {
+		t.Errorf("Attachments.GetByTodoID() returned %d attachments, want 0", len(dbas.Attachments))
+	}
+	if _, err := blobs.Get(key); err != blobstore.ErrBlobNotFound {
+		t.Errorf("Get() of the attachment bytes returned %v, want %v", err, blobstore.ErrBlobNotFound)
+	}
+
+	// The other workspace is kept with its todos, and
+	// the member who joined first becomes its owner.
+	if _, err := db.Todos.GetByID(kept.ID); err != nil {
+		t.Errorf("Todos.GetByID() of a todo of the kept workspace error: %s", err)
+	}
+	dbms, err := db.Workspaces.GetMembers(shared.ID)
+	if err != nil {
+		t.Fatalf("Workspaces.GetMembers() error: %s", err)
+	}
+	roles := map[int]string{}
+	for _, dbm := range dbms.Members {
+		roles[dbm.MemberID] = dbm.Role
+	}
+	want := map[int]string{
+		mids[1]: dbworkspaces.RoleOwner,
+		mids[2]: dbworkspaces.RoleMember,
+	}
+	if len(roles) != len(want) {
+		t.Errorf("Workspaces.GetMembers() returned %d members, want %d", len(roles), len(want))
+	}
+	for mid, role := range want {
+		if roles[mid] != role {
+			t.Errorf("role of member %d = %q, want %q", mid, roles[mid], role)
+		}
+	}
+
+	// The member is deleted.
+	if _, err := db.Members.GetByID(id); err != dbmembers.ErrMemberNotFound {
+		t.Errorf("Members.GetByID() of the purged member returned %v, want %v", err, dbmembers.ErrMemberNotFound)
+	}
+}
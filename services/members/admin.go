@@ -0,0 +1,197 @@
+package members
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	dbmembers "gotodo/database/members"
+	"gotodo/services/errors"
+)
+
+const (
+	// RoleMember is the role of regular members.
+	RoleMember = dbmembers.RoleMember
+
+	// RoleAdmin is the role of the administrators.
+	RoleAdmin = dbmembers.RoleAdmin
+
+	// RoleReadOnly is the role of the members who can read but not change
+	// their todos, lists, tags and series.
+	RoleReadOnly = dbmembers.RoleReadOnly
+)
+
+// IsRole returns whether the given role is one of the member roles.
+func IsRole(role string) bool {
+	return role == RoleMember || role == RoleAdmin || role == RoleReadOnly
+}
+
+// Members defines a set of members.
+type Members struct {
+	Members []*Member `json:"members"`
+	Total   int       `json:"total"`
+}
+
+// GetParams defines the parameters for the Get method.
+type GetParams dbmembers.GetParams
+
+// Get retrieves a set of members.
+func (s *Service) Get(params *GetParams) (*Members, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check role.
+	if params.Role != nil && !IsRole(*params.Role) {
+		pes.Add(errors.NewParamError("role", ErrRoleInvalid))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Try to pull the members from the database.
+	dbms, err := s.db.Members.Get((*dbmembers.GetParams)(params))
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Members.
+	members := &Members{
+		Members: []*Member{},
+		Total:   dbms.Total,
+	}
+
+	// Loop through the set of members.
+	for _, dbm := range dbms.Members {
+		members.Members = append(members.Members, newMember(dbm))
+	}
+
+	return members, nil
+}
+
+// SetRoleParams defines the parameters for the SetRole method.
+type SetRoleParams struct {
+	Role string `json:"role"`
+}
+
+// SetRole sets the role of a member.
+func (s *Service) SetRole(id int, params *SetRoleParams) (*Member, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check role.
+	if !IsRole(params.Role) {
+		pes.Add(errors.NewParamError("role", ErrRoleInvalid))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Update this member in the database.
+	dbm, err := s.db.Members.Update(id, &dbmembers.UpdateParams{
+		Role: &params.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newMember(dbm), nil
+}
+
+// Suspend suspends a member, who cannot log in or use the API until the
+// suspension is lifted. Every session of the member is revoked.
+func (s *Service) Suspend(id int) (*Member, error) {
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the time of an earlier suspension.
+	if dbm.SuspendedAt == nil {
+		now := time.Now()
+		if dbm, err = s.db.Members.Update(id, &dbmembers.UpdateParams{
+			SuspendedAt: &now,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Revoke the sessions of this member.
+	if err := s.db.Tokens.RevokeByMemberID(id); err != nil {
+		return nil, err
+	}
+
+	return newMember(dbm), nil
+}
+
+// Unsuspend lifts the suspension of a member.
+func (s *Service) Unsuspend(id int) (*Member, error) {
+	// Update this member in the database.
+	dbm, err := s.db.Members.Update(id, &dbmembers.UpdateParams{
+		ClearSuspendedAt: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newMember(dbm), nil
+}
+
+// ResetParams defines the parameters for the Reset method.
+type ResetParams struct {
+	DisableTOTP bool `json:"disable_totp"`
+}
+
+// Reset resets the password of a member, such as when their account was
+// taken over or they lost access to it.
+//
+// The password is replaced with a random one nobody knows, which stops
+// every JWT of the member from working, and every session of the member is
+// revoked. A password reset token is then emailed to the member, which
+// expires after the given amount of time. Two-factor authentication is also
+// disabled if asked to.
+func (s *Service) Reset(id int, params *ResetParams, expiry time.Duration) (*Member, error) {
+	// Generate a random password.
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	// Replace the password of this member,
+	// which revokes their sessions.
+	if _, err := s.setPassword(id, hex.EncodeToString(b)); err != nil {
+		return nil, err
+	}
+
+	// Disable two-factor authentication.
+	if params.DisableTOTP {
+		if _, err := s.db.Members.Update(id, &dbmembers.UpdateParams{
+			ClearTOTPSecret:    true,
+			ClearTOTPEnabledAt: true,
+		}); err != nil {
+			return nil, err
+		}
+
+		// Delete the recovery codes of this member.
+		if err := s.db.Recovery.DeleteByMemberID(id); err != nil {
+			return nil, err
+		}
+	}
+
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send a password reset token.
+	if err := s.sendPasswordReset(dbm, "An administrator reset the password of your Go Todo account.\n\n", "If you did not expect this, please contact the administrator.", expiry); err != nil {
+		return nil, err
+	}
+
+	return newMember(dbm), nil
+}
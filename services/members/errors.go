@@ -16,6 +16,60 @@ var (
 	// ErrPassword is returned when the password is in an invalid format.
 	ErrPassword = errors.New("Password must be at least 8 characters")
 
+	// ErrCurrentPasswordInvalid is returned when the current password given
+	// to change the password or the email is invalid.
+	ErrCurrentPasswordInvalid = errors.New("Current password is invalid")
+
+	// ErrResetTokenInvalid is returned when a password reset token is
+	// unknown, expired or already used.
+	ErrResetTokenInvalid = errors.New("Password reset token is invalid or has expired")
+
+	// ErrLastOwner is returned when closing the account of the last
+	// owner of a workspace which has other members.
+	ErrLastOwner = errors.New("You are the last owner of a workspace with other members, make another member an owner first")
+
+	// ErrVerifyTokenInvalid is returned when an email verification token is
+	// unknown, expired or already used.
+	ErrVerifyTokenInvalid = errors.New("Verification token is invalid or has expired")
+
+	// ErrEmailVerified is returned when a verification is requested for
+	// an email that is verified already.
+	ErrEmailVerified = errors.New("Email is verified already")
+
+	// ErrTOTPEnabled is returned when enrolling in two-factor
+	// authentication while it is enabled already.
+	ErrTOTPEnabled = errors.New("Two-factor authentication is enabled already")
+
+	// ErrTOTPNotEnrolled is returned when two-factor authentication is
+	// confirmed before enrolling in it.
+	ErrTOTPNotEnrolled = errors.New("Two-factor authentication must be enrolled in first")
+
+	// ErrTOTPNotEnabled is returned when two-factor authentication is
+	// disabled while it is not enabled.
+	ErrTOTPNotEnabled = errors.New("Two-factor authentication is not enabled")
+
+	// ErrCodeInvalid is returned when a TOTP code or recovery code is
+	// invalid.
+	ErrCodeInvalid = errors.New("Code is invalid")
+
+	// ErrMFATokenInvalid is returned when the token used to finish logging
+	// in with two-factor authentication is unknown, expired or already
+	// used.
+	ErrMFATokenInvalid = errors.New("MFA token is invalid or has expired, please log in again")
+
+	// ErrDisplayNameLength is returned when a display name is too long.
+	ErrDisplayNameLength = errors.New("Display name must be 255 characters or less")
+
+	// ErrTimezoneInvalid is returned when a timezone is not a known IANA
+	// timezone name.
+	ErrTimezoneInvalid = errors.New("Timezone must be an IANA timezone name such as Europe/Paris")
+
+	// ErrRoleInvalid is returned when a role is not one of the member roles.
+	ErrRoleInvalid = errors.New("Role must be one of member, admin or read-only")
+
+	// ErrSuspended is returned when a suspended member logs in.
+	ErrSuspended = errors.New("Account is suspended")
+
 	// ErrInvalidLogin is returned when the email and/or password used
 	// with login is invalid.
 	ErrInvalidLogin = errors.New("Email and/or password is invalid")
@@ -1,28 +1,79 @@
 package members
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"gotodo/blobstore"
 	"gotodo/database"
 	dbmembers "gotodo/database/members"
+	dbonetime "gotodo/database/onetime"
+	"gotodo/mailer"
 	"gotodo/services/errors"
+	"gotodo/services/secrets"
+	"gotodo/throttle"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// PurposePasswordReset is the purpose of the one-time tokens used to
+	// reset a password.
+	PurposePasswordReset = "password_reset"
+
+	// PurposeVerifyEmail is the purpose of the one-time tokens used to
+	// verify an email.
+	PurposeVerifyEmail = "verify_email"
+
+	// tokenBytes is the number of random bytes in a one-time token.
+	tokenBytes = 32
+)
+
 // Service defines the members service.
 type Service struct {
-	db *database.Database
+	db       *database.Database
+	mailer   mailer.Mailer
+	throttle *throttle.Throttle
+	blobs    blobstore.BlobStore
 }
 
-// New returns a new members service.
-func New(db *database.Database) *Service {
+// New returns a new members service, sending emails using the given
+// mailer, throttling logins using the given throttle and deleting the bytes
+// of the attachments on the todos of purged accounts from the given blob
+// store.
+func New(db *database.Database, m mailer.Mailer, t *throttle.Throttle, blobs blobstore.BlobStore) *Service {
 	return &Service{
-		db: db,
+		db:       db,
+		mailer:   m,
+		throttle: t,
+		blobs:    blobs,
 	}
 }
 
 // Members defines a member.
 type Member dbmembers.Member
 
+// newMember returns a new Member from the given database member.
+func newMember(dbm *dbmembers.Member) *Member {
+	return &Member{
+		ID:            dbm.ID,
+		Email:         dbm.Email,
+		Password:      dbm.Password,
+		VerifiedAt:    dbm.VerifiedAt,
+		TOTPSecret:    dbm.TOTPSecret,
+		TOTPEnabledAt: dbm.TOTPEnabledAt,
+		TOTPCounter:   dbm.TOTPCounter,
+		DisplayName:   dbm.DisplayName,
+		Timezone:      dbm.Timezone,
+		DeleteAt:      dbm.DeleteAt,
+		Role:          dbm.Role,
+		SuspendedAt:   dbm.SuspendedAt,
+	}
+}
+
 // NewParams defines the parameters for the New method.
 type NewParams dbmembers.NewParams
 
@@ -68,45 +119,76 @@ func (s *Service) New(params *NewParams) (*Member, error) {
 		return nil, err
 	}
 
-	// Create a new Member.
-	member := &Member{
-		ID:       dbm.ID,
-		Email:    dbm.Email,
-		Password: dbm.Password,
-	}
-
-	return member, nil
+	return newMember(dbm), nil
 }
 
 // LoginParams defines the parameters for the Login method.
+//
+// IP is the client IP the login attempt comes from, used to throttle
+// failed attempts.
 type LoginParams struct {
 	Email    string
 	Password string
+	IP       string `json:"-"`
 }
 
 // Login checks if a member exists in the database and can log in.
+//
+// Failed attempts are throttled per account and per client IP, returning
+// a *throttle.Error once the login attempts have to wait.
 func (s *Service) Login(params *LoginParams) (*Member, error) {
+	// Check whether this login attempt has to wait,
+	// before spending any time on the password.
+	if err := s.throttle.Check(params.Email, params.IP); err != nil {
+		return nil, err
+	}
+
 	// Try to pull this member from the database.
 	dbm, err := s.db.Members.GetByEmail(params.Email)
 	if err == dbmembers.ErrMemberNotFound {
-		return nil, ErrInvalidLogin
+		return nil, s.loginFailed(params.Email, params.IP, ErrInvalidLogin)
 	} else if err != nil {
 		return nil, err
 	}
 
 	// Validate the password.
 	if err = bcrypt.CompareHashAndPassword([]byte(dbm.Password), []byte(params.Password)); err != nil {
-		return nil, ErrInvalidLogin
+		return nil, s.loginFailed(params.Email, params.IP, ErrInvalidLogin)
+	}
+
+	// Check the member is not suspended.
+	if dbm.SuspendedAt != nil {
+		return nil, ErrSuspended
+	}
+
+	// Keep the failures until the member enters
+	// their second factor, if enabled.
+	if dbm.TOTPEnabledAt != nil {
+		return newMember(dbm), nil
+	}
+
+	// Forget the failed login attempts.
+	if err := s.throttle.Succeed(dbm.Email); err != nil {
+		return nil, err
+	}
+
+	// Cancel the deletion of the account.
+	if dbm, err = s.reopen(dbm); err != nil {
+		return nil, err
 	}
 
-	// Create a new Member.
-	member := &Member{
-		ID:       dbm.ID,
-		Email:    dbm.Email,
-		Password: dbm.Password,
+	return newMember(dbm), nil
+}
+
+// loginFailed records a failed login attempt to the given account from the
+// given client IP, returning the given error unless it could not be
+// recorded.
+func (s *Service) loginFailed(email, ip string, failure error) error {
+	if err := s.throttle.Fail(email, ip); err != nil {
+		return err
 	}
 
-	return member, nil
+	return failure
 }
 
 // GetByID retrieves a member by their ID.
@@ -117,12 +199,311 @@ func (s *Service) GetByID(id int) (*Member, error) {
 		return nil, err
 	}
 
-	// Create a new Member.
-	member := &Member{
-		ID:       dbm.ID,
-		Email:    dbm.Email,
-		Password: dbm.Password,
+	return newMember(dbm), nil
+}
+
+// ChangePasswordParams defines the parameters for the ChangePassword method.
+type ChangePasswordParams struct {
+	CurrentPassword string `json:"current_password"`
+	Password        string `json:"password"`
+}
+
+// ChangePassword changes the password of a member, which requires their
+// current password.
+//
+// Changing the password also changes the signing key of the JWTs of the
+// member, so every JWT issued before stops working, and revokes every
+// session of the member.
+func (s *Service) ChangePassword(id int, params *ChangePasswordParams) (*Member, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check password.
+	if len(params.Password) < 8 {
+		pes.Add(errors.NewParamError("password", ErrPassword))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the current password.
+	if err = bcrypt.CompareHashAndPassword([]byte(dbm.Password), []byte(params.CurrentPassword)); err != nil {
+		pes.Add(errors.NewParamError("current_password", ErrCurrentPasswordInvalid))
+		return nil, pes
+	}
+
+	return s.setPassword(id, params.Password)
+}
+
+// ForgotPasswordParams defines the parameters for the ForgotPassword method.
+type ForgotPasswordParams struct {
+	Email string `json:"email"`
+}
+
+// ForgotPassword emails a password reset token to the member with the given
+// email, which expires after the given amount of time.
+//
+// Nothing is sent when there is no member with this email, without telling
+// the caller so that it cannot be used to find out which emails are used.
+func (s *Service) ForgotPassword(params *ForgotPasswordParams, expiry time.Duration) error {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check email.
+	if params.Email == "" {
+		pes.Add(errors.NewParamError("email", ErrEmailEmpty))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return pes
+	}
+
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByEmail(params.Email)
+	if err == dbmembers.ErrMemberNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return s.sendPasswordReset(dbm, "Someone asked to reset the password of your Go Todo account.\n\n", "If you did not ask for this, you can ignore this email.", expiry)
+}
+
+// sendPasswordReset emails a password reset token to a member, which expires
+// after the given amount of time, between the given intro and outro.
+func (s *Service) sendPasswordReset(dbm *dbmembers.Member, intro, outro string, expiry time.Duration) error {
+	// Create a new reset token.
+	secret, err := s.newOneTimeToken(dbm.ID, PurposePasswordReset, expiry)
+	if err != nil {
+		return err
+	}
+
+	// Send the reset token.
+	return s.mailer.Send(&mailer.Message{
+		To:      dbm.Email,
+		Subject: "Reset your Go Todo password",
+		Body: fmt.Sprintf("%sUse the following token to choose a new password, it expires in %d minutes:\n\n%s\n\n%s",
+			intro, int(expiry.Minutes()), secret, outro),
+	})
+}
+
+// ResetPasswordParams defines the parameters for the ResetPassword method.
+type ResetPasswordParams struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// ResetPassword sets a new password for the member a password reset token
+// was sent to, which revokes every session of the member. Every reset token
+// of the member is used up afterwards.
+func (s *Service) ResetPassword(params *ResetPasswordParams) (*Member, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check password.
+	if len(params.Password) < 8 {
+		pes.Add(errors.NewParamError("password", ErrPassword))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Try to use the reset token.
+	mid, err := s.useOneTimeToken(PurposePasswordReset, params.Token)
+	if err == dbonetime.ErrTokenNotFound {
+		pes.Add(errors.NewParamError("token", ErrResetTokenInvalid))
+		return nil, pes
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Use up the other reset tokens of this member.
+	if err := s.db.OneTime.MarkUsedByMemberID(mid, PurposePasswordReset); err != nil {
+		return nil, err
+	}
+
+	return s.setPassword(mid, params.Password)
+}
+
+// SendVerification emails a verification token to a member, which expires
+// after the given amount of time. If a link is given, the token is added to
+// it as the token query parameter and the link is sent instead.
+func (s *Service) SendVerification(id int, link string, expiry time.Duration) error {
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Check the member is not verified already.
+	if dbm.VerifiedAt != nil {
+		return ErrEmailVerified
+	}
+
+	// Create a new verification token.
+	secret, err := s.newOneTimeToken(dbm.ID, PurposeVerifyEmail, expiry)
+	if err != nil {
+		return err
+	}
+
+	// Add the token to the link.
+	verification := secret
+	if link != "" {
+		u, err := url.Parse(link)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		q.Set("token", secret)
+		u.RawQuery = q.Encode()
+		verification = u.String()
+	}
+
+	// Send the verification token.
+	return s.mailer.Send(&mailer.Message{
+		To:      dbm.Email,
+		Subject: "Verify your Go Todo email",
+		Body: fmt.Sprintf("Please verify the email of your Go Todo account.\n\n"+
+			"Use the following to verify it, it expires in %d minutes:\n\n%s\n\n"+
+			"If you did not create an account, you can ignore this email.", int(expiry.Minutes()), verification),
+	})
+}
+
+// VerifyParams defines the parameters for the Verify method.
+type VerifyParams struct {
+	Token string `json:"token"`
+}
+
+// Verify marks the member a verification token was sent to as verified.
+// Every verification token of the member is used up afterwards.
+func (s *Service) Verify(params *VerifyParams) (*Member, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Try to use the verification token.
+	mid, err := s.useOneTimeToken(PurposeVerifyEmail, params.Token)
+	if err == dbonetime.ErrTokenNotFound {
+		pes.Add(errors.NewParamError("token", ErrVerifyTokenInvalid))
+		return nil, pes
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Use up the other verification tokens of this member.
+	if err := s.db.OneTime.MarkUsedByMemberID(mid, PurposeVerifyEmail); err != nil {
+		return nil, err
+	}
+
+	// Update this member in the database.
+	now := time.Now()
+	dbm, err := s.db.Members.Update(mid, &dbmembers.UpdateParams{
+		VerifiedAt: &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newMember(dbm), nil
+}
+
+// ChangeEmailParams defines the parameters for the ChangeEmail method.
+type ChangeEmailParams struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// ChangeEmail changes the email of a member, which requires their password.
+//
+// The member is unverified until the new email is verified, as with the
+// UpdateProfile method.
+func (s *Service) ChangeEmail(id int, params *ChangeEmailParams) (*Member, error) {
+	return s.UpdateProfile(id, &UpdateProfileParams{
+		Email:    &params.Email,
+		Password: params.Password,
+	})
+}
+
+// setPassword hashes and sets the password of a member, revoking every
+// session of the member so they are logged out everywhere.
+func (s *Service) setPassword(id int, password string) (*Member, error) {
+	// Hash the password.
+	pwhash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update this member in the database.
+	hash := string(pwhash)
+	dbm, err := s.db.Members.Update(id, &dbmembers.UpdateParams{
+		Password: &hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Revoke the sessions of this member.
+	if err := s.db.Tokens.RevokeByMemberID(id); err != nil {
+		return nil, err
+	}
+
+	return newMember(dbm), nil
+}
+
+// newOneTimeToken creates a new one-time token for the given member and
+// purpose, which expires after the given amount of time. The plain text
+// token is returned.
+func (s *Service) newOneTimeToken(mid int, purpose string, expiry time.Duration) (string, error) {
+	// Generate the token.
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	secret := hex.EncodeToString(b)
+
+	// Create this token in the database.
+	if _, err := s.db.OneTime.New(mid, &dbonetime.NewParams{
+		Purpose:   purpose,
+		Hash:      secrets.Hash(secret),
+		ExpiresAt: time.Now().Add(expiry),
+	}); err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+// useOneTimeToken uses up the given one-time token, returning the ID of the
+// member it belongs to. dbonetime.ErrTokenNotFound is returned if the token
+// is not valid for the given purpose.
+func (s *Service) useOneTimeToken(purpose, secret string) (int, error) {
+	// Try to pull this token from the database.
+	dbt, err := s.db.OneTime.GetByPurposeAndHash(purpose, secrets.Hash(secret))
+	if err != nil {
+		return 0, err
+	}
+
+	// Check the token has not expired.
+	if !dbt.ExpiresAt.After(time.Now()) {
+		return 0, dbonetime.ErrTokenNotFound
+	}
+
+	// Mark the token as used.
+	if err := s.db.OneTime.MarkUsed(dbt.ID); err == dbonetime.ErrTokenUsed {
+		return 0, dbonetime.ErrTokenNotFound
+	} else if err != nil {
+		return 0, err
 	}
 
-	return member, nil
+	return dbt.MemberID, nil
 }
@@ -0,0 +1,385 @@
+package members
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	dbmembers "gotodo/database/members"
+	dbonetime "gotodo/database/onetime"
+	dbrecovery "gotodo/database/recovery"
+	"gotodo/services/errors"
+	"gotodo/services/secrets"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// TOTPIssuer is the issuer shown by authenticator apps.
+	TOTPIssuer = "Go Todo"
+
+	// PurposeMFAChallenge is the purpose of the one-time tokens used to
+	// finish logging in with two-factor authentication.
+	PurposeMFAChallenge = "mfa_challenge"
+
+	// totpDigits is the number of digits of a TOTP code.
+	totpDigits = 6
+
+	// totpPeriod is the number of seconds a TOTP code is valid for.
+	totpPeriod = 30
+
+	// totpSkew is the number of periods before and after the current one
+	// a TOTP code is accepted for, to allow for clock drift.
+	totpSkew = 1
+
+	// totpSecretBytes is the number of random bytes in a TOTP secret.
+	totpSecretBytes = 20
+
+	// recoveryCodes is the number of recovery codes given to a member.
+	recoveryCodes = 10
+
+	// recoveryCodeBytes is the number of random bytes in a recovery code.
+	recoveryCodeBytes = 10
+)
+
+// base32NoPad is the encoding of TOTP secrets and recovery codes.
+var base32NoPad = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPEnrollment defines the TOTP secret of a member, to add to an
+// authenticator app either directly or through the otpauth URI.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// EnrollTOTP starts enrolling a member in two-factor authentication,
+// generating a new TOTP secret. It is enabled once confirmed with the
+// ConfirmTOTP method.
+func (s *Service) EnrollTOTP(id int) (*TOTPEnrollment, error) {
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check two-factor authentication is not enabled already.
+	if dbm.TOTPEnabledAt != nil {
+		return nil, ErrTOTPEnabled
+	}
+
+	// Generate the secret.
+	b := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	secret := base32NoPad.EncodeToString(b)
+
+	// Update this member in the database.
+	if _, err := s.db.Members.Update(id, &dbmembers.UpdateParams{
+		TOTPSecret: &secret,
+	}); err != nil {
+		return nil, err
+	}
+
+	// Build the otpauth URI, see:
+	// https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+	uri := fmt.Sprintf("otpauth://totp/%s?secret=%s&issuer=%s&algorithm=SHA1&digits=%d&period=%d",
+		url.PathEscape(TOTPIssuer+":"+dbm.Email), secret, url.PathEscape(TOTPIssuer), totpDigits, totpPeriod)
+
+	// Create a new TOTPEnrollment.
+	enrollment := &TOTPEnrollment{
+		Secret: secret,
+		URI:    uri,
+	}
+
+	return enrollment, nil
+}
+
+// ConfirmTOTPParams defines the parameters for the ConfirmTOTP method.
+type ConfirmTOTPParams struct {
+	Code string `json:"code"`
+}
+
+// ConfirmTOTP enables two-factor authentication for a member, given the
+// first code of their authenticator app. The recovery codes of the member
+// are returned, which are not shown again.
+func (s *Service) ConfirmTOTP(id int, params *ConfirmTOTPParams) ([]string, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the member is enrolling.
+	if dbm.TOTPEnabledAt != nil {
+		return nil, ErrTOTPEnabled
+	} else if dbm.TOTPSecret == nil {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	// Check code.
+	counter, ok := checkTOTP(*dbm.TOTPSecret, params.Code, time.Now(), dbm.TOTPCounter)
+	if !ok {
+		pes.Add(errors.NewParamError("code", ErrCodeInvalid))
+		return nil, pes
+	}
+
+	// Update this member in the database.
+	now := time.Now()
+	if _, err := s.db.Members.Update(id, &dbmembers.UpdateParams{
+		TOTPEnabledAt: &now,
+		TOTPCounter:   &counter,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.newRecoveryCodes(id)
+}
+
+// DisableTOTPParams defines the parameters for the DisableTOTP method.
+type DisableTOTPParams struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// DisableTOTP disables two-factor authentication for a member, which
+// requires their password and either a TOTP code or a recovery code.
+func (s *Service) DisableTOTP(id int, params *DisableTOTPParams) error {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Check two-factor authentication is enabled.
+	if dbm.TOTPEnabledAt == nil {
+		return ErrTOTPNotEnabled
+	}
+
+	// Validate the password.
+	if err = bcrypt.CompareHashAndPassword([]byte(dbm.Password), []byte(params.Password)); err != nil {
+		pes.Add(errors.NewParamError("password", ErrCurrentPasswordInvalid))
+		return pes
+	}
+
+	// Check code.
+	if ok, err := s.checkCode(dbm, params.Code); err != nil {
+		return err
+	} else if !ok {
+		pes.Add(errors.NewParamError("code", ErrCodeInvalid))
+		return pes
+	}
+
+	// Update this member in the database.
+	if _, err := s.db.Members.Update(id, &dbmembers.UpdateParams{
+		ClearTOTPSecret:    true,
+		ClearTOTPEnabledAt: true,
+	}); err != nil {
+		return err
+	}
+
+	// Delete the recovery codes of this member.
+	return s.db.Recovery.DeleteByMemberID(id)
+}
+
+// NewMFAChallenge creates the token used to finish logging in a member with
+// two-factor authentication, which expires after the given amount of time.
+func (s *Service) NewMFAChallenge(id int, expiry time.Duration) (string, error) {
+	return s.newOneTimeToken(id, PurposeMFAChallenge, expiry)
+}
+
+// LoginMFAParams defines the parameters for the LoginMFA method.
+//
+// IP is the client IP the login attempt comes from, used to throttle
+// invalid codes.
+type LoginMFAParams struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+	IP       string `json:"-"`
+}
+
+// LoginMFA finishes logging in a member with two-factor authentication,
+// given the token returned when they logged in and either a TOTP code or a
+// recovery code.
+//
+// The token can only be used once, so a wrong code means logging in again.
+func (s *Service) LoginMFA(params *LoginMFAParams) (*Member, error) {
+	// Try to use the token.
+	mid, err := s.useOneTimeToken(PurposeMFAChallenge, params.MFAToken)
+	if err == dbonetime.ErrTokenNotFound {
+		return nil, ErrMFATokenInvalid
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByID(mid)
+	if err == dbmembers.ErrMemberNotFound {
+		return nil, ErrMFATokenInvalid
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Check whether this login attempt has to wait.
+	if err := s.throttle.Check(dbm.Email, params.IP); err != nil {
+		return nil, err
+	}
+
+	// Check code, unless two-factor authentication
+	// was disabled since logging in.
+	if dbm.TOTPEnabledAt != nil {
+		if ok, err := s.checkCode(dbm, params.Code); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, s.loginFailed(dbm.Email, params.IP, ErrCodeInvalid)
+		}
+	}
+
+	// Check the member is not suspended.
+	if dbm.SuspendedAt != nil {
+		return nil, ErrSuspended
+	}
+
+	// Forget the failed login attempts.
+	if err := s.throttle.Succeed(dbm.Email); err != nil {
+		return nil, err
+	}
+
+	// Cancel the deletion of the account.
+	if dbm, err = s.reopen(dbm); err != nil {
+		return nil, err
+	}
+
+	return newMember(dbm), nil
+}
+
+// checkCode checks the given code is either a TOTP code or an unused
+// recovery code of the member, using it up.
+func (s *Service) checkCode(dbm *dbmembers.Member, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	// Check TOTP codes.
+	if len(code) == totpDigits {
+		if dbm.TOTPSecret == nil {
+			return false, nil
+		}
+
+		counter, ok := checkTOTP(*dbm.TOTPSecret, code, time.Now(), dbm.TOTPCounter)
+		if !ok {
+			return false, nil
+		}
+
+		// Keep the code from being used again.
+		_, err := s.db.Members.Update(dbm.ID, &dbmembers.UpdateParams{
+			TOTPCounter: &counter,
+		})
+		return err == nil, err
+	}
+
+	// Check recovery codes, which are accepted
+	// in any case and with or without dashes.
+	code = strings.ToLower(strings.Replace(code, "-", "", -1))
+	dbc, err := s.db.Recovery.GetByMemberIDAndHash(dbm.ID, secrets.Hash(code))
+	if err == dbrecovery.ErrCodeNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	// Mark the recovery code as used.
+	if err := s.db.Recovery.MarkUsed(dbc.ID); err == dbrecovery.ErrCodeUsed {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// newRecoveryCodes replaces the recovery codes of a member, returning the
+// new codes.
+func (s *Service) newRecoveryCodes(mid int) ([]string, error) {
+	// Delete the previous recovery codes.
+	if err := s.db.Recovery.DeleteByMemberID(mid); err != nil {
+		return nil, err
+	}
+
+	codes := []string{}
+	for i := 0; i < recoveryCodes; i++ {
+		// Generate the code.
+		b := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(base32NoPad.EncodeToString(b))
+
+		// Create this code in the database.
+		if _, err := s.db.Recovery.New(mid, &dbrecovery.NewParams{
+			Hash: secrets.Hash(code),
+		}); err != nil {
+			return nil, err
+		}
+
+		// Add the code, split into groups of
+		// four characters to make it easier
+		// to write down.
+		codes = append(codes, code[0:4]+"-"+code[4:8]+"-"+code[8:12]+"-"+code[12:16])
+	}
+
+	return codes, nil
+}
+
+// checkTOTP checks the given code against the TOTP secret at the given
+// time, as defined by RFC 6238, returning the time step of the code.
+//
+// Codes of time steps up to the last one used are rejected, so a code
+// cannot be used twice.
+func checkTOTP(secret, code string, t time.Time, last int64) (int64, bool) {
+	key, err := base32NoPad.DecodeString(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	step := t.Unix() / totpPeriod
+	for i := -totpSkew; i <= totpSkew; i++ {
+		counter := step + int64(i)
+		if counter <= last {
+			continue
+		}
+
+		if hmac.Equal([]byte(totpCode(key, counter)), []byte(code)) {
+			return counter, true
+		}
+	}
+
+	return 0, false
+}
+
+// totpCode returns the TOTP code of the given key for the given time step,
+// using the HOTP algorithm of RFC 4226.
+func totpCode(key []byte, counter int64) string {
+	// Compute the HMAC of the counter.
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// Truncate the HMAC dynamically.
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
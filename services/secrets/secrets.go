@@ -0,0 +1,16 @@
+package secrets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Hash returns the hex encoded SHA-256 hash of the given secret, such as an
+// API key, a refresh token or a one-time token.
+//
+// These secrets are long and random, so unlike passwords a fast hash is
+// enough and lets us look them up directly by their hash.
+func Hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
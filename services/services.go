@@ -1,21 +1,41 @@
 package services
 
 import (
+	"gotodo/blobstore"
 	"gotodo/database"
+	"gotodo/mailer"
+	"gotodo/services/keys"
+	"gotodo/services/lists"
 	"gotodo/services/members"
+	"gotodo/services/shares"
 	"gotodo/services/todos"
+	"gotodo/services/tokens"
+	"gotodo/services/workspaces"
+	"gotodo/throttle"
 )
 
 // Services defines the services.
 type Services struct {
-	Members *members.Service
-	Todos   *todos.Service
+	Keys       *keys.Service
+	Lists      *lists.Service
+	Members    *members.Service
+	Shares     *shares.Service
+	Todos      *todos.Service
+	Tokens     *tokens.Service
+	Workspaces *workspaces.Service
 }
 
-// New returns a new set of services.
-func New(db *database.Database) *Services {
+// New returns a new set of services, sending emails using the given mailer,
+// throttling logins using the given throttle and keeping the bytes of
+// attachments in the given blob store.
+func New(db *database.Database, m mailer.Mailer, t *throttle.Throttle, blobs blobstore.BlobStore) *Services {
 	return &Services{
-		Members: members.New(db),
-		Todos:   todos.New(db),
+		Keys:       keys.New(db),
+		Lists:      lists.New(db, blobs),
+		Members:    members.New(db, m, t, blobs),
+		Shares:     shares.New(db, m),
+		Todos:      todos.New(db, blobs),
+		Tokens:     tokens.New(db),
+		Workspaces: workspaces.New(db, m, blobs),
 	}
 }
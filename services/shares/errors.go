@@ -0,0 +1,35 @@
+package shares
+
+import (
+	"errors"
+
+	dblists "gotodo/database/lists"
+	dbshares "gotodo/database/shares"
+	dbtodos "gotodo/database/todos"
+)
+
+var (
+	// ErrEmailEmpty is returned when the email param is empty.
+	ErrEmailEmpty = errors.New("Email parameter is empty")
+
+	// ErrEmailSelf is returned when a member shares something with
+	// themselves.
+	ErrEmailSelf = errors.New("You cannot share with yourself")
+
+	// ErrPermissionInvalid is returned when the permission param is not
+	// one of the share permissions.
+	ErrPermissionInvalid = errors.New("Permission must be either viewer or editor")
+
+	// ErrShareAccepted is returned when an invitation that was accepted
+	// already is accepted or declined.
+	ErrShareAccepted = errors.New("Invitation was accepted already")
+
+	// ErrShareNotFound is returned when a share could not be found.
+	ErrShareNotFound = dbshares.ErrShareNotFound
+
+	// ErrListNotFound is returned when a list could not be found.
+	ErrListNotFound = dblists.ErrListNotFound
+
+	// ErrTodoNotFound is returned when a todo could not be found.
+	ErrTodoNotFound = dbtodos.ErrTodoNotFound
+)
@@ -0,0 +1,550 @@
+package shares
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gotodo/database"
+	dblists "gotodo/database/lists"
+	dbmembers "gotodo/database/members"
+	dbshares "gotodo/database/shares"
+	dbtodos "gotodo/database/todos"
+	"gotodo/mailer"
+	"gotodo/services/errors"
+	"gotodo/services/history"
+)
+
+const (
+	// PermissionViewer lets a member see a shared list or todo.
+	PermissionViewer = dbshares.PermissionViewer
+
+	// PermissionEditor lets a member see and change a shared list or
+	// todo.
+	PermissionEditor = dbshares.PermissionEditor
+)
+
+// Service defines the shares service.
+type Service struct {
+	db     *database.Database
+	mailer mailer.Mailer
+}
+
+// New returns a new shares service, sending invitations using the given
+// mailer.
+func New(db *database.Database, m mailer.Mailer) *Service {
+	return &Service{
+		db:     db,
+		mailer: m,
+	}
+}
+
+// Share defines a share.
+//
+// Name is the name of the shared list, or the detail of the shared todo.
+// Shares without an AcceptedAt are pending invitations.
+type Share struct {
+	ID          int        `json:"id"`
+	OwnerID     int        `json:"owner_id"`
+	OwnerEmail  string     `json:"owner_email"`
+	MemberID    int        `json:"member_id"`
+	MemberEmail string     `json:"member_email"`
+	ListID      *int       `json:"list_id"`
+	TodoID      *int       `json:"todo_id"`
+	Name        string     `json:"name"`
+	Permission  string     `json:"permission"`
+	Created     time.Time  `json:"created"`
+	AcceptedAt  *time.Time `json:"accepted_at"`
+}
+
+// Shares defines a set of shares.
+type Shares struct {
+	Shares []*Share `json:"shares"`
+	Total  int      `json:"total"`
+}
+
+// NewParams defines the parameters for the ShareList and ShareTodo methods.
+//
+// Email is the email of the member to share with.
+type NewParams struct {
+	Email      string `json:"email"`
+	Permission string `json:"permission"`
+}
+
+// ShareList shares a list of the given owner with another member, who is
+// sent an invitation to accept or decline.
+func (s *Service) ShareList(lid, oid int, params *NewParams) error {
+	// Try to pull this list from the database.
+	dbl, err := s.getList(lid, oid)
+	if err != nil {
+		return err
+	}
+
+	return s.new(oid, &dbshares.NewParams{ListID: &dbl.ID}, "the list \""+dbl.Name+"\"", params)
+}
+
+// ShareTodo shares a todo of the given owner with another member, who is
+// sent an invitation to accept or decline. The subtasks of the todo are
+// shared along with it.
+func (s *Service) ShareTodo(tid, oid int, params *NewParams) error {
+	// Try to pull this todo from the database.
+	dbt, err := s.getTodo(tid, oid)
+	if err != nil {
+		return err
+	}
+
+	return s.new(oid, &dbshares.NewParams{TodoID: &dbt.ID}, "the todo \""+dbt.Detail+"\"", params)
+}
+
+// new creates a new share of the list or todo set in the given database
+// parameters, then sends the invitation. The description names what is
+// being shared in the invitation.
+//
+// Nothing is shared when there is no member with the email, or when it was
+// shared with them already, without telling the caller so that it cannot be
+// used to find out which emails are used. The share is deleted again if the
+// invitation could not be sent, so sharing can be retried.
+func (s *Service) new(oid int, dbparams *dbshares.NewParams, description string, params *NewParams) error {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check email.
+	var dbm *dbmembers.Member
+	email := strings.TrimSpace(params.Email)
+	if email == "" {
+		pes.Add(errors.NewParamError("email", ErrEmailEmpty))
+	} else {
+		var err error
+		dbm, err = s.db.Members.GetByEmail(email)
+		if err == dbmembers.ErrMemberNotFound {
+			dbm = nil
+		} else if err != nil {
+			return err
+		} else if dbm.ID == oid {
+			pes.Add(errors.NewParamError("email", ErrEmailSelf))
+		}
+	}
+
+	// Check permission.
+	if params.Permission != PermissionViewer && params.Permission != PermissionEditor {
+		pes.Add(errors.NewParamError("permission", ErrPermissionInvalid))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return pes
+	}
+
+	// Stop if there is no member with this email.
+	if dbm == nil {
+		return nil
+	}
+
+	// Stop if this was shared with the member already.
+	dbss, err := s.db.Shares.Get(&dbshares.GetParams{
+		MemberID: &dbm.ID,
+		ListID:   dbparams.ListID,
+		TodoID:   dbparams.TodoID,
+	})
+	if err != nil {
+		return err
+	}
+	if dbss.Total > 0 {
+		return nil
+	}
+
+	// Try to pull the owner from the database.
+	owner, err := s.db.Members.GetByID(oid)
+	if err != nil {
+		return err
+	}
+
+	// Create this share in the database.
+	dbparams.MemberID = dbm.ID
+	dbparams.Permission = params.Permission
+	dbs, err := s.db.Shares.New(oid, dbparams)
+	if err != nil {
+		return err
+	}
+
+	// Send the invitation, deleting the
+	// share again if it could not be sent.
+	if err := s.mailer.Send(&mailer.Message{
+		To:      dbm.Email,
+		Subject: fmt.Sprintf("%s shared a %s with you on Go Todo", owner.Email, kind(dbs)),
+		Body: fmt.Sprintf("%s shared %s with you as %s %s.\n\nAccept or decline invitation %d from your shares in Go Todo.",
+			owner.Email, description, article(dbs.Permission), dbs.Permission, dbs.ID),
+	}); err != nil {
+		if derr := s.db.Shares.Delete(dbs.ID); derr != nil {
+			return derr
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetByListID retrieves the shares of a list of the given owner.
+func (s *Service) GetByListID(lid, oid int) (*Shares, error) {
+	// Try to pull this list from the database.
+	if _, err := s.getList(lid, oid); err != nil {
+		return nil, err
+	}
+
+	return s.get(&dbshares.GetParams{
+		OwnerID: &oid,
+		ListID:  &lid,
+	})
+}
+
+// GetByTodoID retrieves the shares of a todo of the given owner.
+func (s *Service) GetByTodoID(tid, oid int) (*Shares, error) {
+	// Try to pull this todo from the database.
+	if _, err := s.getTodo(tid, oid); err != nil {
+		return nil, err
+	}
+
+	return s.get(&dbshares.GetParams{
+		OwnerID: &oid,
+		TodoID:  &tid,
+	})
+}
+
+// GetParams defines the parameters for the GetByMemberID method.
+//
+// Accepted matches the shares which were accepted, or the pending
+// invitations when false.
+type GetParams struct {
+	Accepted *bool `json:"accepted"`
+}
+
+// GetByMemberID retrieves the shares given to a member, including their
+// pending invitations.
+func (s *Service) GetByMemberID(mid int, params *GetParams) (*Shares, error) {
+	return s.get(&dbshares.GetParams{
+		MemberID: &mid,
+		Accepted: params.Accepted,
+	})
+}
+
+// get retrieves the shares matching the given database parameters, skipping
+// the shares of lists and todos that no longer exist.
+func (s *Service) get(params *dbshares.GetParams) (*Shares, error) {
+	// Try to pull the shares from the database.
+	dbss, err := s.db.Shares.Get(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Shares.
+	shares := &Shares{
+		Shares: []*Share{},
+	}
+
+	// Loop through the set of shares.
+	for _, dbs := range dbss.Shares {
+		share, err := s.newShare(dbs)
+		if err == ErrShareNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		// Add to shares set.
+		shares.Shares = append(shares.Shares, share)
+	}
+	shares.Total = len(shares.Shares)
+
+	return shares, nil
+}
+
+// Accept accepts an invitation given to a member, giving them access to the
+// shared list or todo.
+func (s *Service) Accept(id, mid int) (*Share, error) {
+	// Try to pull this invitation from the database.
+	dbs, err := s.getInvitation(id, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update this share in the database.
+	now := time.Now()
+	if dbs, err = s.db.Shares.Update(dbs.ID, &dbshares.UpdateParams{
+		AcceptedAt: &now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.newShare(dbs)
+}
+
+// Decline declines an invitation given to a member, deleting it.
+func (s *Service) Decline(id, mid int) error {
+	// Try to pull this invitation from the database.
+	dbs, err := s.getInvitation(id, mid)
+	if err != nil {
+		return err
+	}
+
+	// Delete this share from the database.
+	return s.db.Shares.Delete(dbs.ID)
+}
+
+// getInvitation retrieves a pending invitation given to a member.
+func (s *Service) getInvitation(id, mid int) (*dbshares.Share, error) {
+	// Try to pull this share from the database.
+	dbs, err := s.db.Shares.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check this share was given to the member.
+	if dbs.MemberID != mid {
+		return nil, ErrShareNotFound
+	}
+
+	// Check this share is pending.
+	if dbs.AcceptedAt != nil {
+		return nil, ErrShareAccepted
+	}
+
+	return dbs, nil
+}
+
+// UpdateParams defines the parameters for the UpdateByIDAndOwnerID method.
+type UpdateParams struct {
+	Permission *string `json:"permission"`
+}
+
+// UpdateByIDAndOwnerID changes the permission of a share of the given owner.
+func (s *Service) UpdateByIDAndOwnerID(id, oid int, params *UpdateParams) (*Share, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check permission.
+	if params.Permission != nil && *params.Permission != PermissionViewer && *params.Permission != PermissionEditor {
+		pes.Add(errors.NewParamError("permission", ErrPermissionInvalid))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Try to pull this share from the database.
+	dbs, err := s.db.Shares.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check this share belongs to the owner.
+	if dbs.OwnerID != oid {
+		return nil, ErrShareNotFound
+	}
+
+	// Update this share in the database.
+	if dbs, err = s.db.Shares.Update(id, &dbshares.UpdateParams{
+		Permission: params.Permission,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.newShare(dbs)
+}
+
+// DeleteByIDAndMemberID deletes a share, either revoking it when the given
+// member is its owner, or leaving it when it was given to them.
+//
+// The member the share was given to is unassigned from the shared todo, or
+// from the todos of the shared list, and from their subtasks, which is
+// recorded in the history of those todos.
+func (s *Service) DeleteByIDAndMemberID(id, mid int) error {
+	// Try to pull this share from the database.
+	dbs, err := s.db.Shares.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Check the member is part of this share.
+	if dbs.OwnerID != mid && dbs.MemberID != mid {
+		return ErrShareNotFound
+	}
+
+	// Delete this share from the database, along
+	// with the assignments it gave access to.
+	return s.db.Transaction(func(tx *database.Database) error {
+		if err := tx.Shares.Delete(id); err != nil {
+			return err
+		}
+
+		return unassign(tx, dbs, mid)
+	})
+}
+
+// unassign removes the member the given share was given to as the assignee
+// of the shared todo, or of the todos of the shared list, and of their
+// subtasks, recording the update of those todos by the given member.
+func unassign(tx *database.Database, dbs *dbshares.Share, mid int) error {
+	// Get the shared todos.
+	var ids []int
+	if dbs.ListID != nil {
+		var err error
+		if ids, err = tx.Todos.GetIDsByListID(*dbs.ListID); err != nil {
+			return err
+		}
+	} else if dbs.TodoID != nil {
+		ids = []int{*dbs.TodoID}
+	}
+	level, err := history.Get(tx, ids)
+	if err == dbtodos.ErrTodoNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// Walk down the shared todos and their subtasks,
+	// keeping those assigned to the member.
+	var dbts []*dbtodos.Todo
+	visited := make(map[int]bool)
+	for len(level) > 0 {
+		var next []*dbtodos.Todo
+		for _, t := range level {
+			if visited[t.ID] {
+				continue
+			}
+			visited[t.ID] = true
+
+			if t.AssigneeID != nil && *t.AssigneeID == dbs.MemberID {
+				dbts = append(dbts, t)
+			}
+
+			// Try to pull the subtasks from the database.
+			subtasks, err := tx.Todos.GetByParentID(t.ID)
+			if err != nil {
+				return err
+			}
+			next = append(next, subtasks.Todos...)
+		}
+		level = next
+	}
+
+	// Unassign the member from these todos.
+	for _, t := range dbts {
+		if _, err := tx.Todos.Update(t.ID, &dbtodos.UpdateParams{
+			ClearAssigneeID: true,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return history.RecordUpdated(tx, mid, dbts)
+}
+
+// newShare returns the service Share of the given database share, returning
+// ErrShareNotFound if the shared list or todo no longer exists.
+func (s *Service) newShare(dbs *dbshares.Share) (*Share, error) {
+	// Create a new Share.
+	share := &Share{
+		ID:         dbs.ID,
+		OwnerID:    dbs.OwnerID,
+		MemberID:   dbs.MemberID,
+		ListID:     dbs.ListID,
+		TodoID:     dbs.TodoID,
+		Permission: dbs.Permission,
+		Created:    dbs.Created,
+		AcceptedAt: dbs.AcceptedAt,
+	}
+
+	// Handle the name of the shared list or todo.
+	if dbs.ListID != nil {
+		dbl, err := s.db.Lists.GetByID(*dbs.ListID)
+		if err == dblists.ErrListNotFound {
+			return nil, ErrShareNotFound
+		} else if err != nil {
+			return nil, err
+		}
+		share.Name = dbl.Name
+	} else if dbs.TodoID != nil {
+		dbt, err := s.db.Todos.GetByID(*dbs.TodoID)
+		if err == dbtodos.ErrTodoNotFound {
+			return nil, ErrShareNotFound
+		} else if err != nil {
+			return nil, err
+		}
+		share.Name = dbt.Detail
+	}
+
+	// Handle the emails of the owner and member.
+	owner, err := s.db.Members.GetByID(dbs.OwnerID)
+	if err == dbmembers.ErrMemberNotFound {
+		return nil, ErrShareNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	share.OwnerEmail = owner.Email
+
+	member, err := s.db.Members.GetByID(dbs.MemberID)
+	if err == dbmembers.ErrMemberNotFound {
+		return nil, ErrShareNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	share.MemberEmail = member.Email
+
+	return share, nil
+}
+
+// getList retrieves a list of the personal space of the given owner.
+// Workspaces share their lists with all of their members instead, so
+// ErrListNotFound is returned for the lists of a workspace.
+func (s *Service) getList(lid, oid int) (*dblists.List, error) {
+	// Try to pull this list from the database.
+	dbl, err := s.db.Lists.GetByIDAndMemberID(lid, oid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the list is in the personal space.
+	if dbl.WorkspaceID != nil {
+		return nil, ErrListNotFound
+	}
+
+	return dbl, nil
+}
+
+// getTodo retrieves a todo of the personal space of the given owner.
+// Workspaces share their todos with all of their members instead, so
+// ErrTodoNotFound is returned for the todos of a workspace.
+func (s *Service) getTodo(tid, oid int) (*dbtodos.Todo, error) {
+	// Try to pull this todo from the database.
+	dbt, err := s.db.Todos.GetByIDAndMemberID(tid, oid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the todo is in the personal space.
+	if dbt.WorkspaceID != nil {
+		return nil, ErrTodoNotFound
+	}
+
+	return dbt, nil
+}
+
+// kind returns what the given share is of, either a list or a todo.
+func kind(dbs *dbshares.Share) string {
+	if dbs.ListID != nil {
+		return "list"
+	}
+
+	return "todo"
+}
+
+// article returns the indefinite article to use before the given
+// permission.
+func article(permission string) string {
+	if permission == PermissionEditor {
+		return "an"
+	}
+
+	return "a"
+}
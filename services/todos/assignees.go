@@ -0,0 +1,105 @@
+package todos
+
+import (
+	"gotodo/database"
+	dbevents "gotodo/database/events"
+	dbtodos "gotodo/database/todos"
+	"gotodo/services/errors"
+)
+
+// AssignParams defines the parameters for the AssignByIDAndMemberID method.
+type AssignParams struct {
+	AssigneeID *int `json:"assignee_id"`
+}
+
+// AssignByIDAndMemberID assigns a todo the given member can edit, as decided
+// by get, to another member.
+//
+// The assignee must have access to the todo, either by belonging to the
+// workspace, or by owning the todo or having it shared with them otherwise.
+// Once assigned, the todo shows up in the todos of the assignee, who can
+// only edit it when it was shared with them to edit.
+func (s *Service) AssignByIDAndMemberID(id, mid int, wid *int, params *AssignParams) (*Todo, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check assignee ID.
+	if params.AssigneeID == nil {
+		pes.Add(errors.NewParamError("assignee_id", ErrAssigneeEmpty))
+		return nil, pes
+	}
+
+	// Try to pull this todo from the database.
+	dbt, permission, err := s.get(id, mid, wid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the member can edit this todo.
+	if permission == PermissionViewer {
+		return nil, ErrTodoReadOnly
+	}
+
+	// Check the assignee has access to this todo.
+	if _, _, err := s.get(id, *params.AssigneeID, wid); err == ErrTodoNotFound {
+		pes.Add(errors.NewParamError("assignee_id", ErrAssigneeInvalid))
+		return nil, pes
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Update this todo in the database, recording
+	// the change in the same transaction.
+	before := dbt
+	if err := s.db.Transaction(func(tx *database.Database) error {
+		var err error
+		if dbt, err = tx.Todos.Update(id, &dbtodos.UpdateParams{
+			AssigneeID: params.AssigneeID,
+		}); err != nil {
+			return err
+		}
+
+		return s.with(tx).record(mid, dbevents.ActionUpdated, before, dbt)
+	}); err != nil {
+		return nil, err
+	}
+
+	return newTodo(dbt, permission), nil
+}
+
+// UnassignByIDAndMemberID removes the assignee of a todo the given member can
+// edit, as decided by get, or which is assigned to them.
+func (s *Service) UnassignByIDAndMemberID(id, mid int, wid *int) error {
+	// Try to pull this todo from the database.
+	dbt, permission, err := s.get(id, mid, wid)
+	if err != nil {
+		return err
+	}
+
+	// Check the member can edit this todo, or is
+	// removing themselves as its assignee.
+	if permission == PermissionViewer && !assigned(dbt, mid) {
+		return ErrTodoReadOnly
+	}
+
+	// Update this todo in the database, recording
+	// the change in the same transaction.
+	if dbt.AssigneeID == nil {
+		return nil
+	}
+	return s.db.Transaction(func(tx *database.Database) error {
+		after, err := tx.Todos.Update(id, &dbtodos.UpdateParams{
+			ClearAssigneeID: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.with(tx).record(mid, dbevents.ActionUpdated, dbt, after)
+	})
+}
+
+// assigned returns whether the given todo is assigned to the given member.
+func assigned(t *dbtodos.Todo, mid int) bool {
+	return t.AssigneeID != nil && *t.AssigneeID == mid
+}
@@ -0,0 +1,227 @@
+package todos
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"gotodo/blobstore"
+	dbattachments "gotodo/database/attachments"
+	"gotodo/services/attachments"
+	"gotodo/services/errors"
+)
+
+// maxAttachmentNameLength is the maximum length of an attachment name.
+const maxAttachmentNameLength = 255
+
+// attachmentKeyBytes is the number of random bytes of the blob key of an
+// attachment.
+const attachmentKeyBytes = 16
+
+// Attachment defines a file attached to a todo.
+type Attachment dbattachments.Attachment
+
+// Attachments defines a set of attachments.
+type Attachments struct {
+	Attachments []*Attachment `json:"attachments"`
+	Total       int           `json:"total"`
+}
+
+// NewAttachmentParams defines the parameters for the NewAttachment method.
+//
+// The size and content type are checked against the limits of the API
+// before the attachment is created, Body is read up to Size bytes.
+type NewAttachmentParams struct {
+	Name        string
+	ContentType string
+	Size        int64
+	Body        io.Reader
+}
+
+// NewAttachment attaches a file to a todo the given member can edit, as
+// decided by get.
+//
+// The bytes of the file are kept in the blob store, and removed again if
+// the attachment could not be created.
+func (s *Service) NewAttachment(tid, mid int, wid *int, params *NewAttachmentParams) (*Attachment, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check name, keeping only the
+	// base name of the file.
+	name := strings.TrimSpace(filepath.Base(strings.Replace(params.Name, "\\", "/", -1)))
+	if name == "" || name == "." || name == "/" {
+		pes.Add(errors.NewParamError("file", ErrAttachmentNameEmpty))
+	} else if utf8.RuneCountInString(name) > maxAttachmentNameLength {
+		pes.Add(errors.NewParamError("file", ErrAttachmentNameLength))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Try to pull this todo from the database.
+	_, permission, err := s.get(tid, mid, wid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the member can edit this todo.
+	if permission == PermissionViewer {
+		return nil, ErrTodoReadOnly
+	}
+
+	// Generate the blob key.
+	b := make([]byte, attachmentKeyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	key := hex.EncodeToString(b)
+
+	// Store the bytes of the file.
+	if err := s.blobs.Put(key, io.LimitReader(params.Body, params.Size)); err != nil {
+		return nil, err
+	}
+
+	// Create this attachment in the database.
+	dba, err := s.db.Attachments.New(tid, mid, &dbattachments.NewParams{
+		Name:        name,
+		ContentType: params.ContentType,
+		Size:        params.Size,
+		Key:         key,
+	})
+	if err != nil {
+		s.blobs.Delete(key)
+		return nil, err
+	}
+
+	return (*Attachment)(dba), nil
+}
+
+// GetAttachmentsByTodoID retrieves the attachments on a todo the given
+// member can see, as decided by get, oldest first.
+func (s *Service) GetAttachmentsByTodoID(tid, mid int, wid *int) (*Attachments, error) {
+	// Check the member can see this todo.
+	if _, _, err := s.get(tid, mid, wid); err != nil {
+		return nil, err
+	}
+
+	// Try to pull the attachments from the database.
+	dbas, err := s.db.Attachments.GetByTodoID(tid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Attachments.
+	attachments := &Attachments{
+		Attachments: []*Attachment{},
+		Total:       dbas.Total,
+	}
+
+	// Loop through the set of attachments.
+	for _, dba := range dbas.Attachments {
+		attachments.Attachments = append(attachments.Attachments, (*Attachment)(dba))
+	}
+
+	return attachments, nil
+}
+
+// OpenAttachmentByIDAndMemberID retrieves an attachment on a todo the given
+// member can see, as decided by get, along with its bytes.
+//
+// The caller must close the returned reader.
+func (s *Service) OpenAttachmentByIDAndMemberID(id, tid, mid int, wid *int) (*Attachment, io.ReadCloser, error) {
+	// Try to pull this attachment from the database.
+	dba, _, err := s.getAttachment(id, tid, mid, wid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Open the bytes of the file.
+	rc, err := s.blobs.Get(dba.Key)
+	if err == blobstore.ErrBlobNotFound {
+		return nil, nil, ErrAttachmentNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	return (*Attachment)(dba), rc, nil
+}
+
+// DeleteAttachmentByIDAndMemberID deletes an attachment on a todo the given
+// member can edit, as decided by get, along with its bytes.
+func (s *Service) DeleteAttachmentByIDAndMemberID(id, tid, mid int, wid *int) error {
+	// Try to pull this attachment from the database.
+	dba, permission, err := s.getAttachment(id, tid, mid, wid)
+	if err != nil {
+		return err
+	}
+
+	// Check the member can edit this todo.
+	if permission == PermissionViewer {
+		return ErrTodoReadOnly
+	}
+
+	return s.deleteAttachment(dba)
+}
+
+// CollectAttachments deletes the attachments on todos which no longer
+// exist, along with their bytes.
+//
+// The attachments on deleted todos are deleted right after them, so this
+// only collects those left behind when that failed, such as when the blob
+// store could not be reached.
+func (s *Service) CollectAttachments() error {
+	// Try to pull the attachments from the database.
+	dbas, err := s.db.Attachments.GetOrphaned()
+	if err != nil {
+		return err
+	}
+
+	// Delete each attachment.
+	for _, dba := range dbas.Attachments {
+		if err := s.deleteAttachment(dba); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getAttachment retrieves an attachment on a todo the given member can see,
+// along with the permission of the member on the todo.
+func (s *Service) getAttachment(id, tid, mid int, wid *int) (*dbattachments.Attachment, string, error) {
+	// Check the member can see this todo.
+	_, permission, err := s.get(tid, mid, wid)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Try to pull this attachment from the database.
+	dba, err := s.db.Attachments.GetByID(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Check the attachment is on this todo.
+	if dba.TodoID != tid {
+		return nil, "", ErrAttachmentNotFound
+	}
+
+	return dba, permission, nil
+}
+
+// deleteAttachments deletes the attachments on the todos with the given IDs,
+// along with their bytes.
+func (s *Service) deleteAttachments(tids []int) error {
+	return attachments.DeleteByTodoIDs(s.db, s.blobs, tids)
+}
+
+// deleteAttachment deletes an attachment along with its bytes.
+func (s *Service) deleteAttachment(dba *dbattachments.Attachment) error {
+	return attachments.Delete(s.db, s.blobs, dba)
+}
@@ -0,0 +1,183 @@
+package todos
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	dbcomments "gotodo/database/comments"
+	"gotodo/services/errors"
+)
+
+// maxCommentLength is the maximum length of a comment body.
+const maxCommentLength = 4096
+
+// Comment defines a comment on a todo.
+type Comment dbcomments.Comment
+
+// Comments defines a set of comments.
+type Comments struct {
+	Comments []*Comment `json:"comments"`
+	Total    int        `json:"total"`
+}
+
+// CommentParams defines the parameters for the NewComment and
+// UpdateCommentByIDAndMemberID methods.
+type CommentParams struct {
+	Body string `json:"body"`
+}
+
+// GetCommentsParams defines the parameters for the GetCommentsByTodoID
+// method.
+type GetCommentsParams struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// NewComment creates a new comment by the given member on a todo they can
+// see, as decided by get.
+//
+// Every member who can see a todo can comment on it, including the members
+// it was shared with to view.
+func (s *Service) NewComment(tid, mid int, wid *int, params *CommentParams) (*Comment, error) {
+	// Check the comment body.
+	if err := checkComment(params); err != nil {
+		return nil, err
+	}
+
+	// Check the member can see this todo.
+	if _, _, err := s.get(tid, mid, wid); err != nil {
+		return nil, err
+	}
+
+	// Try to create this comment in the database.
+	dbc, err := s.db.Comments.New(tid, mid, &dbcomments.NewParams{
+		Body: params.Body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return (*Comment)(dbc), nil
+}
+
+// GetCommentsByTodoID retrieves the comments on a todo the given member can
+// see, as decided by get, oldest first.
+func (s *Service) GetCommentsByTodoID(tid, mid int, wid *int, params *GetCommentsParams) (*Comments, error) {
+	// Check the member can see this todo.
+	if _, _, err := s.get(tid, mid, wid); err != nil {
+		return nil, err
+	}
+
+	// Try to pull the comments from the database.
+	dbcs, err := s.db.Comments.GetByTodoID(tid, &dbcomments.GetParams{
+		Offset: params.Offset,
+		Limit:  params.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Comments.
+	comments := &Comments{
+		Comments: []*Comment{},
+		Total:    dbcs.Total,
+	}
+
+	// Loop through the set of comments.
+	for _, dbc := range dbcs.Comments {
+		comments.Comments = append(comments.Comments, (*Comment)(dbc))
+	}
+
+	return comments, nil
+}
+
+// UpdateCommentByIDAndMemberID changes the body of a comment on a todo the
+// given member can see, as decided by get.
+//
+// Only the member who wrote a comment can edit it, ErrCommentAuthorOnly is
+// returned to everyone else.
+func (s *Service) UpdateCommentByIDAndMemberID(id, tid, mid int, wid *int, params *CommentParams) (*Comment, error) {
+	// Check the comment body.
+	if err := checkComment(params); err != nil {
+		return nil, err
+	}
+
+	// Check the member wrote this comment.
+	if _, err := s.getComment(id, tid, mid, wid); err != nil {
+		return nil, err
+	}
+
+	// Update this comment in the database.
+	dbc, err := s.db.Comments.Update(id, &dbcomments.UpdateParams{
+		Body: &params.Body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return (*Comment)(dbc), nil
+}
+
+// DeleteCommentByIDAndMemberID deletes a comment on a todo the given member
+// can see, as decided by get.
+//
+// Only the member who wrote a comment can delete it, ErrCommentAuthorOnly
+// is returned to everyone else.
+func (s *Service) DeleteCommentByIDAndMemberID(id, tid, mid int, wid *int) error {
+	// Check the member wrote this comment.
+	if _, err := s.getComment(id, tid, mid, wid); err != nil {
+		return err
+	}
+
+	// Try to delete this comment from the database.
+	return s.db.Comments.Delete(id)
+}
+
+// getComment retrieves a comment on a todo the given member can see, which
+// the member must have written.
+func (s *Service) getComment(id, tid, mid int, wid *int) (*dbcomments.Comment, error) {
+	// Check the member can see this todo.
+	if _, _, err := s.get(tid, mid, wid); err != nil {
+		return nil, err
+	}
+
+	// Try to pull this comment from the database.
+	dbc, err := s.db.Comments.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the comment is on this todo.
+	if dbc.TodoID != tid {
+		return nil, ErrCommentNotFound
+	}
+
+	// Check the member wrote this comment.
+	if dbc.MemberID != mid {
+		return nil, ErrCommentAuthorOnly
+	}
+
+	return dbc, nil
+}
+
+// checkComment checks the body of a comment, trimming the whitespace around
+// it.
+func checkComment(params *CommentParams) error {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check body.
+	params.Body = strings.TrimSpace(params.Body)
+	if params.Body == "" {
+		pes.Add(errors.NewParamError("body", ErrCommentEmpty))
+	} else if utf8.RuneCountInString(params.Body) > maxCommentLength {
+		pes.Add(errors.NewParamError("body", ErrCommentLength))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return pes
+	}
+
+	return nil
+}
@@ -3,13 +3,118 @@ package todos
 import (
 	"errors"
 
+	dbattachments "gotodo/database/attachments"
+	dbcomments "gotodo/database/comments"
+	dbevents "gotodo/database/events"
+	dbseries "gotodo/database/series"
 	dbtodos "gotodo/database/todos"
+	dbworkspaces "gotodo/database/workspaces"
 )
 
 var (
 	// ErrDetailEmpty is returned when the detail param is empty.
 	ErrDetailEmpty = errors.New("Detail parameter is empty")
 
+	// ErrAssigneeEmpty is returned when the assignee_id param is empty.
+	ErrAssigneeEmpty = errors.New("Assignee ID parameter is empty")
+
+	// ErrAssigneeInvalid is returned when the assignee_id param does not
+	// belong to a member with access to the todo.
+	ErrAssigneeInvalid = errors.New("Assignee ID parameter must belong to a member with access to the todo")
+
+	// ErrAttachmentNameEmpty is returned when the name of an attached
+	// file is empty.
+	ErrAttachmentNameEmpty = errors.New("File name is empty")
+
+	// ErrAttachmentNameLength is returned when the name of an attached
+	// file is too long.
+	ErrAttachmentNameLength = errors.New("File name must be 255 characters or less")
+
+	// ErrCommentEmpty is returned when the body param of a comment is
+	// empty.
+	ErrCommentEmpty = errors.New("Body parameter is empty")
+
+	// ErrCommentLength is returned when the body param of a comment is
+	// too long.
+	ErrCommentLength = errors.New("Body parameter must be 4096 characters or less")
+
+	// ErrCommentAuthorOnly is returned when a member changes a comment
+	// they did not write.
+	ErrCommentAuthorOnly = errors.New("Only the author of the comment can do this")
+
+	// ErrDeleteFiltersEmpty is returned when a bulk delete is requested
+	// without any filters.
+	ErrDeleteFiltersEmpty = errors.New("At least one filter is required to delete todos")
+
+	// ErrListInvalid is returned when the list_id param does not belong
+	// to a list of the member.
+	ErrListInvalid = errors.New("List ID parameter must belong to an existing list")
+
+	// ErrParentInvalid is returned when the parent_id param does not
+	// belong to a todo of the member.
+	ErrParentInvalid = errors.New("Parent ID parameter must belong to an existing todo")
+
+	// ErrParentCycle is returned when the parent_id param is the todo
+	// itself or one of its subtasks.
+	ErrParentCycle = errors.New("Parent ID parameter cannot be the todo itself or one of its subtasks")
+
+	// ErrTagEmpty is returned when a tag name is empty.
+	ErrTagEmpty = errors.New("Tag name is empty")
+
+	// ErrTagLength is returned when a tag name is too long.
+	ErrTagLength = errors.New("Tag name must be 64 characters or less")
+
+	// ErrTagNameExists is returned when a tag is renamed to the name of
+	// another tag.
+	ErrTagNameExists = errors.New("A tag with this name already exists, merge the tags instead")
+
+	// ErrMergeTagsEmpty is returned when no tags are given to merge.
+	ErrMergeTagsEmpty = errors.New("At least one tag ID is required to merge tags")
+
+	// ErrMergeTagInvalid is returned when a tag being merged could not be
+	// found.
+	ErrMergeTagInvalid = errors.New("Tag IDs must belong to existing tags")
+
+	// ErrRecurrenceInvalid is returned when a recurrence rule could not be
+	// parsed.
+	ErrRecurrenceInvalid = errors.New("Recurrence rule is invalid, it must be a rule such as FREQ=WEEKLY;BYDAY=MO,FR")
+
+	// ErrRecurrenceDueAtEmpty is returned when a recurrence rule is given
+	// for a todo without a due date.
+	ErrRecurrenceDueAtEmpty = errors.New("A due date is required to repeat a todo")
+
+	// ErrSeriesStopped is returned when a stopped series is changed.
+	ErrSeriesStopped = errors.New("Series has been stopped and cannot be changed")
+
+	// ErrTodoReadOnly is returned when a todo shared with the member to
+	// view is changed.
+	ErrTodoReadOnly = errors.New("Todo is shared with you to view only")
+
+	// ErrOwnerOnly is returned when a member changes something only the
+	// owner of a shared todo can change.
+	ErrOwnerOnly = errors.New("Only the owner of the todo can do this")
+
 	// ErrTodoNotFound is returned when a todo could not be found.
 	ErrTodoNotFound = dbtodos.ErrTodoNotFound
+
+	// ErrTagNotFound is returned when a tag could not be found.
+	ErrTagNotFound = dbtodos.ErrTagNotFound
+
+	// ErrAttachmentNotFound is returned when an attachment could not be
+	// found.
+	ErrAttachmentNotFound = dbattachments.ErrAttachmentNotFound
+
+	// ErrCommentNotFound is returned when a comment could not be found.
+	ErrCommentNotFound = dbcomments.ErrCommentNotFound
+
+	// ErrEventNotFound is returned when an event in the history of a
+	// todo could not be found.
+	ErrEventNotFound = dbevents.ErrEventNotFound
+
+	// ErrSeriesNotFound is returned when a series could not be found.
+	ErrSeriesNotFound = dbseries.ErrSeriesNotFound
+
+	// ErrWorkspaceNotFound is returned when a workspace could not be
+	// found, or the member does not belong to it.
+	ErrWorkspaceNotFound = dbworkspaces.ErrWorkspaceNotFound
 )
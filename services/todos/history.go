@@ -0,0 +1,283 @@
+package todos
+
+import (
+	"encoding/json"
+	"time"
+
+	"gotodo/database"
+	dbevents "gotodo/database/events"
+	dbtodos "gotodo/database/todos"
+	"gotodo/services/history"
+)
+
+// Event defines an event in the history of a todo.
+//
+// MemberID is the member who made the change, and Changes holds the
+// previous and new value of each field which changed, by field name.
+type Event struct {
+	ID       int                `json:"id"`
+	TodoID   int                `json:"todo_id"`
+	MemberID int                `json:"member_id"`
+	Action   string             `json:"action"`
+	Changes  map[string]*Change `json:"changes"`
+	Created  time.Time          `json:"created"`
+}
+
+// Change defines the change of a field of a todo, with both values encoded
+// as JSON.
+type Change dbevents.Change
+
+// History defines a set of events in the history of a todo.
+type History struct {
+	Events []*Event `json:"events"`
+	Total  int      `json:"total"`
+}
+
+// GetHistoryParams defines the parameters for the GetHistoryByTodoID method.
+type GetHistoryParams struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// GetHistoryByTodoID retrieves the history of a todo the given member can
+// see, as decided by get, oldest first.
+func (s *Service) GetHistoryByTodoID(tid, mid int, wid *int, params *GetHistoryParams) (*History, error) {
+	// Check the member can see this todo.
+	if _, _, err := s.get(tid, mid, wid); err != nil {
+		return nil, err
+	}
+
+	// Try to pull the events from the database.
+	dbes, err := s.db.Events.GetByTodoID(tid, &dbevents.GetParams{
+		Offset: params.Offset,
+		Limit:  params.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new History.
+	history := &History{
+		Events: []*Event{},
+		Total:  dbes.Total,
+	}
+
+	// Loop through the set of events.
+	for _, dbe := range dbes.Events {
+		history.Events = append(history.Events, newEvent(dbe))
+	}
+
+	return history, nil
+}
+
+// RestoreByIDAndMemberID restores a todo to the revision it was at right
+// after the event with the given ID.
+//
+// Only the owner of a todo can restore it. The list, parent and assignee of
+// the revision are only restored while they are still valid, otherwise the
+// current ones are kept. Restoring a todo is recorded in its history as
+// well, so it can be undone.
+func (s *Service) RestoreByIDAndMemberID(id, eid, mid int, wid *int) (*Todo, error) {
+	// Try to pull this todo from the database.
+	dbt, permission, err := s.get(id, mid, wid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the member owns this todo.
+	if permission == PermissionViewer {
+		return nil, ErrTodoReadOnly
+	} else if permission != PermissionOwner {
+		return nil, ErrOwnerOnly
+	}
+
+	// Try to pull this event from the database.
+	dbe, err := s.db.Events.GetByID(eid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the event belongs to this todo.
+	if dbe.TodoID != id {
+		return nil, ErrEventNotFound
+	}
+
+	// Try to pull the events up to this
+	// event from the database.
+	dbes, err := s.db.Events.GetByTodoID(id, &dbevents.GetParams{
+		MaxID: &eid,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Replay the events to get the fields of the
+	// todo at the time. Creating a todo only records
+	// the fields which were set, so every other
+	// field is reset to null.
+	revision := make(map[string]json.RawMessage)
+	for _, e := range dbes.Events {
+		if e.Action == dbevents.ActionCreated {
+			if revision, err = history.Fields(nil); err != nil {
+				return nil, err
+			}
+		}
+
+		for name, change := range e.Changes {
+			revision[name] = change.To
+		}
+	}
+
+	// Get the changes restoring the revision.
+	params, err := s.restoreParams(dbt, revision, mid, wid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update this todo in the database, recording
+	// the change in the same transaction.
+	before := dbt
+	if err := s.db.Transaction(func(tx *database.Database) error {
+		var err error
+		if dbt, err = tx.Todos.Update(id, params); err != nil {
+			return err
+		}
+
+		return s.with(tx).record(mid, dbevents.ActionRestored, before, dbt)
+	}); err != nil {
+		return nil, err
+	}
+
+	return newTodo(dbt, permission), nil
+}
+
+// restoreParams returns the update parameters restoring the given todo to
+// the given revision, leaving out the list, parent and assignee when they
+// are no longer valid.
+func (s *Service) restoreParams(dbt *dbtodos.Todo, revision map[string]json.RawMessage, mid int, wid *int) (*dbtodos.UpdateParams, error) {
+	params := &dbtodos.UpdateParams{}
+
+	// Handle detail field.
+	if v, ok := revision["detail"]; ok {
+		if err := json.Unmarshal(v, &params.Detail); err != nil {
+			return nil, err
+		}
+	}
+
+	// Handle completed field.
+	if v, ok := revision["completed"]; ok {
+		if err := json.Unmarshal(v, &params.Completed); err != nil {
+			return nil, err
+		}
+	}
+
+	// Handle due at field.
+	if v, ok := revision["due_at"]; ok {
+		if err := json.Unmarshal(v, &params.DueAt); err != nil {
+			return nil, err
+		}
+		params.ClearDueAt = params.DueAt == nil
+	}
+
+	// Handle remind at field.
+	if v, ok := revision["remind_at"]; ok {
+		if err := json.Unmarshal(v, &params.RemindAt); err != nil {
+			return nil, err
+		}
+		params.ClearRemindAt = params.RemindAt == nil
+	}
+
+	// Handle tags field. An empty set of tags
+	// is kept so the tags of the todo are removed.
+	if v, ok := revision["tags"]; ok {
+		params.Tags = []string{}
+		if err := json.Unmarshal(v, &params.Tags); err != nil {
+			return nil, err
+		}
+		if params.Tags == nil {
+			params.Tags = []string{}
+		}
+	}
+
+	// Handle list ID field.
+	if v, ok := revision["list_id"]; ok {
+		var lid *int
+		if err := json.Unmarshal(v, &lid); err != nil {
+			return nil, err
+		}
+
+		if lid == nil {
+			params.ClearListID = true
+		} else if oid, err := s.checkList(*lid, mid, wid); err == nil && oid == mid {
+			params.ListID = lid
+		} else if err != nil && err != ErrListInvalid {
+			return nil, err
+		}
+	}
+
+	// Handle parent ID field.
+	if v, ok := revision["parent_id"]; ok {
+		var pid *int
+		if err := json.Unmarshal(v, &pid); err != nil {
+			return nil, err
+		}
+
+		if pid == nil {
+			params.ClearParentID = true
+		} else if err := s.checkParent(dbt.ID, *pid, mid, wid); err == nil {
+			params.ParentID = pid
+		} else if err != ErrParentInvalid && err != ErrParentCycle {
+			return nil, err
+		}
+	}
+
+	// Handle assignee ID field.
+	if v, ok := revision["assignee_id"]; ok {
+		var aid *int
+		if err := json.Unmarshal(v, &aid); err != nil {
+			return nil, err
+		}
+
+		if aid == nil {
+			params.ClearAssigneeID = true
+		} else if _, _, err := s.get(dbt.ID, *aid, wid); err == nil {
+			params.AssigneeID = aid
+		} else if err != ErrTodoNotFound {
+			return nil, err
+		}
+	}
+
+	return params, nil
+}
+
+// with returns a copy of the service using the given database, such as a
+// transaction.
+func (s *Service) with(db *database.Database) *Service {
+	return &Service{
+		db:    db,
+		blobs: s.blobs,
+	}
+}
+
+// record records an event in the history of a todo, which was changed by the
+// given member from before to after, as history.Record does.
+func (s *Service) record(mid int, action string, before, after *dbtodos.Todo) error {
+	return history.Record(s.db, mid, action, before, after)
+}
+
+// newEvent returns a new Event from the given database event.
+func newEvent(dbe *dbevents.Event) *Event {
+	event := &Event{
+		ID:       dbe.ID,
+		TodoID:   dbe.TodoID,
+		MemberID: dbe.MemberID,
+		Action:   dbe.Action,
+		Changes:  make(map[string]*Change, len(dbe.Changes)),
+		Created:  dbe.Created,
+	}
+	for name, change := range dbe.Changes {
+		event.Changes[name] = (*Change)(change)
+	}
+
+	return event
+}
@@ -0,0 +1,256 @@
+package todos
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// FreqDaily repeats a todo every day.
+	FreqDaily = "DAILY"
+
+	// FreqWeekly repeats a todo every week.
+	FreqWeekly = "WEEKLY"
+
+	// FreqMonthly repeats a todo every month.
+	FreqMonthly = "MONTHLY"
+)
+
+// maxRuleDays is the number of days searched for the next occurrence of a
+// rule per interval, which covers the longest gap between two occurrences
+// of any supported rule.
+const maxRuleDays = 2 * 366
+
+// weekdays maps the RFC 5545 weekday names to their time.Weekday.
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Rule defines a recurrence rule, a subset of the RFC 5545 RRULE.
+//
+// Rules are written as a list of NAME=VALUE parts separated by semicolons,
+// e.g. FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10. The supported parts are:
+//
+//	FREQ      DAILY, WEEKLY or MONTHLY, required.
+//	INTERVAL  How many days, weeks or months apart occurrences are.
+//	BYDAY     Comma separated weekdays occurrences fall on, e.g. MO,WE.
+//	UNTIL     The date after which there are no more occurrences.
+//	COUNT     The total number of occurrences.
+//
+// Monthly rules without BYDAY repeat on the day of the month of the first
+// occurrence, skipping months that do not have that day.
+type Rule struct {
+	Freq     string
+	Interval int
+	ByDay    []time.Weekday
+	Until    *time.Time
+	Count    int
+}
+
+// ParseRule parses the given recurrence rule.
+func ParseRule(s string) (*Rule, error) {
+	// Create a new Rule.
+	rule := &Rule{
+		Interval: 1,
+	}
+
+	// Loop through the rule parts.
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	for _, part := range strings.Split(s, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, ErrRecurrenceInvalid
+		}
+		name, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		switch name {
+		case "FREQ":
+			if value != FreqDaily && value != FreqWeekly && value != FreqMonthly {
+				return nil, ErrRecurrenceInvalid
+			}
+			rule.Freq = value
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, ErrRecurrenceInvalid
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return nil, ErrRecurrenceInvalid
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, ErrRecurrenceInvalid
+			}
+			rule.Until = &until
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return nil, ErrRecurrenceInvalid
+			}
+			rule.Count = count
+		default:
+			return nil, ErrRecurrenceInvalid
+		}
+	}
+
+	// Check the frequency was given, and that
+	// UNTIL and COUNT are not used together.
+	if rule.Freq == "" || (rule.Until != nil && rule.Count > 0) {
+		return nil, ErrRecurrenceInvalid
+	}
+
+	return rule, nil
+}
+
+// parseUntil parses an UNTIL value, which is either a UTC date and time such
+// as 20261231T235959Z, a floating date and time treated as UTC, or a date.
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	// A date includes the whole day.
+	t, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+// Next returns the first occurrence of the rule after the given time, for a
+// series whose first occurrence was at start. It returns false once the rule
+// has no more occurrences, where occurrences is the number of occurrences so
+// far.
+func (r *Rule) Next(start, after time.Time, occurrences int) (time.Time, bool) {
+	// Check the count.
+	if r.Count > 0 && occurrences >= r.Count {
+		return time.Time{}, false
+	}
+
+	// Check every following day at the time of day
+	// of the first occurrence.
+	after = after.In(start.Location())
+	for i := 1; i <= maxRuleDays*r.Interval; i++ {
+		next := time.Date(after.Year(), after.Month(), after.Day()+i, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+		if !next.After(after) || !r.matches(start, next) {
+			continue
+		}
+
+		// Check the until date.
+		if r.Until != nil && next.After(*r.Until) {
+			return time.Time{}, false
+		}
+
+		return next, true
+	}
+
+	return time.Time{}, false
+}
+
+// matches returns whether the given day is an occurrence of the rule, for a
+// series whose first occurrence was at start.
+func (r *Rule) matches(start, day time.Time) bool {
+	// Check the weekday.
+	if len(r.ByDay) > 0 {
+		var found bool
+		for _, weekday := range r.ByDay {
+			if day.Weekday() == weekday {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	// Check the interval.
+	switch r.Freq {
+	case FreqDaily:
+		return daysBetween(start, day)%r.Interval == 0
+	case FreqWeekly:
+		if len(r.ByDay) == 0 && day.Weekday() != start.Weekday() {
+			return false
+		}
+
+		// Weeks start on Monday, as they do by default
+		// with RFC 5545.
+		monday := func(t time.Time) time.Time {
+			return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+		}
+		return (daysBetween(monday(start), monday(day))/7)%r.Interval == 0
+	case FreqMonthly:
+		if len(r.ByDay) == 0 && day.Day() != start.Day() {
+			return false
+		}
+
+		months := (day.Year()-start.Year())*12 + int(day.Month()) - int(start.Month())
+		return months%r.Interval == 0
+	}
+
+	return false
+}
+
+// daysBetween returns the number of calendar days from a to b.
+func daysBetween(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+// String returns the rule in the format accepted by ParseRule.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		var days []string
+		for _, weekday := range r.ByDay {
+			for name, wd := range weekdays {
+				if wd == weekday {
+					days = append(days, name)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// formatRule returns the given recurrence rule as formatted by the String
+// method of Rule, so rules are stored the same way however they were
+// written. The rule must have been checked already.
+func formatRule(s string) *string {
+	rule, err := ParseRule(s)
+	if err != nil {
+		return &s
+	}
+
+	formatted := rule.String()
+	return &formatted
+}
@@ -0,0 +1,257 @@
+package todos
+
+import (
+	"time"
+
+	dbevents "gotodo/database/events"
+	dbseries "gotodo/database/series"
+	dbtodos "gotodo/database/todos"
+	"gotodo/services/errors"
+)
+
+// Series defines a recurring todo series.
+type Series dbseries.Series
+
+// SeriesSet defines a set of recurring todo series.
+type SeriesSet struct {
+	Series []*Series `json:"series"`
+	Total  int       `json:"total"`
+}
+
+// GetSeriesByMemberID retrieves the series of a given member.
+func (s *Service) GetSeriesByMemberID(mid int) (*SeriesSet, error) {
+	// Try to pull the series from the database.
+	dbss, err := s.db.Series.GetByMemberID(mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new SeriesSet.
+	set := &SeriesSet{
+		Series: []*Series{},
+		Total:  dbss.Total,
+	}
+
+	// Loop through the set of series.
+	for _, dbs := range dbss.Series {
+		// Create a new Series.
+		series := &Series{
+			ID:          dbs.ID,
+			MemberID:    dbs.MemberID,
+			Created:     dbs.Created,
+			Rule:        dbs.Rule,
+			StartsAt:    dbs.StartsAt,
+			Occurrences: dbs.Occurrences,
+			LatestID:    dbs.LatestID,
+			StoppedAt:   dbs.StoppedAt,
+		}
+
+		// Add to series set.
+		set.Series = append(set.Series, series)
+	}
+
+	return set, nil
+}
+
+// GetSeriesByIDAndMemberID retrieves a series by its ID and member ID.
+func (s *Service) GetSeriesByIDAndMemberID(id, mid int) (*Series, error) {
+	// Try to pull this series from the database.
+	dbs, err := s.db.Series.GetByIDAndMemberID(id, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Series.
+	series := &Series{
+		ID:          dbs.ID,
+		MemberID:    dbs.MemberID,
+		Created:     dbs.Created,
+		Rule:        dbs.Rule,
+		StartsAt:    dbs.StartsAt,
+		Occurrences: dbs.Occurrences,
+		LatestID:    dbs.LatestID,
+		StoppedAt:   dbs.StoppedAt,
+	}
+
+	return series, nil
+}
+
+// UpdateSeriesParams defines the parameters for the
+// UpdateSeriesByIDAndMemberID method.
+type UpdateSeriesParams struct {
+	Rule *string `json:"rule"`
+}
+
+// UpdateSeriesByIDAndMemberID changes the rule of a series, which is used
+// from the next occurrence on.
+func (s *Service) UpdateSeriesByIDAndMemberID(id, mid int, params *UpdateSeriesParams) (*Series, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check rule.
+	if params.Rule != nil {
+		if _, err := ParseRule(*params.Rule); err != nil {
+			pes.Add(errors.NewParamError("rule", err))
+		}
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Try to pull this series from the database.
+	dbs, err := s.db.Series.GetByIDAndMemberID(id, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the series is not stopped.
+	if dbs.StoppedAt != nil {
+		pes.Add(errors.NewParamError("rule", ErrSeriesStopped))
+		return nil, pes
+	}
+
+	// Update this series in the database.
+	var rule *string
+	if params.Rule != nil {
+		rule = formatRule(*params.Rule)
+	}
+	if dbs, err = s.db.Series.Update(id, &dbseries.UpdateParams{
+		Rule: rule,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.GetSeriesByIDAndMemberID(dbs.ID, mid)
+}
+
+// StopSeriesByIDAndMemberID stops a series, so no more occurrences are
+// created. The existing occurrences are kept as they are.
+func (s *Service) StopSeriesByIDAndMemberID(id, mid int) (*Series, error) {
+	// Try to pull this series from the database.
+	dbs, err := s.db.Series.GetByIDAndMemberID(id, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stop this series in the database.
+	if dbs.StoppedAt == nil {
+		now := time.Now()
+		if _, err := s.db.Series.Update(id, &dbseries.UpdateParams{
+			StoppedAt: &now,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetSeriesByIDAndMemberID(id, mid)
+}
+
+// checkRecurrence checks the given recurrence rule can be used for a todo
+// with the given due date, which is where the series starts.
+func checkRecurrence(rule string, dueAt *time.Time) error {
+	if _, err := ParseRule(rule); err != nil {
+		return err
+	}
+
+	if dueAt == nil {
+		return ErrRecurrenceDueAtEmpty
+	}
+
+	return nil
+}
+
+// newSeries starts a new series with the given rule, with the given todo as
+// its first occurrence, returning the updated todo.
+func (s *Service) newSeries(todo *dbtodos.Todo, rule string) (*dbtodos.Todo, error) {
+	// Create this series in the database.
+	dbs, err := s.db.Series.New(todo.MemberID, &dbseries.NewParams{
+		Rule:     *formatRule(rule),
+		StartsAt: *todo.DueAt,
+		LatestID: todo.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Link the todo to this series.
+	return s.db.Todos.Update(todo.ID, &dbtodos.UpdateParams{
+		SeriesID: &dbs.ID,
+	})
+}
+
+// nextOccurrence creates the occurrence of a series following the given
+// todo, which has just been completed.
+//
+// Only the latest occurrence of a series creates the next one, so that
+// completing a todo again after reopening it does not create another one.
+// Once the rule of the series has no more occurrences, the series is
+// stopped. The next occurrence is recorded as created by the given member.
+func (s *Service) nextOccurrence(todo *dbtodos.Todo, mid int) error {
+	// Try to pull the series from the database.
+	dbs, err := s.db.Series.GetByID(*todo.SeriesID)
+	if err == dbseries.ErrSeriesNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// Check this todo is the latest occurrence
+	// of an active series.
+	if dbs.StoppedAt != nil || dbs.LatestID != todo.ID || todo.DueAt == nil {
+		return nil
+	}
+
+	// Parse the rule of the series.
+	rule, err := ParseRule(dbs.Rule)
+	if err != nil {
+		return err
+	}
+
+	// Get the due date of the next occurrence,
+	// stopping the series if there is none.
+	dueAt, ok := rule.Next(dbs.StartsAt, *todo.DueAt, dbs.Occurrences)
+	if !ok {
+		now := time.Now()
+		_, err := s.db.Series.Update(dbs.ID, &dbseries.UpdateParams{
+			StoppedAt: &now,
+		})
+		return err
+	}
+
+	// Keep the reminder the same amount
+	// of time before the due date.
+	var remindAt *time.Time
+	if todo.RemindAt != nil {
+		r := dueAt.Add(todo.RemindAt.Sub(*todo.DueAt))
+		remindAt = &r
+	}
+
+	// Create the next occurrence in the database.
+	next, err := s.db.Todos.New(todo.MemberID, &dbtodos.NewParams{
+		WorkspaceID: todo.WorkspaceID,
+		AssigneeID:  todo.AssigneeID,
+		ListID:      todo.ListID,
+		ParentID:    todo.ParentID,
+		SeriesID:    todo.SeriesID,
+		Detail:      todo.Detail,
+		DueAt:       &dueAt,
+		RemindAt:    remindAt,
+		Tags:        todo.Tags,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.record(mid, dbevents.ActionCreated, nil, next); err != nil {
+		return err
+	}
+
+	// Update the series in the database.
+	occurrences := dbs.Occurrences + 1
+	_, err = s.db.Series.Update(dbs.ID, &dbseries.UpdateParams{
+		Occurrences: &occurrences,
+		LatestID:    &next.ID,
+	})
+	return err
+}
@@ -0,0 +1,220 @@
+package todos
+
+import (
+	dblists "gotodo/database/lists"
+	dbshares "gotodo/database/shares"
+	dbtodos "gotodo/database/todos"
+)
+
+const (
+	// PermissionOwner is the permission of a member on their own todos.
+	PermissionOwner = "owner"
+
+	// PermissionEditor is the permission of a member on the todos shared
+	// with them to edit.
+	PermissionEditor = dbshares.PermissionEditor
+
+	// PermissionViewer is the permission of a member on the todos shared
+	// with them to view.
+	PermissionViewer = dbshares.PermissionViewer
+)
+
+// sharedItems defines the lists and todos shared with a member, along with
+// the permission of the member on each of them.
+type sharedItems struct {
+	lists map[int]string
+	todos map[int]string
+}
+
+// sharedWith retrieves the lists and todos shared with the given member,
+// only keeping the shares the member accepted. When an owner is given, only
+// the shares of that owner are kept.
+func (s *Service) sharedWith(mid int, oid *int) (*sharedItems, error) {
+	// Try to pull the shares from the database.
+	accepted := true
+	dbss, err := s.db.Shares.Get(&dbshares.GetParams{
+		OwnerID:  oid,
+		MemberID: &mid,
+		Accepted: &accepted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new sharedItems.
+	shared := &sharedItems{
+		lists: make(map[int]string),
+		todos: make(map[int]string),
+	}
+
+	// Keep the highest permission of each item.
+	for _, share := range dbss.Shares {
+		if share.ListID != nil {
+			shared.lists[*share.ListID] = highest(shared.lists[*share.ListID], share.Permission)
+		}
+		if share.TodoID != nil {
+			shared.todos[*share.TodoID] = highest(shared.todos[*share.TodoID], share.Permission)
+		}
+	}
+
+	return shared, nil
+}
+
+// listIDs returns the IDs of the shared lists.
+func (si *sharedItems) listIDs() []int {
+	ids := []int{}
+	for id := range si.lists {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// todoIDs returns the IDs of the shared todos.
+func (si *sharedItems) todoIDs() []int {
+	ids := []int{}
+	for id := range si.todos {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// permission returns the permission given on the todo by either sharing it
+// or sharing its list, or an empty string if neither is shared.
+func (si *sharedItems) permission(t *dbtodos.Todo) string {
+	permission := si.todos[t.ID]
+	if t.ListID != nil {
+		permission = highest(permission, si.lists[*t.ListID])
+	}
+
+	return permission
+}
+
+// get retrieves a todo of the given workspace, or of the personal space of
+// the member when nil, along with the permission of the member on it.
+// ErrTodoNotFound is returned when the member has no access to the todo.
+//
+// This decides who can see and edit todos for every method of the service
+// taking a member and a workspace. Within a workspace, its members can access
+// every todo, with the permission given by workspacePermission. Within their
+// personal space, members can access the todos they own, which were shared
+// with them or which are assigned to them. Sharing or assigning a todo gives
+// access to its subtasks as well, so the ancestors of the todo are checked
+// too. Assigning a todo only lets the assignee view it, so they can edit it
+// only when it was shared with them to edit.
+func (s *Service) get(id, mid int, wid *int) (*dbtodos.Todo, string, error) {
+	// Try to pull this todo from the database.
+	dbt, err := s.db.Todos.GetByID(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Check the todo is in the workspace.
+	if !inWorkspace(dbt.WorkspaceID, wid) {
+		return nil, "", ErrTodoNotFound
+	}
+
+	// Get the permission of the member
+	// within the workspace.
+	if wid != nil {
+		role, err := s.workspaceRole(*wid, mid)
+		if err == ErrWorkspaceNotFound {
+			return nil, "", ErrTodoNotFound
+		} else if err != nil {
+			return nil, "", err
+		}
+
+		return dbt, workspacePermission(role, dbt.MemberID == mid), nil
+	}
+
+	// Check if the member owns this todo.
+	if dbt.MemberID == mid {
+		return dbt, PermissionOwner, nil
+	}
+
+	// Get the items the owner shared with the member.
+	shared, err := s.sharedWith(mid, &dbt.MemberID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Walk up the ancestors of the todo,
+	// keeping the highest permission.
+	var permission string
+	visited := make(map[int]bool)
+	for t := dbt; t != nil && !visited[t.ID]; {
+		visited[t.ID] = true
+		permission = highest(permission, shared.permission(t))
+		if assigned(t, mid) {
+			permission = highest(permission, PermissionViewer)
+		}
+
+		if t.ParentID == nil {
+			break
+		}
+		if t, err = s.db.Todos.GetByID(*t.ParentID); err != nil && err != dbtodos.ErrTodoNotFound {
+			return nil, "", err
+		}
+	}
+
+	// Check if the member has access.
+	if permission == "" {
+		return nil, "", ErrTodoNotFound
+	}
+
+	return dbt, permission, nil
+}
+
+// checkList checks the list with the given ID is in the given workspace,
+// or in the personal space of the member when nil, returning the ID of the
+// member todos added to the list belong to. ErrListInvalid is returned
+// otherwise.
+//
+// Within their personal space, the list must either belong to the member or
+// be shared with them to edit. Within a workspace, todos belong to the
+// member who adds them, who must belong to the workspace.
+func (s *Service) checkList(lid, mid int, wid *int) (int, error) {
+	// Try to pull this list from the database.
+	dbl, err := s.db.Lists.GetByID(lid)
+	if err == dblists.ErrListNotFound {
+		return 0, ErrListInvalid
+	} else if err != nil {
+		return 0, err
+	}
+
+	// Check the list is in the workspace.
+	if !inWorkspace(dbl.WorkspaceID, wid) {
+		return 0, ErrListInvalid
+	} else if wid != nil {
+		return mid, nil
+	}
+
+	// Check if the member owns this list.
+	if dbl.MemberID == mid {
+		return mid, nil
+	}
+
+	// Check if the list was shared with the member to edit.
+	shared, err := s.sharedWith(mid, &dbl.MemberID)
+	if err != nil {
+		return 0, err
+	}
+	if shared.lists[lid] != PermissionEditor {
+		return 0, ErrListInvalid
+	}
+
+	return dbl.MemberID, nil
+}
+
+// highest returns the highest of the two given share permissions, where an
+// empty string means no permission.
+func highest(a, b string) string {
+	if a == PermissionEditor || b == PermissionEditor {
+		return PermissionEditor
+	} else if a == PermissionViewer || b == PermissionViewer {
+		return PermissionViewer
+	}
+
+	return ""
+}
@@ -0,0 +1,211 @@
+package todos
+
+import (
+	"testing"
+	"time"
+
+	"gotodo/database"
+	dblists "gotodo/database/lists"
+	dbshares "gotodo/database/shares"
+	dbtodos "gotodo/database/todos"
+	dbworkspaces "gotodo/database/workspaces"
+	"gotodo/services/shares"
+)
+
+// newTestTodo creates a todo of the given member for the tests, failing the
+// test if it could not be created.
+func newTestTodo(t *testing.T, db *database.Database, mid int, params *dbtodos.NewParams) *dbtodos.Todo {
+	dbt, err := db.Todos.New(mid, params)
+	if err != nil {
+		t.Fatalf("Todos.New() error: %s", err)
+	}
+
+	return dbt
+}
+
+// newTestShare shares a todo or list of the owner with the given ID for the
+// tests, accepting the share unless it is pending.
+func newTestShare(t *testing.T, db *database.Database, oid int, params *dbshares.NewParams, pending bool) *dbshares.Share {
+	dbs, err := db.Shares.New(oid, params)
+	if err != nil {
+		t.Fatalf("Shares.New() error: %s", err)
+	}
+	if pending {
+		return dbs
+	}
+
+	now := time.Now()
+	if dbs, err = db.Shares.Update(dbs.ID, &dbshares.UpdateParams{AcceptedAt: &now}); err != nil {
+		t.Fatalf("Shares.Update() error: %s", err)
+	}
+
+	return dbs
+}
+
+// checkGet checks the permission get returns for the given member on a todo,
+// where an empty permission means the member has no access.
+func checkGet(t *testing.T, s *Service, id, mid int, wid *int, want string) {
+	t.Helper()
+
+	_, permission, err := s.get(id, mid, wid)
+	if want == "" {
+		if err != ErrTodoNotFound {
+			t.Errorf("get(%d, %d) returned %q, %v, want %v", id, mid, permission, err, ErrTodoNotFound)
+		}
+		return
+	}
+	if err != nil {
+		t.Errorf("get(%d, %d) error: %s", id, mid, err)
+	} else if permission != want {
+		t.Errorf("get(%d, %d) permission = %q, want %q", id, mid, permission, want)
+	}
+}
+
+func TestGetShared(t *testing.T) {
+	db := database.NewMemory()
+	s := New(db, nil)
+
+	// Member 1 owns a list and todos, some of
+	// which are shared with member 2.
+	list, err := db.Lists.New(1, &dblists.NewParams{Name: "List"})
+	if err != nil {
+		t.Fatalf("Lists.New() error: %s", err)
+	}
+	viewed := newTestTodo(t, db, 1, &dbtodos.NewParams{Detail: "viewed"})
+	subtask := newTestTodo(t, db, 1, &dbtodos.NewParams{Detail: "subtask", ParentID: &viewed.ID})
+	listed := newTestTodo(t, db, 1, &dbtodos.NewParams{Detail: "listed", ListID: &list.ID})
+	pending := newTestTodo(t, db, 1, &dbtodos.NewParams{Detail: "pending"})
+	private := newTestTodo(t, db, 1, &dbtodos.NewParams{Detail: "private"})
+	newTestShare(t, db, 1, &dbshares.NewParams{MemberID: 2, TodoID: &viewed.ID, Permission: PermissionViewer}, false)
+	newTestShare(t, db, 1, &dbshares.NewParams{MemberID: 2, ListID: &list.ID, Permission: PermissionEditor}, false)
+	newTestShare(t, db, 1, &dbshares.NewParams{MemberID: 2, TodoID: &pending.ID, Permission: PermissionEditor}, true)
+
+	checkGet(t, s, viewed.ID, 1, nil, PermissionOwner)
+	checkGet(t, s, viewed.ID, 2, nil, PermissionViewer)
+	checkGet(t, s, subtask.ID, 2, nil, PermissionViewer)
+	checkGet(t, s, listed.ID, 2, nil, PermissionEditor)
+	checkGet(t, s, pending.ID, 2, nil, "")
+	checkGet(t, s, private.ID, 2, nil, "")
+	checkGet(t, s, viewed.ID, 3, nil, "")
+}
+
+func TestGetShareRevoked(t *testing.T) {
+	db := database.NewMemory()
+	s := New(db, nil)
+
+	// Member 1 shares a todo with member 2
+	// to edit, and assigns it to them.
+	dbt := newTestTodo(t, db, 1, &dbtodos.NewParams{Detail: "shared"})
+	subtask := newTestTodo(t, db, 1, &dbtodos.NewParams{Detail: "subtask", ParentID: &dbt.ID})
+	dbs := newTestShare(t, db, 1, &dbshares.NewParams{MemberID: 2, TodoID: &dbt.ID, Permission: PermissionEditor}, false)
+	two := 2
+	if _, err := s.AssignByIDAndMemberID(dbt.ID, 1, nil, &AssignParams{AssigneeID: &two}); err != nil {
+		t.Fatalf("AssignByIDAndMemberID() error: %s", err)
+	}
+	checkGet(t, s, dbt.ID, 2, nil, PermissionEditor)
+
+	// Revoking the share removes every access,
+	// including the one given by the assignment.
+	if err := shares.New(db, nil).DeleteByIDAndMemberID(dbs.ID, 1); err != nil {
+		t.Fatalf("shares.DeleteByIDAndMemberID() error: %s", err)
+	}
+	checkGet(t, s, dbt.ID, 2, nil, "")
+	checkGet(t, s, subtask.ID, 2, nil, "")
+
+	after, err := db.Todos.GetByID(dbt.ID)
+	if err != nil {
+		t.Fatalf("Todos.GetByID() error: %s", err)
+	}
+	if after.AssigneeID != nil {
+		t.Errorf("AssigneeID = %d after revoking the share, want nil", *after.AssigneeID)
+	}
+}
+
+func TestGetAssigned(t *testing.T) {
+	db := database.NewMemory()
+	s := New(db, nil)
+
+	// Member 1 assigns todos to member 2, sharing
+	// some of them with them as well.
+	two := 2
+	assigned := newTestTodo(t, db, 1, &dbtodos.NewParams{Detail: "assigned", AssigneeID: &two})
+	subtask := newTestTodo(t, db, 1, &dbtodos.NewParams{Detail: "subtask", ParentID: &assigned.ID})
+	viewed := newTestTodo(t, db, 1, &dbtodos.NewParams{Detail: "viewed", AssigneeID: &two})
+	edited := newTestTodo(t, db, 1, &dbtodos.NewParams{Detail: "edited", AssigneeID: &two})
+	newTestShare(t, db, 1, &dbshares.NewParams{MemberID: 2, TodoID: &viewed.ID, Permission: PermissionViewer}, false)
+	newTestShare(t, db, 1, &dbshares.NewParams{MemberID: 2, TodoID: &edited.ID, Permission: PermissionEditor}, false)
+
+	// Assigning a todo only lets the
+	// assignee view it and its subtasks.
+	checkGet(t, s, assigned.ID, 2, nil, PermissionViewer)
+	checkGet(t, s, subtask.ID, 2, nil, PermissionViewer)
+	checkGet(t, s, viewed.ID, 2, nil, PermissionViewer)
+	checkGet(t, s, edited.ID, 2, nil, PermissionEditor)
+	checkGet(t, s, assigned.ID, 3, nil, "")
+
+	if err := s.CanEditByIDAndMemberID(assigned.ID, 2, nil); err != ErrTodoReadOnly {
+		t.Errorf("CanEditByIDAndMemberID() of an assigned todo returned %v, want %v", err, ErrTodoReadOnly)
+	}
+	detail := "changed"
+	if _, err := s.UpdateByIDAndMemberID(assigned.ID, 2, nil, &UpdateParams{Detail: &detail}); err != ErrTodoReadOnly {
+		t.Errorf("UpdateByIDAndMemberID() of an assigned todo returned %v, want %v", err, ErrTodoReadOnly)
+	}
+	if err := s.CanEditByIDAndMemberID(edited.ID, 2, nil); err != nil {
+		t.Errorf("CanEditByIDAndMemberID() of a todo shared to edit error: %s", err)
+	}
+
+	// The assignee can still unassign themselves.
+	if err := s.UnassignByIDAndMemberID(assigned.ID, 2, nil); err != nil {
+		t.Errorf("UnassignByIDAndMemberID() by the assignee error: %s", err)
+	}
+	checkGet(t, s, assigned.ID, 2, nil, "")
+}
+
+func TestGetWorkspace(t *testing.T) {
+	db := database.NewMemory()
+	s := New(db, nil)
+
+	// Create a workspace with a member of each role,
+	// where member 3 creates a todo.
+	dbw, err := db.Workspaces.New(&dbworkspaces.NewParams{Name: "Workspace"})
+	if err != nil {
+		t.Fatalf("Workspaces.New() error: %s", err)
+	}
+	roles := map[int]string{
+		1: dbworkspaces.RoleOwner,
+		2: dbworkspaces.RoleAdmin,
+		3: dbworkspaces.RoleMember,
+		4: dbworkspaces.RoleMember,
+	}
+	for mid, role := range roles {
+		if _, err := db.Workspaces.NewMember(dbw.ID, mid, role); err != nil {
+			t.Fatalf("Workspaces.NewMember() error: %s", err)
+		}
+	}
+	dbt := newTestTodo(t, db, 3, &dbtodos.NewParams{Detail: "workspace", WorkspaceID: &dbw.ID})
+	personal := newTestTodo(t, db, 3, &dbtodos.NewParams{Detail: "personal"})
+
+	tests := []struct {
+		mid  int
+		want string
+	}{
+		{1, PermissionOwner},
+		{2, PermissionOwner},
+		{3, PermissionOwner},
+		{4, PermissionEditor},
+		{5, ""},
+	}
+	for _, test := range tests {
+		checkGet(t, s, dbt.ID, test.mid, &dbw.ID, test.want)
+	}
+
+	// Todos are only found in their own space.
+	checkGet(t, s, dbt.ID, 3, nil, "")
+	checkGet(t, s, personal.ID, 3, &dbw.ID, "")
+
+	// Removing a member removes their access.
+	if err := db.Workspaces.DeleteMember(dbw.ID, 4); err != nil {
+		t.Fatalf("Workspaces.DeleteMember() error: %s", err)
+	}
+	checkGet(t, s, dbt.ID, 4, &dbw.ID, "")
+}
@@ -1,25 +1,62 @@
 package todos
 
 import (
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gotodo/blobstore"
 	"gotodo/database"
+	dbevents "gotodo/database/events"
+	dbseries "gotodo/database/series"
 	dbtodos "gotodo/database/todos"
 	"gotodo/services/errors"
+	"gotodo/services/history"
 )
 
+// maxTagLength is the maximum length of a tag name.
+const maxTagLength = 64
+
 // Service defines the todos service.
 type Service struct {
-	db *database.Database
+	db    *database.Database
+	blobs blobstore.BlobStore
 }
 
-// New returns a new todos service.
-func New(db *database.Database) *Service {
+// New returns a new todos service, keeping the bytes of attachments in the
+// given blob store.
+func New(db *database.Database, blobs blobstore.BlobStore) *Service {
 	return &Service{
-		db: db,
+		db:    db,
+		blobs: blobs,
 	}
 }
 
 // Todo defines a todo.
-type Todo dbtodos.Todo
+//
+// Permission is the permission of the member the todo was retrieved for,
+// which is PermissionOwner for their own todos, or the permission they were
+// given for todos shared with them. Within a workspace, it is
+// PermissionOwner for the todos they created or when they are an owner or
+// admin of the workspace, and PermissionEditor otherwise. Members can view
+// the todos assigned to them.
+type Todo struct {
+	ID          int        `json:"id"`
+	MemberID    int        `json:"member_id"`
+	WorkspaceID *int       `json:"workspace_id"`
+	AssigneeID  *int       `json:"assignee_id"`
+	ListID      *int       `json:"list_id"`
+	ParentID    *int       `json:"parent_id"`
+	SeriesID    *int       `json:"series_id"`
+	Created     time.Time  `json:"created"`
+	Detail      string     `json:"detail"`
+	Completed   bool       `json:"completed"`
+	DueAt       *time.Time `json:"due_at"`
+	RemindAt    *time.Time `json:"remind_at"`
+	Tags        []string   `json:"tags"`
+	Permission  string     `json:"permission"`
+}
 
 // Todos defines a set of todos.
 type Todos struct {
@@ -28,10 +65,33 @@ type Todos struct {
 }
 
 // NewParams defines the parameters for the New method.
-type NewParams dbtodos.NewParams
+//
+// When a Recurrence rule is given, the todo becomes the first occurrence of
+// a new series, starting at its due date.
+type NewParams struct {
+	ListID     *int       `json:"list_id"`
+	ParentID   *int       `json:"parent_id"`
+	Detail     string     `json:"detail"`
+	DueAt      *time.Time `json:"due_at"`
+	RemindAt   *time.Time `json:"remind_at"`
+	Tags       []string   `json:"tags"`
+	Recurrence *string    `json:"recurrence"`
+}
+
+// New creates a new todo within the given workspace, or within the personal
+// space of the member when nil.
+//
+// Todos added to a list or as a subtask of a todo shared with the member to
+// edit belong to the owner of that list or todo, so they are shared the same
+// way.
+func (s *Service) New(mid int, wid *int, params *NewParams) (*Todo, error) {
+	// Check the member belongs to the workspace.
+	if wid != nil {
+		if _, err := s.workspaceRole(*wid, mid); err != nil {
+			return nil, err
+		}
+	}
 
-// New creates a new todo.
-func (s *Service) New(mid int, params *NewParams) (*Todo, error) {
 	// Create a new ParamErrors.
 	pes := errors.NewParamErrors()
 
@@ -40,44 +100,184 @@ func (s *Service) New(mid int, params *NewParams) (*Todo, error) {
 		pes.Add(errors.NewParamError("detail", ErrDetailEmpty))
 	}
 
+	// Check list ID, which decides who
+	// the todo belongs to.
+	oid := mid
+	if params.ListID != nil {
+		if owner, err := s.checkList(*params.ListID, mid, wid); err == ErrListInvalid {
+			pes.Add(errors.NewParamError("list_id", err))
+		} else if err != nil {
+			return nil, err
+		} else {
+			oid = owner
+		}
+	} else if params.ParentID != nil && wid == nil {
+		// Use the owner of the parent instead
+		// when it was shared with the member.
+		if parent, permission, err := s.get(*params.ParentID, mid, nil); err == nil && permission != PermissionViewer {
+			oid = parent.MemberID
+		} else if err != nil && err != ErrTodoNotFound {
+			return nil, err
+		}
+	}
+
+	// Check parent ID.
+	if params.ParentID != nil {
+		if err := s.checkParent(0, *params.ParentID, oid, wid); err == ErrParentInvalid {
+			pes.Add(errors.NewParamError("parent_id", err))
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	// Check tags.
+	tags, err := normalizeTags(params.Tags)
+	if err != nil {
+		pes.Add(errors.NewParamError("tags", err))
+	}
+
+	// Check recurrence.
+	if params.Recurrence != nil {
+		if err := checkRecurrence(*params.Recurrence, params.DueAt); err != nil {
+			pes.Add(errors.NewParamError("recurrence", err))
+		}
+	}
+
 	// Return if there were parameter errors.
 	if pes.Length() > 0 {
 		return nil, pes
 	}
 
-	// Create this member in the database.
-	dbt, err := s.db.Todos.New(mid, &dbtodos.NewParams{
-		Detail: params.Detail,
-	})
-	if err != nil {
+	// Create this todo in the database, recording
+	// its creation in the same transaction.
+	var dbt *dbtodos.Todo
+	if err := s.db.Transaction(func(tx *database.Database) error {
+		ts := s.with(tx)
+
+		var err error
+		if dbt, err = tx.Todos.New(oid, &dbtodos.NewParams{
+			WorkspaceID: wid,
+			ListID:      params.ListID,
+			ParentID:    params.ParentID,
+			Detail:      params.Detail,
+			DueAt:       params.DueAt,
+			RemindAt:    params.RemindAt,
+			Tags:        tags,
+		}); err != nil {
+			return err
+		}
+
+		// Start a new series with this todo.
+		if params.Recurrence != nil {
+			if dbt, err = ts.newSeries(dbt, *params.Recurrence); err != nil {
+				return err
+			}
+		}
+
+		return ts.record(mid, dbevents.ActionCreated, nil, dbt)
+	}); err != nil {
 		return nil, err
 	}
 
-	// Create a new Todo.
-	todo := &Todo{
-		ID:        dbt.ID,
-		MemberID:  dbt.MemberID,
-		Created:   dbt.Created,
-		Detail:    dbt.Detail,
-		Completed: dbt.Completed,
+	// Get the permission of the member,
+	// who may have added it for the owner.
+	permission := PermissionOwner
+	if oid != mid {
+		permission = PermissionEditor
 	}
 
-	return todo, nil
+	return newTodo(dbt, permission), nil
 }
 
 // GetParams defines the parameters for the Get method.
-type GetParams dbtodos.GetParams
+//
+// When a WorkspaceID is given, every todo of the workspace is matched and
+// the MemberID must belong to the workspace. Otherwise only the todos of the
+// personal space of members are matched, and when a MemberID is given, the
+// todos shared with or assigned to the member are included along with their
+// own todos.
+//
+// AssigneeID matches the todos assigned to the member with that ID, while
+// Unassigned matches the todos which are not assigned to anyone.
+type GetParams struct {
+	ID          *int       `json:"id"`
+	MemberID    *int       `json:"member_id"`
+	WorkspaceID *int       `json:"workspace_id"`
+	AssigneeID  *int       `json:"assignee_id"`
+	Unassigned  bool       `json:"unassigned"`
+	ListID      *int       `json:"list_id"`
+	Inbox       bool       `json:"inbox"`
+	Created     *time.Time `json:"created"`
+	Completed   *bool      `json:"completed"`
+	DueBefore   *time.Time `json:"due_before"`
+	DueAfter    *time.Time `json:"due_after"`
+	Overdue     *bool      `json:"overdue"`
+	Tags        []string   `json:"tags"`
+	TagsAll     bool       `json:"tags_all"`
+	Offset      int        `json:"offset"`
+	Limit       int        `json:"limit"`
+}
 
 // Get gets a set of todos.
+//
+// Tag names which could never match a tag are rejected, rather than being
+// skipped, so a filter made only of them never matches every todo.
 func (s *Service) Get(params *GetParams) (*Todos, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check tags.
+	tags, err := normalizeTags(params.Tags)
+	if err != nil {
+		pes.Add(errors.NewParamError("tag", err))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	memberID := params.MemberID
+	shared := &sharedItems{}
+	var role string
+
+	// Get the role of the member within the workspace,
+	// or the items shared with them otherwise.
+	if params.WorkspaceID != nil {
+		memberID = nil
+		if params.MemberID != nil {
+			if role, err = s.workspaceRole(*params.WorkspaceID, *params.MemberID); err != nil {
+				return nil, err
+			}
+		}
+	} else if params.MemberID != nil {
+		if shared, err = s.sharedWith(*params.MemberID, nil); err != nil {
+			return nil, err
+		}
+	}
+
 	// Try to pull the todos from the database.
 	dbts, err := s.db.Todos.Get(&dbtodos.GetParams{
-		ID:        params.ID,
-		MemberID:  params.MemberID,
-		Created:   params.Created,
-		Completed: params.Completed,
-		Offset:    params.Offset,
-		Limit:     params.Limit,
+		ID:            params.ID,
+		MemberID:      memberID,
+		WorkspaceID:   params.WorkspaceID,
+		Personal:      params.WorkspaceID == nil,
+		SharedIDs:     shared.todoIDs(),
+		SharedListIDs: shared.listIDs(),
+		Assigned:      params.WorkspaceID == nil,
+		AssigneeID:    params.AssigneeID,
+		Unassigned:    params.Unassigned,
+		ListID:        params.ListID,
+		Inbox:         params.Inbox,
+		Created:       params.Created,
+		Completed:     params.Completed,
+		DueBefore:     params.DueBefore,
+		DueAfter:      params.DueAfter,
+		Overdue:       params.Overdue,
+		Tags:          tags,
+		TagsAll:       params.TagsAll,
+		Offset:        params.Offset,
+		Limit:         params.Limit,
 	})
 	if err != nil {
 		return nil, err
@@ -91,73 +291,813 @@ func (s *Service) Get(params *GetParams) (*Todos, error) {
 
 	// Loop through the set of todos.
 	for _, t := range dbts.Todos {
-		// Create a new Todo.
-		todo := &Todo{
-			ID:        t.ID,
-			MemberID:  t.MemberID,
-			Created:   t.Created,
-			Detail:    t.Detail,
-			Completed: t.Completed,
+		// Get the permission of the member.
+		permission := PermissionOwner
+		if params.MemberID != nil && params.WorkspaceID != nil {
+			permission = workspacePermission(role, t.MemberID == *params.MemberID)
+		} else if params.MemberID != nil && t.MemberID != *params.MemberID {
+			permission = shared.permission(t)
+			if assigned(t, *params.MemberID) {
+				permission = highest(permission, PermissionViewer)
+			}
 		}
 
 		// Add to todos set.
-		todos.Todos = append(todos.Todos, todo)
+		todos.Todos = append(todos.Todos, newTodo(t, permission))
 	}
 
 	return todos, nil
 }
 
-// GetByIDAndMemberID retrieves a todo by its ID and member ID.
-func (s *Service) GetByIDAndMemberID(id, mid int) (*Todo, error) {
+// GetByIDAndMemberID retrieves a todo the given member can see, as decided
+// by get.
+func (s *Service) GetByIDAndMemberID(id, mid int, wid *int) (*Todo, error) {
 	// Try to pull this todo from the database.
-	dbt, err := s.db.Todos.GetByIDAndMemberID(id, mid)
+	dbt, permission, err := s.get(id, mid, wid)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create a new Todo.
-	todo := &Todo{
-		ID:        dbt.ID,
-		MemberID:  dbt.MemberID,
-		Created:   dbt.Created,
-		Detail:    dbt.Detail,
-		Completed: dbt.Completed,
+	return newTodo(dbt, permission), nil
+}
+
+// CanEditByIDAndMemberID checks the given member can edit a todo, as decided
+// by get. ErrTodoReadOnly is returned when they can only view it.
+//
+// This lets callers check access before doing costly work, such as reading
+// an upload.
+func (s *Service) CanEditByIDAndMemberID(id, mid int, wid *int) error {
+	// Try to pull this todo from the database.
+	_, permission, err := s.get(id, mid, wid)
+	if err != nil {
+		return err
 	}
 
-	return todo, nil
+	// Check the member can edit this todo.
+	if permission == PermissionViewer {
+		return ErrTodoReadOnly
+	}
+
+	return nil
 }
 
 // UpdateParams defines the parameters for the update methods.
-type UpdateParams dbtodos.UpdateParams
+//
+// When CompleteDescendants is set and the todo is being completed, all of
+// its subtasks are completed as well, down to the last level.
+//
+// When a Recurrence rule is given, the todo becomes the first occurrence of
+// a new series, or the rule of its series is changed if it already is part
+// of one.
+type UpdateParams struct {
+	ListID              *int       `json:"list_id"`
+	ClearListID         bool       `json:"clear_list_id"`
+	ParentID            *int       `json:"parent_id"`
+	ClearParentID       bool       `json:"clear_parent_id"`
+	Created             *time.Time `json:"created"`
+	Detail              *string    `json:"detail"`
+	Completed           *bool      `json:"completed"`
+	CompleteDescendants bool       `json:"complete_descendants"`
+	DueAt               *time.Time `json:"due_at"`
+	ClearDueAt          bool       `json:"clear_due_at"`
+	RemindAt            *time.Time `json:"remind_at"`
+	ClearRemindAt       bool       `json:"clear_remind_at"`
+	Tags                []string   `json:"tags"`
+	Recurrence          *string    `json:"recurrence"`
+}
+
+// UpdateByIDAndMemberID updates a todo the given member can edit, as decided
+// by get.
+//
+// Only the owner of a todo can change its list, parent or recurrence.
+//
+// When an occurrence of a series is completed, the next occurrence of the
+// series is created.
+func (s *Service) UpdateByIDAndMemberID(id, mid int, wid *int, params *UpdateParams) (*Todo, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
 
-// UpdateByIDAndMemberID updates a todo.
-func (s *Service) UpdateByIDAndMemberID(id, mid int, params *UpdateParams) (*Todo, error) {
 	// Try to pull this todo from the database.
-	dbt, err := s.db.Todos.GetByIDAndMemberID(id, mid)
+	dbt, permission, err := s.get(id, mid, wid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the member can edit this todo.
+	if permission == PermissionViewer {
+		return nil, ErrTodoReadOnly
+	}
+	owner := permission == PermissionOwner
+
+	// Check list ID.
+	if params.ListID != nil || params.ClearListID {
+		if !owner {
+			pes.Add(errors.NewParamError("list_id", ErrOwnerOnly))
+		} else if params.ListID != nil {
+			if oid, err := s.checkList(*params.ListID, mid, wid); err == ErrListInvalid || (err == nil && oid != mid) {
+				pes.Add(errors.NewParamError("list_id", ErrListInvalid))
+			} else if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Check parent ID.
+	if params.ParentID != nil || params.ClearParentID {
+		if !owner {
+			pes.Add(errors.NewParamError("parent_id", ErrOwnerOnly))
+		} else if params.ParentID != nil {
+			if err := s.checkParent(id, *params.ParentID, mid, wid); err == ErrParentInvalid || err == ErrParentCycle {
+				pes.Add(errors.NewParamError("parent_id", err))
+			} else if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Check tags. An empty set of tags is kept
+	// as is, so the tags of the todo are removed.
+	var tags []string
+	if params.Tags != nil {
+		var err error
+		if tags, err = normalizeTags(params.Tags); err != nil {
+			pes.Add(errors.NewParamError("tags", err))
+		}
+	}
+
+	completed := dbt.Completed
+
+	// Check recurrence, using the due date
+	// the todo will have once updated.
+	if params.Recurrence != nil && !owner {
+		pes.Add(errors.NewParamError("recurrence", ErrOwnerOnly))
+	} else if params.Recurrence != nil {
+		dueAt := dbt.DueAt
+		if params.DueAt != nil {
+			dueAt = params.DueAt
+		} else if params.ClearDueAt {
+			dueAt = nil
+		}
+
+		if err := checkRecurrence(*params.Recurrence, dueAt); err != nil {
+			pes.Add(errors.NewParamError("recurrence", err))
+		}
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Update this todo in the database, recording
+	// the changes in the same transaction.
+	before := dbt
+	if err := s.db.Transaction(func(tx *database.Database) error {
+		ts := s.with(tx)
+
+		var err error
+		if dbt, err = tx.Todos.Update(id, &dbtodos.UpdateParams{
+			ListID:        params.ListID,
+			ClearListID:   params.ClearListID,
+			ParentID:      params.ParentID,
+			ClearParentID: params.ClearParentID,
+			Created:       params.Created,
+			Detail:        params.Detail,
+			Completed:     params.Completed,
+			DueAt:         params.DueAt,
+			ClearDueAt:    params.ClearDueAt,
+			RemindAt:      params.RemindAt,
+			ClearRemindAt: params.ClearRemindAt,
+			Tags:          tags,
+		}); err != nil {
+			return err
+		}
+
+		if err := ts.record(mid, dbevents.ActionUpdated, before, dbt); err != nil {
+			return err
+		}
+
+		// Complete the descendants of this todo.
+		if params.Completed != nil && *params.Completed && params.CompleteDescendants {
+			if err := ts.completeDescendants(id, mid, map[int]bool{id: true}); err != nil {
+				return err
+			}
+		}
+
+		// Start a new series with this todo, or
+		// change the rule of its series.
+		if params.Recurrence != nil {
+			if dbt.SeriesID == nil {
+				if dbt, err = ts.newSeries(dbt, *params.Recurrence); err != nil {
+					return err
+				}
+			} else if _, err := tx.Series.Update(*dbt.SeriesID, &dbseries.UpdateParams{
+				Rule: formatRule(*params.Recurrence),
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Create the next occurrence of the series
+		// if this todo was just completed.
+		if !completed && dbt.Completed && dbt.SeriesID != nil {
+			if err := ts.nextOccurrence(dbt, mid); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return newTodo(dbt, permission), nil
+}
+
+// completeDescendants completes every subtask of the todo with the given ID,
+// down to the last level, on behalf of the given member, creating the next
+// occurrence of the subtasks which recur. The IDs of the todos already
+// visited are kept so a corrupted tree can never loop forever.
+func (s *Service) completeDescendants(id, mid int, visited map[int]bool) error {
+	// Try to pull the subtasks from the database.
+	dbts, err := s.db.Todos.GetByParentID(id)
+	if err != nil {
+		return err
+	}
+
+	// Loop through the subtasks.
+	completed := true
+	for _, t := range dbts.Todos {
+		if visited[t.ID] {
+			continue
+		}
+		visited[t.ID] = true
+
+		// Complete this subtask.
+		if !t.Completed {
+			after, err := s.db.Todos.Update(t.ID, &dbtodos.UpdateParams{
+				Completed: &completed,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := s.record(mid, dbevents.ActionUpdated, t, after); err != nil {
+				return err
+			}
+
+			// Create the next occurrence of its series,
+			// as completing the todo itself does.
+			if after.SeriesID != nil {
+				if err := s.nextOccurrence(after, mid); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Complete its subtasks.
+		if err := s.completeDescendants(t.ID, mid, visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkParent checks the todo with the given parent ID is in the given
+// workspace, or belongs to the given member within their personal space when
+// nil, returning ErrParentInvalid if it does not.
+//
+// When moving an existing todo, its ID is given so ErrParentCycle can be
+// returned if the parent is the todo itself or one of its subtasks. New
+// todos use an ID of 0.
+func (s *Service) checkParent(id, pid, mid int, wid *int) error {
+	// Try to pull the parent from the database.
+	parent, err := s.db.Todos.GetByID(pid)
 	if err == dbtodos.ErrTodoNotFound {
-		return nil, ErrTodoNotFound
+		return ErrParentInvalid
 	} else if err != nil {
+		return err
+	}
+
+	// Check the parent is in the workspace.
+	if !inWorkspace(parent.WorkspaceID, wid) || (wid == nil && parent.MemberID != mid) {
+		return ErrParentInvalid
+	}
+
+	// Walk up the ancestors of the parent, making
+	// sure the todo is not one of them.
+	visited := make(map[int]bool)
+	for parent != nil && id != 0 {
+		if parent.ID == id || visited[parent.ID] {
+			return ErrParentCycle
+		}
+		visited[parent.ID] = true
+
+		if parent.ParentID == nil {
+			break
+		}
+		if parent, err = s.db.Todos.GetByID(*parent.ParentID); err != nil && err != dbtodos.ErrTodoNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Tree defines a todo along with its subtasks.
+type Tree struct {
+	Todo     *Todo   `json:"todo"`
+	Children []*Tree `json:"children"`
+}
+
+// GetTreeByIDAndMemberID retrieves a todo the given member can see, as
+// decided by get, along with all of its subtasks.
+func (s *Service) GetTreeByIDAndMemberID(id, mid int, wid *int) (*Tree, error) {
+	// Try to pull this todo from the database.
+	todo, err := s.GetByIDAndMemberID(id, mid, wid)
+	if err != nil {
 		return nil, err
 	}
 
-	// Update this todo in the database.
-	dbt, err = s.db.Todos.Update(id, &dbtodos.UpdateParams{
-		Created:   params.Created,
-		Detail:    params.Detail,
-		Completed: params.Completed,
-	})
+	// Get the role of the member within the workspace.
+	var role string
+	if wid != nil {
+		if role, err = s.workspaceRole(*wid, mid); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create a new Tree.
+	tree := &Tree{
+		Todo:     todo,
+		Children: []*Tree{},
+	}
+
+	// Add the subtasks, level by level.
+	visited := map[int]bool{id: true}
+	level := []*Tree{tree}
+	for len(level) > 0 {
+		var next []*Tree
+		for _, node := range level {
+			// Try to pull the subtasks from the database.
+			dbts, err := s.db.Todos.GetByParentID(node.Todo.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			// Loop through the subtasks.
+			for _, t := range dbts.Todos {
+				if visited[t.ID] {
+					continue
+				}
+				visited[t.ID] = true
+
+				// Get the permission of the member, which
+				// subtasks inherit from the shared todo.
+				permission := todo.Permission
+				if t.MemberID == mid {
+					permission = PermissionOwner
+				} else if wid != nil {
+					permission = workspacePermission(role, false)
+				}
+
+				// Create a new Tree.
+				child := &Tree{
+					Todo:     newTodo(t, permission),
+					Children: []*Tree{},
+				}
+
+				node.Children = append(node.Children, child)
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+
+	return tree, nil
+}
+
+// DeleteByIDAndMemberID deletes a todo of the given workspace, or of the
+// personal space of the member when nil, along with its shares, comments
+// and attachments, returning the number of todos deleted.
+//
+// Only the owner of a todo can delete it, ErrOwnerOnly is returned to the
+// members it was shared with.
+func (s *Service) DeleteByIDAndMemberID(id, mid int, wid *int) (int, error) {
+	// Check the member owns this todo.
+	dbt, permission, err := s.get(id, mid, wid)
+	if err != nil {
+		return 0, err
+	} else if permission != PermissionOwner {
+		return 0, ErrOwnerOnly
+	}
+
+	// Try to delete this todo from the database, recording
+	// its deletion in the same transaction.
+	var deleted int
+	if err := s.db.Transaction(func(tx *database.Database) error {
+		// Get the subtasks of this todo, which
+		// are kept as top level todos.
+		subtasks, err := history.GetSubtasks(tx, []int{id})
+		if err != nil {
+			return err
+		}
+
+		if deleted, err = tx.Todos.DeleteByIDAndMemberID(id, dbt.MemberID); err != nil {
+			return err
+		}
+
+		// Check if the todo was found.
+		if deleted == 0 {
+			return ErrTodoNotFound
+		}
+
+		// Delete the shares of this todo.
+		if err := tx.Shares.DeleteByTodoID(id); err != nil {
+			return err
+		}
+
+		// Delete the comments on this todo.
+		if err := tx.Comments.DeleteByTodoID(id); err != nil {
+			return err
+		}
+
+		// Record the deletion of this todo,
+		// and the update of its subtasks.
+		if err := s.with(tx).record(mid, dbevents.ActionDeleted, dbt, nil); err != nil {
+			return err
+		}
+		return history.RecordUpdated(tx, mid, subtasks)
+	}); err != nil {
+		return 0, err
+	}
+
+	// Delete the attachments on this todo, which
+	// are not part of the transaction as their
+	// files cannot be rolled back.
+	if err := s.deleteAttachments([]int{id}); err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// DeleteParams defines the parameters for the DeleteByMemberID method.
+type DeleteParams dbtodos.DeleteParams
+
+// DeleteByMemberID deletes the set of todos belonging to the given member
+// within the given workspace, or within their personal space when nil, that
+// match the given filters, along with their shares, comments and
+// attachments, returning the number of todos deleted.
+//
+// At least one filter must be given, so a malformed request can never
+// wipe out every todo of a member.
+func (s *Service) DeleteByMemberID(mid int, wid *int, params *DeleteParams) (int, error) {
+	// Check the member belongs to the workspace.
+	if wid != nil {
+		if _, err := s.workspaceRole(*wid, mid); err != nil {
+			return 0, err
+		}
+	}
+
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check filters.
+	if len(params.IDs) == 0 && params.ListID == nil && params.Created == nil && params.Completed == nil {
+		pes.Add(errors.NewParamError("ids", ErrDeleteFiltersEmpty))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return 0, pes
+	}
+
+	// Try to delete the todos from the database, recording
+	// their deletion in the same transaction.
+	var deleted int
+	var ids []int
+	if err := s.db.Transaction(func(tx *database.Database) error {
+		// Get the todos matching the filters.
+		var err error
+		ids, err = tx.Todos.GetIDsByMemberID(mid, &dbtodos.DeleteParams{
+			WorkspaceID: wid,
+			Personal:    wid == nil,
+			IDs:         params.IDs,
+			ListID:      params.ListID,
+			Created:     params.Created,
+			Completed:   params.Completed,
+		})
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		// Get these todos, and their subtasks which
+		// are kept as top level todos.
+		dbts, err := history.Get(tx, ids)
+		if err != nil {
+			return err
+		}
+		subtasks, err := history.GetSubtasks(tx, ids)
+		if err != nil {
+			return err
+		}
+
+		// Delete the shares of these todos,
+		// and the comments on them.
+		if err := tx.Shares.DeleteByTodoIDs(ids); err != nil {
+			return err
+		}
+		if err := tx.Comments.DeleteByTodoIDs(ids); err != nil {
+			return err
+		}
+
+		// Delete these todos.
+		deleted, err = tx.Todos.DeleteByMemberID(mid, &dbtodos.DeleteParams{
+			WorkspaceID: wid,
+			Personal:    wid == nil,
+			IDs:         ids,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Record the deletion of these todos,
+		// and the update of their subtasks.
+		if err := history.RecordDeleted(tx, mid, dbts); err != nil {
+			return err
+		}
+		return history.RecordUpdated(tx, mid, subtasks)
+	}); err != nil {
+		return 0, err
+	}
+
+	// Delete the attachments on these todos.
+	if err := s.deleteAttachments(ids); err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// Tag defines a tag.
+type Tag dbtodos.Tag
+
+// Tags defines a set of tags.
+type Tags struct {
+	Tags  []*Tag `json:"tags"`
+	Total int    `json:"total"`
+}
+
+// GetTagsByMemberID retrieves the tags of a given member.
+func (s *Service) GetTagsByMemberID(mid int) (*Tags, error) {
+	// Try to pull the tags from the database.
+	dbts, err := s.db.Todos.GetTagsByMemberID(mid)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create a new Todo.
-	todo := &Todo{
-		ID:        dbt.ID,
-		MemberID:  dbt.MemberID,
-		Created:   dbt.Created,
-		Detail:    dbt.Detail,
-		Completed: dbt.Completed,
+	// Create a new Tags.
+	tags := &Tags{
+		Tags:  []*Tag{},
+		Total: dbts.Total,
+	}
+
+	// Loop through the set of tags.
+	for _, t := range dbts.Tags {
+		// Create a new Tag.
+		tag := &Tag{
+			ID:       t.ID,
+			MemberID: t.MemberID,
+			Name:     t.Name,
+			Todos:    t.Todos,
+		}
+
+		// Add to tags set.
+		tags.Tags = append(tags.Tags, tag)
+	}
+
+	return tags, nil
+}
+
+// UpdateTagParams defines the parameters for the UpdateTagByIDAndMemberID
+// method.
+type UpdateTagParams dbtodos.UpdateTagParams
+
+// UpdateTagByIDAndMemberID renames a tag.
+//
+// A tag cannot be renamed to the name of another tag of the member, those
+// tags should be merged instead.
+func (s *Service) UpdateTagByIDAndMemberID(id, mid int, params *UpdateTagParams) (*Tag, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check name.
+	var name *string
+	if params.Name != nil {
+		n, err := normalizeTag(*params.Name)
+		if err != nil {
+			pes.Add(errors.NewParamError("name", err))
+		}
+		name = &n
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Try to pull this tag from the database.
+	if _, err := s.db.Todos.GetTagByIDAndMemberID(id, mid); err != nil {
+		return nil, err
+	}
+
+	// Check the name is not used by another tag.
+	if name != nil {
+		dbts, err := s.db.Todos.GetTagsByMemberID(mid)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range dbts.Tags {
+			if t.Name == *name && t.ID != id {
+				pes.Add(errors.NewParamError("name", ErrTagNameExists))
+				return nil, pes
+			}
+		}
+	}
+
+	// Update this tag in the database, recording the
+	// update of its todos in the same transaction.
+	var dbt *dbtodos.Tag
+	if err := s.updateTags([]int{id}, mid, func(tx *database.Database) error {
+		var err error
+		dbt, err = tx.Todos.UpdateTag(id, &dbtodos.UpdateTagParams{
+			Name: name,
+		})
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	// Create a new Tag.
+	tag := &Tag{
+		ID:       dbt.ID,
+		MemberID: dbt.MemberID,
+		Name:     dbt.Name,
+		Todos:    dbt.Todos,
+	}
+
+	return tag, nil
+}
+
+// MergeTagsParams defines the parameters for the MergeTagsByIDAndMemberID
+// method.
+type MergeTagsParams struct {
+	IDs []int `json:"ids"`
+}
+
+// MergeTagsByIDAndMemberID merges the tags with the given IDs into a tag,
+// moving their todos over to it and deleting them.
+func (s *Service) MergeTagsByIDAndMemberID(id, mid int, params *MergeTagsParams) (*Tag, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check IDs.
+	if len(params.IDs) == 0 {
+		pes.Add(errors.NewParamError("ids", ErrMergeTagsEmpty))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Try to pull this tag from the database.
+	if _, err := s.db.Todos.GetTagByIDAndMemberID(id, mid); err != nil {
+		return nil, err
+	}
+
+	// Make sure every merged tag belongs to the member.
+	for _, mergeID := range params.IDs {
+		_, err := s.db.Todos.GetTagByIDAndMemberID(mergeID, mid)
+		if err == dbtodos.ErrTagNotFound {
+			pes.Add(errors.NewParamError("ids", ErrMergeTagInvalid))
+			return nil, pes
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	// Merge the tags in the database, recording the
+	// update of their todos in the same transaction.
+	var dbt *dbtodos.Tag
+	if err := s.updateTags(params.IDs, mid, func(tx *database.Database) error {
+		var err error
+		dbt, err = tx.Todos.MergeTags(id, params.IDs)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	// Create a new Tag.
+	tag := &Tag{
+		ID:       dbt.ID,
+		MemberID: dbt.MemberID,
+		Name:     dbt.Name,
+		Todos:    dbt.Todos,
+	}
+
+	return tag, nil
+}
+
+// DeleteTagByIDAndMemberID deletes a tag, removing it from every todo.
+func (s *Service) DeleteTagByIDAndMemberID(id, mid int) error {
+	// Delete this tag from the database, recording the
+	// update of its todos in the same transaction.
+	return s.updateTags([]int{id}, mid, func(tx *database.Database) error {
+		return tx.Todos.DeleteTagByIDAndMemberID(id, mid)
+	})
+}
+
+// updateTags runs the given function, which changes the tags with the given
+// IDs, within a transaction, recording the update of every todo those tags
+// are set on by the given member.
+func (s *Service) updateTags(ids []int, mid int, fn func(tx *database.Database) error) error {
+	return s.db.Transaction(func(tx *database.Database) error {
+		// Get the todos these tags are set on.
+		tids, err := tx.Todos.GetIDsByTagIDs(ids)
+		if err != nil {
+			return err
+		}
+		dbts, err := history.Get(tx, tids)
+		if err != nil {
+			return err
+		}
+
+		// Change the tags.
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		return history.RecordUpdated(tx, mid, dbts)
+	})
+}
+
+// newTodo returns the service Todo of the given database todo, with the
+// given permission of the member it was retrieved for.
+func newTodo(t *dbtodos.Todo, permission string) *Todo {
+	return &Todo{
+		ID:          t.ID,
+		MemberID:    t.MemberID,
+		WorkspaceID: t.WorkspaceID,
+		AssigneeID:  t.AssigneeID,
+		ListID:      t.ListID,
+		ParentID:    t.ParentID,
+		SeriesID:    t.SeriesID,
+		Created:     t.Created,
+		Detail:      t.Detail,
+		Completed:   t.Completed,
+		DueAt:       t.DueAt,
+		RemindAt:    t.RemindAt,
+		Tags:        t.Tags,
+		Permission:  permission,
+	}
+}
+
+// normalizeTag returns the normalized form of a tag name, which is trimmed
+// and lower case so tags are matched regardless of how they were typed.
+func normalizeTag(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	// Check the name.
+	if name == "" {
+		return "", ErrTagEmpty
+	} else if utf8.RuneCountInString(name) > maxTagLength {
+		return "", ErrTagLength
+	}
+
+	return name, nil
+}
+
+// normalizeTags returns the normalized, sorted and unique set of the given
+// tag names.
+func normalizeTags(names []string) ([]string, error) {
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		tag, err := normalizeTag(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
 	}
+	sort.Strings(tags)
 
-	return todo, nil
+	return tags, nil
 }
@@ -0,0 +1,43 @@
+package todos
+
+import (
+	dbworkspaces "gotodo/database/workspaces"
+)
+
+// workspaceRole returns the role of the given member within the given
+// workspace, returning ErrWorkspaceNotFound if they do not belong to it.
+func (s *Service) workspaceRole(wid, mid int) (string, error) {
+	// Try to pull this member from the database.
+	dbm, err := s.db.Workspaces.GetMember(wid, mid)
+	if err == dbworkspaces.ErrMemberNotFound {
+		return "", ErrWorkspaceNotFound
+	} else if err != nil {
+		return "", err
+	}
+
+	return dbm.Role, nil
+}
+
+// workspacePermission returns the permission of a member with the given
+// role on a todo of their workspace, where created tells whether they
+// created the todo.
+//
+// Every member of a workspace can edit its todos, while its owners and
+// admins are treated as the owner of every todo.
+func workspacePermission(role string, created bool) string {
+	if created || role == dbworkspaces.RoleOwner || role == dbworkspaces.RoleAdmin {
+		return PermissionOwner
+	}
+
+	return PermissionEditor
+}
+
+// inWorkspace returns whether a todo or list with the given workspace ID is
+// within the given workspace, where nil is the personal space of members.
+func inWorkspace(id, wid *int) bool {
+	if id == nil || wid == nil {
+		return id == nil && wid == nil
+	}
+
+	return *id == *wid
+}
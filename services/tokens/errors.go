@@ -0,0 +1,17 @@
+package tokens
+
+import "errors"
+
+var (
+	// ErrRefreshTokenEmpty is returned when the refresh_token param is
+	// empty.
+	ErrRefreshTokenEmpty = errors.New("Refresh token parameter is empty")
+
+	// ErrInvalidToken is returned when a refresh token is unknown, expired
+	// or revoked.
+	ErrInvalidToken = errors.New("Refresh token is invalid")
+
+	// ErrTokenReused is returned when a refresh token which has already
+	// been rotated is used again. Every token of its family is revoked.
+	ErrTokenReused = errors.New("Refresh token has already been used, please log in again")
+)
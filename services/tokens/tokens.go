@@ -0,0 +1,160 @@
+package tokens
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"gotodo/database"
+	dbtokens "gotodo/database/tokens"
+	"gotodo/services/errors"
+	"gotodo/services/secrets"
+)
+
+const (
+	// tokenPrefix is prepended to every generated refresh token so they
+	// are easy to recognize, e.g. when scanning for leaked secrets.
+	tokenPrefix = "gtr_"
+
+	// tokenBytes is the number of random bytes in a refresh token.
+	tokenBytes = 32
+
+	// familyBytes is the number of random bytes in a token family ID.
+	familyBytes = 16
+)
+
+// Service defines the refresh tokens service.
+//
+// Refresh tokens are rotated, each one can be exchanged for a new access
+// token only once, which also returns the next refresh token of its family.
+// Using a refresh token twice means it was stolen, so the whole family is
+// revoked.
+type Service struct {
+	db *database.Database
+}
+
+// New returns a new refresh tokens service.
+func New(db *database.Database) *Service {
+	return &Service{
+		db: db,
+	}
+}
+
+// Token defines a refresh token.
+type Token dbtokens.Token
+
+// New creates a new refresh token for the given member, starting a new
+// family. The token expires after the given amount of time.
+//
+// The plain text token is returned alongside the stored token. It is never
+// persisted and cannot be retrieved again.
+func (s *Service) New(mid int, expiry time.Duration) (*Token, string, error) {
+	// Generate the family.
+	b := make([]byte, familyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+
+	return s.newToken(mid, hex.EncodeToString(b), expiry)
+}
+
+// RefreshParams defines the parameters for the Refresh method.
+type RefreshParams struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Refresh exchanges a refresh token for the next refresh token of its
+// family, which expires after the given amount of time.
+func (s *Service) Refresh(params *RefreshParams, expiry time.Duration) (*Token, string, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check refresh token.
+	if params.RefreshToken == "" {
+		pes.Add(errors.NewParamError("refresh_token", ErrRefreshTokenEmpty))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, "", pes
+	}
+
+	// Try to pull this token from the database.
+	dbt, err := s.db.Tokens.GetByHash(secrets.Hash(params.RefreshToken))
+	if err == dbtokens.ErrTokenNotFound {
+		return nil, "", ErrInvalidToken
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	// Check the token is still valid.
+	if dbt.RevokedAt != nil || !dbt.ExpiresAt.After(time.Now()) {
+		return nil, "", ErrInvalidToken
+	}
+
+	// Mark the token as used, revoking the
+	// family if it was used before.
+	if err := s.db.Tokens.MarkUsed(dbt.ID); err == dbtokens.ErrTokenUsed {
+		if err := s.db.Tokens.RevokeByFamily(dbt.Family); err != nil {
+			return nil, "", err
+		}
+		return nil, "", ErrTokenReused
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	return s.newToken(dbt.MemberID, dbt.Family, expiry)
+}
+
+// IsFamilyActive returns whether the given token family has not been
+// revoked or expired, which is used to check the access tokens issued for
+// it are still valid.
+func (s *Service) IsFamilyActive(family string) (bool, error) {
+	return s.db.Tokens.IsFamilyActive(family)
+}
+
+// RevokeByFamily revokes every refresh token of a family, logging out the
+// session it belongs to.
+func (s *Service) RevokeByFamily(family string) error {
+	return s.db.Tokens.RevokeByFamily(family)
+}
+
+// RevokeByMemberID revokes every refresh token of a given member, logging
+// them out everywhere.
+func (s *Service) RevokeByMemberID(mid int) error {
+	return s.db.Tokens.RevokeByMemberID(mid)
+}
+
+// newToken creates a new refresh token in the given family.
+func (s *Service) newToken(mid int, family string, expiry time.Duration) (*Token, string, error) {
+	// Generate the token.
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+	secret := tokenPrefix + hex.EncodeToString(b)
+
+	// Create this token in the database.
+	dbt, err := s.db.Tokens.New(mid, &dbtokens.NewParams{
+		Family:    family,
+		Hash:      secrets.Hash(secret),
+		ExpiresAt: time.Now().Add(expiry),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Create a new Token.
+	token := &Token{
+		ID:        dbt.ID,
+		MemberID:  dbt.MemberID,
+		Family:    dbt.Family,
+		Hash:      dbt.Hash,
+		Created:   dbt.Created,
+		ExpiresAt: dbt.ExpiresAt,
+		UsedAt:    dbt.UsedAt,
+		RevokedAt: dbt.RevokedAt,
+	}
+
+	return token, secret, nil
+}
@@ -0,0 +1,71 @@
+package tokens
+
+import (
+	"testing"
+	"time"
+
+	"gotodo/database"
+)
+
+func TestRefreshReused(t *testing.T) {
+	s := New(database.NewMemory())
+
+	// Start a family and rotate its first token.
+	_, first, err := s.New(1, time.Hour)
+	if err != nil {
+		t.Fatalf("New() error: %s", err)
+	}
+	token, second, err := s.Refresh(&RefreshParams{RefreshToken: first}, time.Hour)
+	if err != nil {
+		t.Fatalf("Refresh() error: %s", err)
+	}
+
+	// Using the first token again revokes the family.
+	if _, _, err := s.Refresh(&RefreshParams{RefreshToken: first}, time.Hour); err != ErrTokenReused {
+		t.Fatalf("Refresh() of a used token returned %v, want %v", err, ErrTokenReused)
+	}
+	if _, _, err := s.Refresh(&RefreshParams{RefreshToken: second}, time.Hour); err != ErrInvalidToken {
+		t.Errorf("Refresh() of the next token returned %v, want %v", err, ErrInvalidToken)
+	}
+	active, err := s.IsFamilyActive(token.Family)
+	if err != nil {
+		t.Fatalf("IsFamilyActive() error: %s", err)
+	}
+	if active {
+		t.Errorf("IsFamilyActive() = true after reuse, want false")
+	}
+}
+
+func TestRefreshOtherFamily(t *testing.T) {
+	s := New(database.NewMemory())
+
+	// Start two families of the same member.
+	_, first, err := s.New(1, time.Hour)
+	if err != nil {
+		t.Fatalf("New() error: %s", err)
+	}
+	other, secret, err := s.New(1, time.Hour)
+	if err != nil {
+		t.Fatalf("New() error: %s", err)
+	}
+
+	// Reuse a token of the first family.
+	if _, _, err := s.Refresh(&RefreshParams{RefreshToken: first}, time.Hour); err != nil {
+		t.Fatalf("Refresh() error: %s", err)
+	}
+	if _, _, err := s.Refresh(&RefreshParams{RefreshToken: first}, time.Hour); err != ErrTokenReused {
+		t.Fatalf("Refresh() of a used token returned %v, want %v", err, ErrTokenReused)
+	}
+
+	// The other session stays logged in.
+	active, err := s.IsFamilyActive(other.Family)
+	if err != nil {
+		t.Fatalf("IsFamilyActive() error: %s", err)
+	}
+	if !active {
+		t.Errorf("IsFamilyActive() of another family = false, want true")
+	}
+	if _, _, err := s.Refresh(&RefreshParams{RefreshToken: secret}, time.Hour); err != nil {
+		t.Errorf("Refresh() of another family error: %s", err)
+	}
+}
@@ -0,0 +1,56 @@
+package workspaces
+
+import (
+	"errors"
+
+	dbinvitations "gotodo/database/invitations"
+	dbworkspaces "gotodo/database/workspaces"
+)
+
+var (
+	// ErrNameEmpty is returned when the name param is empty.
+	ErrNameEmpty = errors.New("Name parameter is empty")
+
+	// ErrRoleInvalid is returned when the role param is not one of the
+	// workspace roles.
+	ErrRoleInvalid = errors.New("Role must be either owner, admin or member")
+
+	// ErrInvitationRoleInvalid is returned when the role param of an
+	// invitation is not one of the roles members can be invited with.
+	ErrInvitationRoleInvalid = errors.New("Role must be either admin or member")
+
+	// ErrEmailEmpty is returned when the email param is empty.
+	ErrEmailEmpty = errors.New("Email parameter is empty")
+
+	// ErrMemberExists is returned when someone who already belongs to the
+	// workspace is invited to it.
+	ErrMemberExists = errors.New("This member already belongs to the workspace")
+
+	// ErrInvitationExists is returned when someone who was invited to the
+	// workspace already is invited again.
+	ErrInvitationExists = errors.New("This email was invited to the workspace already")
+
+	// ErrAdminOnly is returned when a member does something only the
+	// owners and admins of the workspace can do.
+	ErrAdminOnly = errors.New("Only the owners and admins of the workspace can do this")
+
+	// ErrOwnerOnly is returned when a member does something only the
+	// owners of the workspace can do.
+	ErrOwnerOnly = errors.New("Only the owners of the workspace can do this")
+
+	// ErrLastOwner is returned when the last owner of a workspace would
+	// leave it or lose their role.
+	ErrLastOwner = errors.New("A workspace must keep at least one owner, make another member an owner first")
+
+	// ErrWorkspaceNotFound is returned when a workspace could not be
+	// found.
+	ErrWorkspaceNotFound = dbworkspaces.ErrWorkspaceNotFound
+
+	// ErrMemberNotFound is returned when a member of a workspace could
+	// not be found.
+	ErrMemberNotFound = dbworkspaces.ErrMemberNotFound
+
+	// ErrInvitationNotFound is returned when an invitation could not be
+	// found.
+	ErrInvitationNotFound = dbinvitations.ErrInvitationNotFound
+)
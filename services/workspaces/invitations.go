@@ -0,0 +1,344 @@
+package workspaces
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gotodo/database"
+	dbinvitations "gotodo/database/invitations"
+	dbmembers "gotodo/database/members"
+	dbworkspaces "gotodo/database/workspaces"
+	"gotodo/mailer"
+	"gotodo/services/errors"
+)
+
+// Invitation defines an invitation to a workspace.
+type Invitation struct {
+	ID            int       `json:"id"`
+	WorkspaceID   int       `json:"workspace_id"`
+	WorkspaceName string    `json:"workspace_name"`
+	InviterID     int       `json:"inviter_id"`
+	InviterEmail  string    `json:"inviter_email"`
+	Email         string    `json:"email"`
+	Role          string    `json:"role"`
+	Created       time.Time `json:"created"`
+}
+
+// Invitations defines a set of invitations.
+type Invitations struct {
+	Invitations []*Invitation `json:"invitations"`
+	Total       int           `json:"total"`
+}
+
+// InviteParams defines the parameters for the Invite method.
+type InviteParams struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+// Invite invites an email to join a workspace with the given role, which is
+// either admin or member. Only the owners and admins of the workspace can
+// invite.
+//
+// The email does not need to belong to a member yet, so people can sign up
+// after being invited. The invitation is deleted again when its email could
+// not be sent, so the email can be invited again.
+func (s *Service) Invite(wid, mid int, params *InviteParams) (*Invitation, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check email.
+	email := strings.TrimSpace(params.Email)
+	if email == "" {
+		pes.Add(errors.NewParamError("email", ErrEmailEmpty))
+	}
+
+	// Check role.
+	if params.Role != RoleAdmin && params.Role != RoleMember {
+		pes.Add(errors.NewParamError("role", ErrInvitationRoleInvalid))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Try to pull this workspace from the database.
+	dbw, role, err := s.get(wid, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the member can manage this workspace.
+	if role == RoleMember {
+		return nil, ErrAdminOnly
+	}
+
+	// Check the email does not belong to a member
+	// of the workspace already.
+	if dbm, err := s.db.Members.GetByEmail(email); err == nil {
+		email = dbm.Email
+		if _, err := s.db.Workspaces.GetMember(wid, dbm.ID); err == nil {
+			pes.Add(errors.NewParamError("email", ErrMemberExists))
+			return nil, pes
+		} else if err != dbworkspaces.ErrMemberNotFound {
+			return nil, err
+		}
+	} else if err != dbmembers.ErrMemberNotFound {
+		return nil, err
+	}
+
+	// Check the email was not invited already.
+	dbis, err := s.db.Invitations.Get(&dbinvitations.GetParams{
+		WorkspaceID: &wid,
+		Email:       &email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if dbis.Total > 0 {
+		pes.Add(errors.NewParamError("email", ErrInvitationExists))
+		return nil, pes
+	}
+
+	// Try to pull the inviter from the database.
+	inviter, err := s.db.Members.GetByID(mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create this invitation in the database.
+	dbi, err := s.db.Invitations.New(wid, &dbinvitations.NewParams{
+		InviterID: mid,
+		Email:     email,
+		Role:      params.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the invitation, deleting it
+	// again if it could not be sent.
+	if err := s.mailer.Send(&mailer.Message{
+		To:      email,
+		Subject: fmt.Sprintf("%s invited you to the %s workspace on Go Todo", inviter.Email, dbw.Name),
+		Body: fmt.Sprintf("%s invited you to join the %s workspace as %s %s.\n\nSign up or log in to Go Todo with this email to accept or decline invitation %d.",
+			inviter.Email, dbw.Name, article(dbi.Role), dbi.Role, dbi.ID),
+	}); err != nil {
+		if derr := s.db.Invitations.Delete(dbi.ID); derr != nil {
+			return nil, derr
+		}
+		return nil, err
+	}
+
+	return s.newInvitation(dbi)
+}
+
+// GetInvitations retrieves the pending invitations to a workspace. Only the
+// owners and admins of the workspace can see them.
+func (s *Service) GetInvitations(wid, mid int) (*Invitations, error) {
+	// Try to pull this workspace from the database.
+	_, role, err := s.get(wid, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the member can manage this workspace.
+	if role == RoleMember {
+		return nil, ErrAdminOnly
+	}
+
+	return s.getInvitations(&dbinvitations.GetParams{
+		WorkspaceID: &wid,
+	})
+}
+
+// GetInvitationsByMemberID retrieves the pending invitations sent to the
+// email of a given member.
+func (s *Service) GetInvitationsByMemberID(mid int) (*Invitations, error) {
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByID(mid)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getInvitations(&dbinvitations.GetParams{
+		Email: &dbm.Email,
+	})
+}
+
+// getInvitations retrieves the invitations matching the given database
+// parameters, skipping the invitations to workspaces that no longer exist.
+func (s *Service) getInvitations(params *dbinvitations.GetParams) (*Invitations, error) {
+	// Try to pull the invitations from the database.
+	dbis, err := s.db.Invitations.Get(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Invitations.
+	invitations := &Invitations{
+		Invitations: []*Invitation{},
+	}
+
+	// Loop through the set of invitations.
+	for _, dbi := range dbis.Invitations {
+		invitation, err := s.newInvitation(dbi)
+		if err == ErrInvitationNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		// Add to invitations set.
+		invitations.Invitations = append(invitations.Invitations, invitation)
+	}
+	invitations.Total = len(invitations.Invitations)
+
+	return invitations, nil
+}
+
+// AcceptInvitation accepts an invitation sent to the email of a given
+// member, adding them to the workspace with the role they were invited
+// with.
+func (s *Service) AcceptInvitation(id, mid int) (*Workspace, error) {
+	// Try to pull this invitation from the database.
+	dbi, err := s.getInvitation(id, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Try to pull this workspace from the database.
+	dbw, err := s.db.Workspaces.GetByID(dbi.WorkspaceID)
+	if err == dbworkspaces.ErrWorkspaceNotFound {
+		return nil, ErrInvitationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Add the member to this workspace, unless they
+	// joined it already, and delete the invitation.
+	role := dbi.Role
+	if err := s.db.Transaction(func(tx *database.Database) error {
+		if dbm, err := tx.Workspaces.GetMember(dbw.ID, mid); err == nil {
+			role = dbm.Role
+		} else if err != dbworkspaces.ErrMemberNotFound {
+			return err
+		} else if _, err := tx.Workspaces.NewMember(dbw.ID, mid, dbi.Role); err != nil {
+			return err
+		}
+
+		return tx.Invitations.Delete(dbi.ID)
+	}); err != nil {
+		return nil, err
+	}
+
+	return newWorkspace(dbw, role), nil
+}
+
+// DeclineInvitation declines an invitation sent to the email of a given
+// member, deleting it.
+func (s *Service) DeclineInvitation(id, mid int) error {
+	// Try to pull this invitation from the database.
+	dbi, err := s.getInvitation(id, mid)
+	if err != nil {
+		return err
+	}
+
+	// Delete this invitation from the database.
+	return s.db.Invitations.Delete(dbi.ID)
+}
+
+// DeleteInvitation revokes an invitation to a workspace. Only the owners and
+// admins of the workspace can revoke it.
+func (s *Service) DeleteInvitation(id, mid int) error {
+	// Try to pull this invitation from the database.
+	dbi, err := s.db.Invitations.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Try to pull this workspace from the database.
+	_, role, err := s.get(dbi.WorkspaceID, mid)
+	if err == ErrWorkspaceNotFound {
+		return ErrInvitationNotFound
+	} else if err != nil {
+		return err
+	}
+
+	// Check the member can manage this workspace.
+	if role == RoleMember {
+		return ErrAdminOnly
+	}
+
+	// Delete this invitation from the database.
+	return s.db.Invitations.Delete(id)
+}
+
+// getInvitation retrieves an invitation sent to the email of a given
+// member.
+func (s *Service) getInvitation(id, mid int) (*dbinvitations.Invitation, error) {
+	// Try to pull this invitation from the database.
+	dbi, err := s.db.Invitations.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Try to pull this member from the database.
+	dbm, err := s.db.Members.GetByID(mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check this invitation was sent to the member.
+	if dbi.Email != dbm.Email {
+		return nil, ErrInvitationNotFound
+	}
+
+	return dbi, nil
+}
+
+// newInvitation returns the service Invitation of the given database
+// invitation, returning ErrInvitationNotFound if its workspace no longer
+// exists.
+func (s *Service) newInvitation(dbi *dbinvitations.Invitation) (*Invitation, error) {
+	// Create a new Invitation.
+	invitation := &Invitation{
+		ID:          dbi.ID,
+		WorkspaceID: dbi.WorkspaceID,
+		InviterID:   dbi.InviterID,
+		Email:       dbi.Email,
+		Role:        dbi.Role,
+		Created:     dbi.Created,
+	}
+
+	// Handle the name of the workspace.
+	dbw, err := s.db.Workspaces.GetByID(dbi.WorkspaceID)
+	if err == dbworkspaces.ErrWorkspaceNotFound {
+		return nil, ErrInvitationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	invitation.WorkspaceName = dbw.Name
+
+	// Handle the email of the inviter, who
+	// may have deleted their account since.
+	inviter, err := s.db.Members.GetByID(dbi.InviterID)
+	if err != nil && err != dbmembers.ErrMemberNotFound {
+		return nil, err
+	} else if err == nil {
+		invitation.InviterEmail = inviter.Email
+	}
+
+	return invitation, nil
+}
+
+// article returns the indefinite article to use before the given role.
+func article(role string) string {
+	if role == RoleAdmin {
+		return "an"
+	}
+
+	return "a"
+}
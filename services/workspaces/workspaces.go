@@ -0,0 +1,517 @@
+package workspaces
+
+import (
+	"strings"
+	"time"
+
+	"gotodo/blobstore"
+	"gotodo/database"
+	dbmembers "gotodo/database/members"
+	dbworkspaces "gotodo/database/workspaces"
+	"gotodo/mailer"
+	"gotodo/services/attachments"
+	"gotodo/services/errors"
+	"gotodo/services/history"
+)
+
+const (
+	// RoleOwner is the role of the members who own a workspace. Owners
+	// can do anything within the workspace, including deleting it.
+	RoleOwner = dbworkspaces.RoleOwner
+
+	// RoleAdmin is the role of the members who manage the members and
+	// invitations of a workspace.
+	RoleAdmin = dbworkspaces.RoleAdmin
+
+	// RoleMember is the role of the members who work on the lists and
+	// todos of a workspace.
+	RoleMember = dbworkspaces.RoleMember
+)
+
+// Service defines the workspaces service.
+type Service struct {
+	db     *database.Database
+	mailer mailer.Mailer
+	blobs  blobstore.BlobStore
+}
+
+// New returns a new workspaces service, sending invitations using the given
+// mailer and deleting the bytes of the attachments on the todos of deleted
+// workspaces from the given blob store.
+func New(db *database.Database, m mailer.Mailer, blobs blobstore.BlobStore) *Service {
+	return &Service{
+		db:     db,
+		mailer: m,
+		blobs:  blobs,
+	}
+}
+
+// Workspace defines a workspace.
+//
+// Role is the role of the member the workspace was retrieved for.
+type Workspace struct {
+	ID      int       `json:"id"`
+	Name    string    `json:"name"`
+	Role    string    `json:"role"`
+	Created time.Time `json:"created"`
+}
+
+// Workspaces defines a set of workspaces.
+type Workspaces struct {
+	Workspaces []*Workspace `json:"workspaces"`
+	Total      int          `json:"total"`
+}
+
+// NewParams defines the parameters for the New method.
+type NewParams dbworkspaces.NewParams
+
+// New creates a new workspace, owned by the given member.
+func (s *Service) New(mid int, params *NewParams) (*Workspace, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check name.
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		pes.Add(errors.NewParamError("name", ErrNameEmpty))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Create this workspace in the database,
+	// along with its owner.
+	var dbw *dbworkspaces.Workspace
+	if err := s.db.Transaction(func(tx *database.Database) error {
+		var err error
+		if dbw, err = tx.Workspaces.New(&dbworkspaces.NewParams{
+			Name: name,
+		}); err != nil {
+			return err
+		}
+
+		_, err = tx.Workspaces.NewMember(dbw.ID, mid, RoleOwner)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return newWorkspace(dbw, RoleOwner), nil
+}
+
+// GetByMemberID retrieves the workspaces a given member belongs to.
+func (s *Service) GetByMemberID(mid int) (*Workspaces, error) {
+	// Try to pull the workspaces from the database.
+	dbws, err := s.db.Workspaces.GetByMemberID(mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Workspaces.
+	workspaces := &Workspaces{
+		Workspaces: []*Workspace{},
+		Total:      dbws.Total,
+	}
+
+	// Loop through the set of workspaces.
+	for _, w := range dbws.Workspaces {
+		// Get the role of the member.
+		dbm, err := s.db.Workspaces.GetMember(w.ID, mid)
+		if err != nil {
+			return nil, err
+		}
+
+		// Add to workspaces set.
+		workspaces.Workspaces = append(workspaces.Workspaces, newWorkspace(w, dbm.Role))
+	}
+
+	return workspaces, nil
+}
+
+// GetByIDAndMemberID retrieves a workspace by its ID, which the given
+// member must belong to.
+func (s *Service) GetByIDAndMemberID(id, mid int) (*Workspace, error) {
+	// Try to pull this workspace from the database.
+	dbw, role, err := s.get(id, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWorkspace(dbw, role), nil
+}
+
+// UpdateParams defines the parameters for the UpdateByIDAndMemberID method.
+type UpdateParams dbworkspaces.UpdateParams
+
+// UpdateByIDAndMemberID renames a workspace. Only its owners and admins can
+// rename it.
+func (s *Service) UpdateByIDAndMemberID(id, mid int, params *UpdateParams) (*Workspace, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check name.
+	var name *string
+	if params.Name != nil {
+		n := strings.TrimSpace(*params.Name)
+		if n == "" {
+			pes.Add(errors.NewParamError("name", ErrNameEmpty))
+		}
+		name = &n
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Try to pull this workspace from the database.
+	_, role, err := s.get(id, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the member can manage this workspace.
+	if role == RoleMember {
+		return nil, ErrAdminOnly
+	}
+
+	// Update this workspace in the database.
+	dbw, err := s.db.Workspaces.Update(id, &dbworkspaces.UpdateParams{
+		Name: name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newWorkspace(dbw, role), nil
+}
+
+// DeleteByIDAndMemberID deletes a workspace along with its lists, its todos
+// and their shares, comments and attachments, its members and invitations,
+// clearing the changes in the history of its todos. Only its owners can
+// delete it.
+func (s *Service) DeleteByIDAndMemberID(id, mid int) error {
+	// Try to pull this workspace from the database.
+	_, role, err := s.get(id, mid)
+	if err != nil {
+		return err
+	}
+
+	// Check the member owns this workspace.
+	if role != RoleOwner {
+		return ErrOwnerOnly
+	}
+
+	// Delete this workspace from the database,
+	// along with everything in it.
+	var ids []int
+	if err := s.db.Transaction(func(tx *database.Database) error {
+		ids, err = Delete(tx, id)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	// Delete the attachments on its todos, which
+	// are not part of the transaction as their
+	// files cannot be rolled back.
+	return attachments.DeleteByTodoIDs(s.db, s.blobs, ids)
+}
+
+// Delete deletes the workspace with the given ID along with its lists, its
+// todos and their shares and comments, its members and invitations, clearing
+// the changes in the history of its todos, using the given database, such
+// as a transaction. The IDs of the deleted todos are returned, so the
+// attachments on them can be deleted once the transaction is committed.
+//
+// It is used by DeleteByIDAndMemberID, and by the members service to delete
+// the workspaces of purged accounts.
+func Delete(db *database.Database, id int) ([]int, error) {
+	ids, err := db.Todos.GetIDsByWorkspaceID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Todos.DeleteByWorkspaceID(id); err != nil {
+		return nil, err
+	}
+	if err := db.Shares.DeleteByTodoIDs(ids); err != nil {
+		return nil, err
+	}
+	if err := db.Comments.DeleteByTodoIDs(ids); err != nil {
+		return nil, err
+	}
+	if err := db.Events.ClearChangesByTodoIDs(ids); err != nil {
+		return nil, err
+	}
+	if err := db.Lists.DeleteByWorkspaceID(id); err != nil {
+		return nil, err
+	}
+	if err := db.Invitations.DeleteByWorkspaceID(id); err != nil {
+		return nil, err
+	}
+
+	if err := db.Workspaces.Delete(id); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Member defines a member of a workspace.
+type Member struct {
+	MemberID    int       `json:"member_id"`
+	WorkspaceID int       `json:"workspace_id"`
+	Email       string    `json:"email"`
+	DisplayName string    `json:"display_name"`
+	Role        string    `json:"role"`
+	Created     time.Time `json:"created"`
+}
+
+// Members defines a set of members of a workspace.
+type Members struct {
+	Members []*Member `json:"members"`
+	Total   int       `json:"total"`
+}
+
+// GetMembers retrieves the members of a workspace the given member belongs
+// to.
+func (s *Service) GetMembers(wid, mid int) (*Members, error) {
+	// Try to pull this workspace from the database.
+	if _, _, err := s.get(wid, mid); err != nil {
+		return nil, err
+	}
+
+	// Try to pull the members from the database.
+	dbms, err := s.db.Workspaces.GetMembers(wid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new Members.
+	members := &Members{
+		Members: []*Member{},
+	}
+
+	// Loop through the set of members.
+	for _, dbm := range dbms.Members {
+		member, err := s.newMember(dbm)
+		if err == ErrMemberNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		// Add to members set.
+		members.Members = append(members.Members, member)
+	}
+	members.Total = len(members.Members)
+
+	return members, nil
+}
+
+// UpdateMemberParams defines the parameters for the UpdateMember method.
+type UpdateMemberParams struct {
+	Role *string `json:"role"`
+}
+
+// UpdateMember changes the role of a member of a workspace.
+//
+// Owners and admins can change the role of the other members, but only
+// owners can change the role of an owner or make someone an owner. The last
+// owner of a workspace cannot lose their role.
+func (s *Service) UpdateMember(wid, mid, target int, params *UpdateMemberParams) (*Member, error) {
+	// Create a new ParamErrors.
+	pes := errors.NewParamErrors()
+
+	// Check role.
+	if params.Role != nil && !validRole(*params.Role) {
+		pes.Add(errors.NewParamError("role", ErrRoleInvalid))
+	}
+
+	// Return if there were parameter errors.
+	if pes.Length() > 0 {
+		return nil, pes
+	}
+
+	// Try to pull this workspace from the database.
+	_, role, err := s.get(wid, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the member can manage this workspace.
+	if role == RoleMember {
+		return nil, ErrAdminOnly
+	}
+
+	// Try to pull the target member from the database.
+	dbm, err := s.db.Workspaces.GetMember(wid, target)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if there is anything to change.
+	if params.Role == nil || *params.Role == dbm.Role {
+		return s.newMember(dbm)
+	}
+
+	// Check only owners change owners.
+	if (dbm.Role == RoleOwner || *params.Role == RoleOwner) && role != RoleOwner {
+		return nil, ErrOwnerOnly
+	}
+
+	// Check the workspace keeps an owner.
+	if dbm.Role == RoleOwner {
+		if err := s.checkOwners(wid); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update this member in the database.
+	if dbm, err = s.db.Workspaces.UpdateMember(wid, target, *params.Role); err != nil {
+		return nil, err
+	}
+
+	return s.newMember(dbm)
+}
+
+// DeleteMember removes a member from a workspace, either by one of its
+// owners or admins, or by the member themselves to leave it.
+//
+// Only owners can remove another owner, and the last owner of a workspace
+// cannot leave it. The lists and todos the member created are kept in the
+// workspace, while the todos assigned to them are unassigned.
+func (s *Service) DeleteMember(wid, mid, target int) error {
+	// Try to pull this workspace from the database.
+	_, role, err := s.get(wid, mid)
+	if err != nil {
+		return err
+	}
+
+	// Try to pull the target member from the database.
+	dbm, err := s.db.Workspaces.GetMember(wid, target)
+	if err != nil {
+		return err
+	}
+
+	// Check the member can remove the target.
+	if target != mid {
+		if role == RoleMember {
+			return ErrAdminOnly
+		} else if dbm.Role == RoleOwner && role != RoleOwner {
+			return ErrOwnerOnly
+		}
+	}
+
+	// Check the workspace keeps an owner.
+	if dbm.Role == RoleOwner {
+		if err := s.checkOwners(wid); err != nil {
+			return err
+		}
+	}
+
+	// Delete this member from the database, along
+	// with their assignments within the workspace,
+	// recording the update of the unassigned todos.
+	return s.db.Transaction(func(tx *database.Database) error {
+		ids, err := tx.Todos.GetIDsByAssigneeID(target, &wid)
+		if err != nil {
+			return err
+		}
+		dbts, err := history.Get(tx, ids)
+		if err != nil {
+			return err
+		}
+		if err := tx.Todos.UnassignByMemberID(target, &wid); err != nil {
+			return err
+		}
+		if err := history.RecordUpdated(tx, mid, dbts); err != nil {
+			return err
+		}
+
+		return tx.Workspaces.DeleteMember(wid, target)
+	})
+}
+
+// get retrieves a workspace the given member belongs to, along with the
+// role of the member. ErrWorkspaceNotFound is returned when the member does
+// not belong to it.
+func (s *Service) get(id, mid int) (*dbworkspaces.Workspace, string, error) {
+	// Try to pull this member from the database.
+	dbm, err := s.db.Workspaces.GetMember(id, mid)
+	if err == dbworkspaces.ErrMemberNotFound {
+		return nil, "", ErrWorkspaceNotFound
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	// Try to pull this workspace from the database.
+	dbw, err := s.db.Workspaces.GetByID(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return dbw, dbm.Role, nil
+}
+
+// checkOwners checks the workspace with the given ID has more than one
+// owner, returning ErrLastOwner if it does not.
+func (s *Service) checkOwners(wid int) error {
+	// Try to pull the members from the database.
+	dbms, err := s.db.Workspaces.GetMembers(wid)
+	if err != nil {
+		return err
+	}
+
+	// Count the owners.
+	var owners int
+	for _, dbm := range dbms.Members {
+		if dbm.Role == RoleOwner {
+			owners++
+		}
+	}
+	if owners < 2 {
+		return ErrLastOwner
+	}
+
+	return nil
+}
+
+// newMember returns the service Member of the given database member,
+// returning ErrMemberNotFound if their account no longer exists.
+func (s *Service) newMember(dbm *dbworkspaces.Member) (*Member, error) {
+	// Try to pull the account of this member from the database.
+	account, err := s.db.Members.GetByID(dbm.MemberID)
+	if err == dbmembers.ErrMemberNotFound {
+		return nil, ErrMemberNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &Member{
+		MemberID:    dbm.MemberID,
+		WorkspaceID: dbm.WorkspaceID,
+		Email:       account.Email,
+		DisplayName: account.DisplayName,
+		Role:        dbm.Role,
+		Created:     dbm.Created,
+	}, nil
+}
+
+// newWorkspace returns the service Workspace of the given database
+// workspace, with the given role of the member it was retrieved for.
+func newWorkspace(w *dbworkspaces.Workspace, role string) *Workspace {
+	return &Workspace{
+		ID:      w.ID,
+		Name:    w.Name,
+		Role:    role,
+		Created: w.Created,
+	}
+}
+
+// validRole returns whether the given role is one of the workspace roles.
+func validRole(role string) bool {
+	return role == RoleOwner || role == RoleAdmin || role == RoleMember
+}
@@ -0,0 +1,20 @@
+package throttle
+
+import "time"
+
+// Error is returned when a login attempt has to wait. Locked is set when
+// the account is locked out, rather than backed off or limited by client
+// IP.
+type Error struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	if e.Locked {
+		return "Account is locked due to too many failed login attempts, please try again later"
+	}
+
+	return "Too many failed login attempts, please try again later"
+}
@@ -0,0 +1,104 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the Memory store removes the failures that
+// expired.
+const sweepInterval = time.Minute
+
+// entry defines the failures of a key in the Memory store.
+type entry struct {
+	times   []time.Time
+	expires time.Time
+}
+
+// Memory defines the store of the failed login attempts backed by memory.
+//
+// It is safe for concurrent use, but is not shared between processes, so
+// each API server counts its own failures.
+type Memory struct {
+	mu        sync.Mutex
+	entries   map[string]*entry
+	lastSweep time.Time
+}
+
+// NewMemory creates a new in-memory store.
+func NewMemory() *Memory {
+	return &Memory{
+		entries:   make(map[string]*entry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Failures retrieves the times of the failures of a key since the given
+// time, oldest first.
+func (m *Memory) Failures(key string, since time.Time) ([]time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, nil
+	}
+
+	times := []time.Time{}
+	for _, t := range e.times {
+		if !t.Before(since) {
+			times = append(times, t)
+		}
+	}
+
+	return times, nil
+}
+
+// AddFailure records a failure of a key at the given time, which is kept
+// for at least the given time to live.
+func (m *Memory) AddFailure(key string, at time.Time, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Remove the expired failures of every
+	// key once in a while.
+	if at.Sub(m.lastSweep) >= sweepInterval {
+		for k, e := range m.entries {
+			if at.After(e.expires) {
+				delete(m.entries, k)
+			}
+		}
+		m.lastSweep = at
+	}
+
+	e, ok := m.entries[key]
+	if !ok {
+		e = &entry{}
+		m.entries[key] = e
+	}
+
+	// Drop the failures of this key that
+	// expired, then add the new one.
+	times := e.times[:0]
+	for _, t := range e.times {
+		if !t.Before(at.Add(-ttl)) {
+			times = append(times, t)
+		}
+	}
+	e.times = append(times, at)
+
+	if expires := at.Add(ttl); expires.After(e.expires) {
+		e.expires = expires
+	}
+
+	return nil
+}
+
+// Reset removes the failures of a key.
+func (m *Memory) Reset(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
@@ -0,0 +1,181 @@
+// Package throttle slows down brute-force attacks on logins, by counting
+// the failed login attempts per account and per client IP.
+//
+// Each failure of an account doubles the time before it can be tried again,
+// and the account is locked out for a while after too many failures. Client
+// IPs are limited to a number of failures within a sliding window. The
+// failures are kept in a Store, implemented by Memory, which is the default.
+package throttle
+
+import (
+	"strings"
+	"time"
+)
+
+// Store defines the store of the failed login attempts.
+type Store interface {
+	// Failures retrieves the times of the failures of a key since the
+	// given time, oldest first.
+	Failures(key string, since time.Time) ([]time.Time, error)
+
+	// AddFailure records a failure of a key at the given time, which is
+	// kept for at least the given time to live.
+	AddFailure(key string, at time.Time, ttl time.Duration) error
+
+	// Reset removes the failures of a key.
+	Reset(key string) error
+}
+
+// Limits defines the limits of a Throttle.
+//
+// After each failure, an account has to wait BackoffBase before being tried
+// again, doubled for every failure before it, up to BackoffMax. The account
+// is locked out after LockoutThreshold failures, until LockoutTime has
+// passed since the last one. The failures of an account are remembered for
+// LockoutTime, so accounts are not throttled if it is zero.
+//
+// Client IPs are limited to IPLimit failures within IPWindow.
+//
+// Setting BackoffBase, LockoutThreshold or IPLimit to zero or less turns off
+// the backoff, the lockout or the client IP limit.
+type Limits struct {
+	BackoffBase      time.Duration
+	BackoffMax       time.Duration
+	LockoutThreshold int
+	LockoutTime      time.Duration
+	IPLimit          int
+	IPWindow         time.Duration
+}
+
+// Throttle defines the login throttle.
+type Throttle struct {
+	store  Store
+	limits Limits
+}
+
+// New returns a new login throttle, keeping the failures in the given
+// store.
+func New(store Store, limits *Limits) *Throttle {
+	return &Throttle{
+		store:  store,
+		limits: *limits,
+	}
+}
+
+// Check checks whether a login attempt to the given account from the given
+// client IP can be made now. If not, an *Error is returned with the time
+// left to wait.
+//
+// The client IP is not checked if it is empty.
+func (t *Throttle) Check(account, ip string) error {
+	now := time.Now()
+	e := &Error{}
+
+	// Check the account.
+	if t.limits.LockoutTime > 0 {
+		times, err := t.store.Failures(accountKey(account), now.Add(-t.limits.LockoutTime))
+		if err != nil {
+			return err
+		}
+
+		if n := len(times); n > 0 {
+			last := times[n-1]
+			if t.limits.LockoutThreshold > 0 && n >= t.limits.LockoutThreshold {
+				e.Locked = true
+				e.RetryAfter = last.Add(t.limits.LockoutTime).Sub(now)
+			} else {
+				e.RetryAfter = last.Add(t.backoff(n)).Sub(now)
+			}
+		}
+	}
+
+	// Check the client IP.
+	if t.checkIP(ip) {
+		times, err := t.store.Failures(ipKey(ip), now.Add(-t.limits.IPWindow))
+		if err != nil {
+			return err
+		}
+
+		// Wait until enough failures leave the window.
+		if n := len(times); n >= t.limits.IPLimit {
+			wait := times[n-t.limits.IPLimit].Add(t.limits.IPWindow).Sub(now)
+			if wait > e.RetryAfter {
+				e.RetryAfter = wait
+			}
+		}
+	}
+
+	if e.RetryAfter > 0 {
+		return e
+	}
+
+	return nil
+}
+
+// Fail records a failed login attempt to the given account from the given
+// client IP.
+func (t *Throttle) Fail(account, ip string) error {
+	now := time.Now()
+
+	// Record the failure of the account.
+	if t.limits.LockoutTime > 0 {
+		if err := t.store.AddFailure(accountKey(account), now, t.limits.LockoutTime); err != nil {
+			return err
+		}
+	}
+
+	// Record the failure of the client IP.
+	if t.checkIP(ip) {
+		if err := t.store.AddFailure(ipKey(ip), now, t.limits.IPWindow); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Succeed records a successful login to the given account, which forgets
+// its failures.
+//
+// The failures of the client IP are kept, so logging in to an account of
+// their own does not let attackers try more passwords on others.
+func (t *Throttle) Succeed(account string) error {
+	if t.limits.LockoutTime <= 0 {
+		return nil
+	}
+
+	return t.store.Reset(accountKey(account))
+}
+
+// backoff returns how long an account has to wait after the given number of
+// failures.
+func (t *Throttle) backoff(n int) time.Duration {
+	if t.limits.BackoffBase <= 0 {
+		return 0
+	}
+
+	d := t.limits.BackoffBase
+	for i := 1; i < n && d < t.limits.BackoffMax; i++ {
+		d *= 2
+	}
+	if t.limits.BackoffMax > 0 && d > t.limits.BackoffMax {
+		d = t.limits.BackoffMax
+	}
+
+	return d
+}
+
+// checkIP returns whether the given client IP is limited.
+func (t *Throttle) checkIP(ip string) bool {
+	return ip != "" && t.limits.IPLimit > 0 && t.limits.IPWindow > 0
+}
+
+// accountKey returns the store key of an account, given by its email.
+func accountKey(account string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(account))
+}
+
+// ipKey returns the store key of a client IP.
+func ipKey(ip string) string {
+	return "ip:" + ip
+}